@@ -15,12 +15,19 @@ import (
 	"github.com/gin-gonic/gin"
 	redisClient "github.com/go-redis/redis/v8"
 
+	"github.com/otp-auth/internal/application/dto"
 	"github.com/otp-auth/internal/application/usecases"
 	"github.com/otp-auth/internal/config"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/infrastructure/http/middleware"
 	"github.com/otp-auth/internal/infrastructure/http/router"
+	"github.com/otp-auth/internal/infrastructure/persistence/filesystem"
+	"github.com/otp-auth/internal/infrastructure/persistence/memory"
 	"github.com/otp-auth/internal/infrastructure/persistence/postgres"
 	"github.com/otp-auth/internal/infrastructure/persistence/redis"
+	"github.com/otp-auth/internal/infrastructure/ratelimit"
 	infraServices "github.com/otp-auth/internal/infrastructure/services"
+	"github.com/otp-auth/pkg/errors"
 )
 
 func main() {
@@ -73,10 +80,30 @@ func main() {
 
 	// Initialize repositories
 	userRepo, otpRepo, tokenRepo, rateLimiter := initializeRepositories(db, redisConn)
+	unitOfWork := postgres.NewUnitOfWork(db)
+	deviceRepo := postgres.NewDeviceRepository(db)
+	clientRepo, clientRegistry := initializeClients(cfg, db)
+
+	// Keep limits enforced per instance while Redis is unavailable
+	if cfg.Security.RateLimit.FallbackEnabled {
+		rateLimiter = ratelimit.NewFailoverRateLimiter(rateLimiter, ratelimit.NewLocalRateLimiter(), cfg.Security.RateLimit.FallbackProbeInterval)
+	}
 
 	// Initialize services
 	otpSender, jwtService, hashService := initializeServices(cfg)
 
+	keySetProvider, ok := jwtService.(services.KeySetProvider)
+	if !ok {
+		log.Fatalf("JWT service does not expose a key set")
+	}
+
+	// Load the signing key ring and keep rotating it in the background
+	rotationCtx, stopRotation := context.WithCancel(context.Background())
+	defer stopRotation()
+	if rotateKeysUseCase := initializeKeyRing(cfg, db, jwtService); rotateKeysUseCase != nil {
+		go runKeyRotation(rotationCtx, rotateKeysUseCase, cfg.JWT.KeyRing.CheckInterval)
+	}
+
 	// Initialize use cases
 	sendOTPUseCase := usecases.NewSendOTPUseCase(
 		userRepo, otpRepo, rateLimiter,
@@ -86,50 +113,228 @@ func main() {
 		cfg.Security.RateLimit.OTPLimit,
 	)
 
+	sendOTPChallengeUseCase := initializeChallenge(cfg, redisConn, rateLimiter)
+
+	tokenClaims := tokenClaimsConfig(cfg, clientRepo)
+	sessions := sessionPolicies(cfg)
+
+	revokeLinkRepo := redis.NewRevokeLinkRepository(redisConn)
+	notifyNewLoginUseCase := initializeNewLoginNotifications(cfg, revokeLinkRepo, rateLimiter, hashService)
+
+	accessTokenDenylist := redis.NewAccessTokenDenylist(redisConn)
+
 	loginUseCase := usecases.NewLoginUseCase(
-		userRepo, otpRepo, tokenRepo,
+		userRepo, otpRepo, tokenRepo, deviceRepo, unitOfWork, accessTokenDenylist,
 		jwtService, hashService,
 		cfg.JWT.AccessTokenTTL,
 		cfg.JWT.RefreshTokenTTL,
+		tokenClaims,
+		sessions,
+		usecases.SessionLimit{
+			MaxPerScope: cfg.JWT.SessionLimit.MaxPerScope,
+			OnExceeded:  cfg.JWT.SessionLimit.OnExceeded,
+		},
+		notifyNewLoginUseCase,
+	)
+
+	securityEvents := infraServices.NewLogSecurityEventPublisher()
+
+	revokeSessionByLinkUseCase := usecases.NewRevokeSessionByLinkUseCase(
+		revokeLinkRepo, tokenRepo, accessTokenDenylist,
+		hashService,
+		securityEvents,
+		cfg.JWT.AccessTokenTTL,
 	)
 
 	refreshUseCase := usecases.NewRefreshUseCase(
-		userRepo, tokenRepo,
+		userRepo, tokenRepo, deviceRepo, unitOfWork, accessTokenDenylist,
 		jwtService, hashService,
 		cfg.JWT.AccessTokenTTL,
 		cfg.JWT.RefreshTokenTTL,
+		tokenClaims,
+		sessions,
+		securityEvents,
+		cfg.JWT.RefreshReuseGrace,
 	)
 
+	// Protected routes only accept our own tokens minted for this API
+	tokenVerifyOptions := []services.VerifyOption{services.WithIssuer(cfg.JWT.Issuer)}
+	if cfg.JWT.Audience != "" {
+		tokenVerifyOptions = append(tokenVerifyOptions, services.WithAudience(cfg.JWT.Audience))
+	}
+
 	logoutUseCase := usecases.NewLogoutUseCase(
 		tokenRepo,
+		accessTokenDenylist,
+		jwtService,
 		hashService,
 	)
 
+	listUserSessionsUseCase := usecases.NewListUserSessionsUseCase(tokenRepo)
+	revokeUserSessionUseCase := usecases.NewRevokeUserSessionUseCase(
+		tokenRepo, accessTokenDenylist,
+		cfg.JWT.AccessTokenTTL,
+	)
+	revokeOtherUserSessionsUseCase := usecases.NewRevokeOtherUserSessionsUseCase(
+		tokenRepo, accessTokenDenylist,
+		cfg.JWT.AccessTokenTTL,
+	)
+
+	listDevicesUseCase := usecases.NewListDevicesUseCase(deviceRepo)
+
+	// Introspection and revocation accept tokens minted for any client's audience
+	introspectTokenUseCase := usecases.NewIntrospectTokenUseCase(
+		clientRepo, tokenRepo, userRepo,
+		accessTokenDenylist, cfg.Security.DenylistFailClosed,
+		jwtService, hashService,
+		tokenClaims, sessions,
+		services.WithIssuer(cfg.JWT.Issuer),
+	)
+	revokeTokenUseCase := usecases.NewRevokeTokenUseCase(
+		clientRepo, tokenRepo,
+		accessTokenDenylist,
+		jwtService, hashService,
+		services.WithIssuer(cfg.JWT.Issuer),
+	)
+
+	// Authorization code flow: the user logs in on the hosted page and the code
+	// parked in Redis records who; the session starts when the client exchanges it
+	authorizationCodeRepo := redis.NewAuthorizationCodeRepository(redisConn)
+	authorizeUseCase := usecases.NewAuthorizeUseCase(clientRepo)
+	completeAuthorizationUseCase := usecases.NewCompleteAuthorizationUseCase(
+		authorizeUseCase, loginUseCase,
+		authorizationCodeRepo, hashService,
+		cfg.OAuth.AuthorizationCodeTTL,
+	)
+	exchangeAuthorizationCodeUseCase := usecases.NewExchangeAuthorizationCodeUseCase(
+		clientRepo, authorizationCodeRepo, userRepo, tokenRepo, accessTokenDenylist,
+		hashService, loginUseCase, securityEvents,
+		cfg.JWT.AccessTokenTTL,
+	)
+	exchangeRefreshTokenUseCase := usecases.NewExchangeRefreshTokenUseCase(
+		clientRepo, tokenRepo, hashService, refreshUseCase,
+	)
+	issueClientTokenUseCase := usecases.NewIssueClientTokenUseCase(
+		clientRepo, jwtService, hashService,
+		cfg.JWT.AccessTokenTTL,
+		tokenClaims,
+	)
+
+	revokeUserTokensUseCase := usecases.NewRevokeUserTokensUseCase(
+		userRepo, tokenRepo,
+		accessTokenDenylist,
+		cfg.JWT.AccessTokenTTL,
+	)
+
 	getUserProfileUseCase := usecases.NewGetUserProfileUseCase(
 		userRepo,
 	)
 
+	var openIDConfiguration *dto.OpenIDConfiguration
+	if cfg.OIDC.Enabled {
+		openIDConfiguration = dto.NewOpenIDConfiguration(cfg.OIDC.Issuer, cfg.JWT.Algorithm)
+	}
+	getOIDCUserInfoUseCase := usecases.NewGetOIDCUserInfoUseCase(userRepo)
+
+	// Admins manage clients only when they are registered in the database
+	var listClientsUseCase *usecases.ListClientsUseCase
+	var createClientUseCase *usecases.CreateClientUseCase
+	var updateClientUseCase *usecases.UpdateClientUseCase
+	var deleteClientUseCase *usecases.DeleteClientUseCase
+	if clientRegistry != nil {
+		listClientsUseCase = usecases.NewListClientsUseCase(clientRegistry)
+		createClientUseCase = usecases.NewCreateClientUseCase(clientRegistry, hashService, sessions, cfg.JWT.AccessTokenTTL)
+		updateClientUseCase = usecases.NewUpdateClientUseCase(clientRegistry, hashService, sessions, cfg.JWT.AccessTokenTTL)
+		deleteClientUseCase = usecases.NewDeleteClientUseCase(clientRegistry)
+	}
+
 	getUsersListUseCase := usecases.NewGetUsersListUseCase(
 		userRepo,
 	)
 
+	ipBanRepo := redis.NewIPBanRepository(redisConn)
+	listIPBansUseCase := usecases.NewListIPBansUseCase(ipBanRepo)
+	addIPBanUseCase := usecases.NewAddIPBanUseCase(ipBanRepo)
+	removeIPBanUseCase := usecases.NewRemoveIPBanUseCase(ipBanRepo)
+
+	var ipReputation *middleware.IPReputation
+	if cfg.Security.IPReputation.Enabled {
+		ipReputation, err = middleware.NewIPReputation(ipBanRepo, rateLimiter, middleware.IPReputationConfig{
+			AllowCIDRs:       cfg.Security.IPReputation.AllowCIDRs,
+			DenyCIDRs:        cfg.Security.IPReputation.DenyCIDRs,
+			FailureThreshold: cfg.Security.IPReputation.FailureThreshold,
+			FailureWindow:    cfg.Security.IPReputation.FailureWindow,
+			BanTTL:           cfg.Security.IPReputation.BanTTL,
+			CacheTTL:         cfg.Security.IPReputation.CacheTTL,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize IP reputation: %v", err)
+		}
+	}
+
 	log.Println("ratelimit: ", cfg.Security.RateLimit.OTPWindow, cfg.Security.RateLimit.OTPLimit)
-	// Setup router
+	// Setup router; same_site was checked when the config was loaded
+	cookieSameSite, _ := middleware.ParseSameSite(cfg.Auth.Cookie.SameSite)
 	deps := router.Dependencies{
-		SendOTPUseCase:        sendOTPUseCase,
-		LoginUseCase:          loginUseCase,
-		RefreshUseCase:        refreshUseCase,
-		LogoutUseCase:         logoutUseCase,
-		GetUserProfileUseCase: getUserProfileUseCase,
-		GetUsersListUseCase:   getUsersListUseCase,
+		SendOTPUseCase:          sendOTPUseCase,
+		SendOTPChallengeUseCase: sendOTPChallengeUseCase,
+		LoginUseCase:            loginUseCase,
+		RefreshUseCase:          refreshUseCase,
+		LogoutUseCase:           logoutUseCase,
+		GetUserProfileUseCase:   getUserProfileUseCase,
+		GetUsersListUseCase:     getUsersListUseCase,
+		ListIPBansUseCase:       listIPBansUseCase,
+		AddIPBanUseCase:         addIPBanUseCase,
+		RemoveIPBanUseCase:      removeIPBanUseCase,
+		RevokeUserTokensUseCase: revokeUserTokensUseCase,
+
+		ListUserSessionsUseCase:        listUserSessionsUseCase,
+		RevokeUserSessionUseCase:       revokeUserSessionUseCase,
+		RevokeOtherUserSessionsUseCase: revokeOtherUserSessionsUseCase,
+		RevokeSessionByLinkUseCase:     revokeSessionByLinkUseCase,
+		ListDevicesUseCase:             listDevicesUseCase,
+		IntrospectTokenUseCase:         introspectTokenUseCase,
+		RevokeTokenUseCase:             revokeTokenUseCase,
+		GetOIDCUserInfoUseCase:         getOIDCUserInfoUseCase,
+
+		AuthorizeUseCase:                 authorizeUseCase,
+		CompleteAuthorizationUseCase:     completeAuthorizationUseCase,
+		ExchangeAuthorizationCodeUseCase: exchangeAuthorizationCodeUseCase,
+		IssueClientTokenUseCase:          issueClientTokenUseCase,
+		ExchangeRefreshTokenUseCase:      exchangeRefreshTokenUseCase,
+
+		ListClientsUseCase:  listClientsUseCase,
+		CreateClientUseCase: createClientUseCase,
+		UpdateClientUseCase: updateClientUseCase,
+		DeleteClientUseCase: deleteClientUseCase,
+
 		JWTService:            jwtService,
+		KeySetProvider:        keySetProvider,
+		TokenVerifyOptions:    tokenVerifyOptions,
+		UserInfoVerifyOptions: []services.VerifyOption{services.WithIssuer(cfg.JWT.Issuer)},
 		RateLimiter:           rateLimiter,
+		AccessTokenDenylist:   accessTokenDenylist,
+		DenylistFailClosed:    cfg.Security.DenylistFailClosed,
+		ClientRepository:      clientRepo,
+		IPReputation:          ipReputation,
 		RateLimitConfig:       &cfg.Security.RateLimit,
+		TokenTransport: middleware.TokenTransport{
+			Precedence: middleware.TokenPrecedence(cfg.Auth.TokenPrecedence),
+			TokenOnly:  cfg.Auth.TokenOnly,
+			Cookie: middleware.CookieConfig{
+				Secure:   cfg.Auth.Cookie.Secure,
+				SameSite: cookieSameSite,
+				Domain:   cfg.Auth.Cookie.Domain,
+				Path:     cfg.Auth.Cookie.Path,
+			},
+			CSRF: cfg.Auth.CSRF.Enabled,
+		},
+		OpenIDConfiguration: openIDConfiguration,
 	}
 
 	var r *gin.Engine
 	if cfg.Server.Mode == gin.ReleaseMode {
-		r = router.SetupProductionRouter(deps, []string{"*"}) // Allow all origins for now
+		r = router.SetupProductionRouter(deps, cfg.CORS.AllowOrigins)
 	} else {
 		r = router.SetupDevelopmentRouter(deps)
 	}
@@ -151,11 +356,29 @@ func main() {
 		}
 	}()
 
+	// Metrics are only served on the internal listener
+	var metricsSrv *http.Server
+	if cfg.Server.MetricsAddress != "" {
+		metricsSrv = &http.Server{
+			Addr:        cfg.Server.MetricsAddress,
+			Handler:     router.SetupMetricsRouter(),
+			ReadTimeout: cfg.Server.ReadTimeout,
+			IdleTimeout: cfg.Server.IdleTimeout,
+		}
+		go func() {
+			log.Printf("Serving metrics on %s", cfg.Server.MetricsAddress)
+			if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to start metrics server: %v", err)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopRotation()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -164,6 +387,9 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	if metricsSrv != nil {
+		metricsSrv.Shutdown(ctx)
+	}
 
 	// Close database connection
 	db.Close()
@@ -179,14 +405,37 @@ func initializeRepositories(db *sql.DB, redisConn *redisClient.Client) (reposito
 }
 
 func initializeServices(cfg *config.Config) (services.OTPSender, services.JWTService, services.HashService) {
-	// Initialize JWT service
-	jwtService, err := infraServices.NewECDSAJWTService(infraServices.JWTConfig{
+	jwtConfig := infraServices.JWTConfig{
 		PrivateKeyPEM:   cfg.JWT.PrivateKeyPEM,
 		PublicKeyPEM:    cfg.JWT.PublicKeyPEM,
 		AccessTokenTTL:  cfg.JWT.AccessTokenTTL,
 		RefreshTokenTTL: cfg.JWT.RefreshTokenTTL,
 		Issuer:          cfg.JWT.Issuer,
-	})
+
+		VerificationKeysPEM: cfg.JWT.VerificationKeysPEM,
+	}
+
+	// Without configured keys, share one persisted pair across restarts and replicas.
+	// A key ring signs with its own keys, so no pair is needed then.
+	if jwtConfig.PrivateKeyPEM == "" && jwtConfig.PublicKeyPEM == "" && cfg.JWT.KeysDir != "" && cfg.JWT.KeyRing.Store == "" {
+		var err error
+		jwtConfig.PrivateKeyPEM, jwtConfig.PublicKeyPEM, err = filesystem.NewKeyPairStore(cfg.JWT.KeysDir).LoadOrCreate(keyPairGenerator(cfg.JWT.Algorithm))
+		if err != nil {
+			log.Fatalf("Failed to load signing keys: %v", err)
+		}
+	}
+
+	// Initialize JWT service
+	var jwtService services.JWTService
+	var err error
+	switch cfg.JWT.Algorithm {
+	case infraServices.AlgorithmRS256:
+		jwtService, err = infraServices.NewRSAJWTService(jwtConfig)
+	case infraServices.AlgorithmEdDSA:
+		jwtService, err = infraServices.NewEdDSAJWTService(jwtConfig)
+	default:
+		jwtService, err = infraServices.NewECDSAJWTService(jwtConfig)
+	}
 	if err != nil {
 		log.Fatalf("Failed to initialize JWT service: %v", err)
 	}
@@ -209,3 +458,230 @@ func initializeServices(cfg *config.Config) (services.OTPSender, services.JWTSer
 
 	return otpSender, jwtService, hashService
 }
+
+func initializeChallenge(cfg *config.Config, redisConn *redisClient.Client, rateLimiter repositories.RateLimiter) *usecases.SendOTPChallengeUseCase {
+	challengeCfg := cfg.Security.Challenge
+	if challengeCfg.Mode == usecases.ChallengeModeOff {
+		return nil
+	}
+
+	var verifier services.ChallengeVerifier
+	var err error
+	switch challengeCfg.Type {
+	case services.ChallengeTypeCaptcha:
+		verifier, err = infraServices.NewCaptchaChallengeVerifier(infraServices.CaptchaConfig{
+			VerifyURL: challengeCfg.Captcha.VerifyURL,
+			Secret:    challengeCfg.Captcha.Secret,
+			SiteKey:   challengeCfg.Captcha.SiteKey,
+			Timeout:   challengeCfg.Captcha.Timeout,
+		})
+	default:
+		verifier, err = infraServices.NewPoWChallengeVerifier(infraServices.PoWConfig{
+			Secret:     challengeCfg.PoW.Secret,
+			Difficulty: challengeCfg.PoW.Difficulty,
+			TTL:        challengeCfg.PoW.TTL,
+		}, redis.NewChallengeRepository(redisConn))
+	}
+	if err != nil {
+		log.Fatalf("Failed to initialize challenge verifier: %v", err)
+	}
+
+	return usecases.NewSendOTPChallengeUseCase(verifier, rateLimiter, usecases.ChallengePolicy{
+		Mode:           challengeCfg.Mode,
+		IPThreshold:    challengeCfg.IPThreshold,
+		PhoneThreshold: challengeCfg.PhoneThreshold,
+		Window:         challengeCfg.Window,
+	})
+}
+
+// initializeNewLoginNotifications returns nil when new-login notifications are disabled
+func initializeNewLoginNotifications(cfg *config.Config, revokeLinkRepo repositories.RevokeLinkRepository, rateLimiter repositories.RateLimiter, hashService services.HashService) *usecases.NotifyNewLoginUseCase {
+	newLoginCfg := cfg.Notifications.NewLogin
+	if !newLoginCfg.Enabled {
+		return nil
+	}
+
+	var sender services.NotificationSender
+	switch cfg.Notifications.SenderType {
+	case "console":
+		sender = infraServices.NewConsoleNotificationSender(nil)
+	default:
+		// Default to console sender
+		sender = infraServices.NewConsoleNotificationSender(nil)
+		log.Printf("Unknown notification sender type '%s', using console sender", cfg.Notifications.SenderType)
+	}
+
+	return usecases.NewNotifyNewLoginUseCase(sender, revokeLinkRepo, rateLimiter, hashService, usecases.LoginNotificationPolicy{
+		RevokeURL:     newLoginCfg.RevokeURL,
+		RevokeLinkTTL: newLoginCfg.RevokeLinkTTL,
+		Limit:         newLoginCfg.Limit,
+		Window:        newLoginCfg.Window,
+		DefaultLocale: newLoginCfg.DefaultLocale,
+	})
+}
+
+// tokenClaimsConfig builds the access token issuer and default audience; clients
+// and their audiences come from clients
+func tokenClaimsConfig(cfg *config.Config, clients repositories.ClientReader) usecases.TokenClaimsConfig {
+	var defaultAudience []string
+	if cfg.JWT.Audience != "" {
+		defaultAudience = []string{cfg.JWT.Audience}
+	}
+
+	claims := usecases.TokenClaimsConfig{
+		Issuer:          cfg.JWT.Issuer,
+		DefaultClientID: cfg.JWT.DefaultClientID,
+		DefaultAudience: defaultAudience,
+		Clients:         clients,
+	}
+	if cfg.OIDC.Enabled {
+		claims.IDTokenIssuer = cfg.OIDC.Issuer
+		claims.IDTokenTTL = cfg.OIDC.IDTokenTTL
+	}
+	return claims
+}
+
+// initializeClients returns the client registry. The postgres store is seeded with
+// the clients in the config file that aren't registered yet and also returned as
+// a repository admins can manage; otherwise the config file is the registry.
+func initializeClients(cfg *config.Config, db *sql.DB) (repositories.ClientReader, repositories.ClientRepository) {
+	if cfg.JWT.ClientStore != "postgres" {
+		return memory.NewClientRepository(configuredClients(cfg)), nil
+	}
+
+	clientRepo := postgres.NewClientRepository(db)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, client := range configuredClients(cfg) {
+		if err := clientRepo.Create(ctx, client); err != nil {
+			if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.ConflictError {
+				log.Fatalf("Failed to register client %s: %v", client.ID, err)
+			}
+		}
+	}
+
+	return clientRepo, clientRepo
+}
+
+// configuredClients returns the clients listed in the config file
+func configuredClients(cfg *config.Config) []*entities.Client {
+	clients := make([]*entities.Client, 0, len(cfg.JWT.Clients))
+	for _, configured := range cfg.JWT.Clients {
+		client := entities.NewClient(configured.ID)
+		client.SecretHash = configured.SecretHash
+		client.Type = configured.Type
+		client.Audience = configured.Audience
+		client.RedirectURIs = configured.RedirectURIs
+		if configured.AllowedScopes != nil {
+			client.AllowedScopes = configured.AllowedScopes
+		}
+		if configured.AllowedGrantTypes != nil {
+			client.AllowedGrantTypes = configured.AllowedGrantTypes
+		}
+		client.AccessTokenTTL = configured.AccessTokenTTL
+		client.RefreshTokenTTL = configured.RefreshTokenTTL
+		client.CORSOrigins = configured.CORSOrigins
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// sessionPolicies maps every client type to its session limits
+func sessionPolicies(cfg *config.Config) usecases.SessionPolicies {
+	byType := make(map[string]usecases.SessionPolicy, len(cfg.JWT.Sessions))
+	for clientType, policy := range cfg.JWT.Sessions {
+		byType[clientType] = usecases.SessionPolicy{
+			AbsoluteLifetime: policy.AbsoluteLifetime,
+			IdleTimeout:      policy.IdleTimeout,
+		}
+	}
+
+	return usecases.SessionPolicies{
+		Default: byType[config.DefaultClientType],
+		ByType:  byType,
+	}
+}
+
+// signingKeyGenerator returns the key ring generator for the configured algorithm
+func signingKeyGenerator(algorithm string) services.SigningKeyGenerator {
+	switch algorithm {
+	case infraServices.AlgorithmRS256:
+		return infraServices.NewRSAKeyGenerator()
+	case infraServices.AlgorithmEdDSA:
+		return infraServices.NewEdDSAKeyGenerator()
+	default:
+		return infraServices.NewECDSAKeyGenerator()
+	}
+}
+
+// keyPairGenerator returns the keys directory generator for the configured algorithm
+func keyPairGenerator(algorithm string) filesystem.KeyPairGenerator {
+	switch algorithm {
+	case infraServices.AlgorithmRS256:
+		return infraServices.GenerateRSAKeyPairPEM
+	case infraServices.AlgorithmEdDSA:
+		return infraServices.GenerateEdDSAKeyPairPEM
+	default:
+		return infraServices.GenerateECDSAKeyPairPEM
+	}
+}
+
+func initializeKeyRing(cfg *config.Config, db *sql.DB, jwtService services.JWTService) *usecases.RotateSigningKeysUseCase {
+	var keyRepo repositories.SigningKeyRepository
+	var err error
+	switch cfg.JWT.KeyRing.Store {
+	case "directory":
+		keyRepo, err = filesystem.NewSigningKeyRepository(cfg.JWT.KeyRing.Directory)
+		if err != nil {
+			log.Fatalf("Failed to open signing key directory: %v", err)
+		}
+	case "postgres":
+		keyRepo = postgres.NewSigningKeyRepository(db)
+	default:
+		return nil
+	}
+
+	loader, ok := jwtService.(services.SigningKeyLoader)
+	if !ok {
+		log.Fatalf("JWT service does not support a signing key ring")
+	}
+
+	// Retired keys must verify every token they signed, ID tokens included, and
+	// other replicas keep signing with a retired key until their next check
+	maxTokenTTL := cfg.JWT.AccessTokenTTL
+	if cfg.OIDC.Enabled && cfg.OIDC.IDTokenTTL > maxTokenTTL {
+		maxTokenTTL = cfg.OIDC.IDTokenTTL
+	}
+
+	rotateKeysUseCase := usecases.NewRotateSigningKeysUseCase(keyRepo, signingKeyGenerator(cfg.JWT.Algorithm), loader, usecases.SigningKeyRotationPolicy{
+		RotationInterval: cfg.JWT.KeyRing.RotationInterval,
+		PublishAhead:     cfg.JWT.KeyRing.PublishAhead,
+		MaxTokenTTL:      maxTokenTTL + cfg.JWT.KeyRing.CheckInterval,
+	})
+
+	// The service must not start signing before the ring is loaded
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := rotateKeysUseCase.Execute(ctx); err != nil {
+		log.Fatalf("Failed to load signing key ring: %v", err)
+	}
+
+	return rotateKeysUseCase
+}
+
+// runKeyRotation periodically rotates the key ring and picks up rotations done by other replicas
+func runKeyRotation(ctx context.Context, rotateKeysUseCase *usecases.RotateSigningKeysUseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := rotateKeysUseCase.Execute(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("[WARN] Signing key rotation failed, keeping current keys: %v", err)
+			}
+		}
+	}
+}
@@ -6,27 +6,47 @@ import (
 
 // SendOTPRequest represents the request to send an OTP
 type SendOTPRequest struct {
-	PhoneNumber string `json:"phone_number" binding:"required" example:"+989123456789"`
-	SessionID   string `json:"session_id,omitempty" example:"abc123def456"`
+	PhoneNumber string             `json:"phone_number" binding:"required" example:"+989123456789"`
+	SessionID   string             `json:"session_id,omitempty" example:"abc123def456"`
+	Challenge   *ChallengeSolution `json:"challenge,omitempty"`
+}
+
+// ChallengeSolution represents the client's answer to an anti-abuse challenge
+type ChallengeSolution struct {
+	Token string `json:"token" example:"eyJ2IjoxfQ.c2lnbmF0dXJl"` // Proof-of-work token or captcha response
+	Nonce string `json:"nonce,omitempty" example:"184467"`       // Proof-of-work nonce
 }
 
 // LoginRequest represents the request to login/register
 type LoginRequest struct {
-	PhoneNumber string `json:"phone_number" binding:"required" example:"+989123456789"`
-	OTP         string `json:"otp" binding:"required" example:"123456"`
+	PhoneNumber    string `json:"phone_number" binding:"required" example:"+989123456789"`
+	OTP            string `json:"otp" binding:"required" example:"123456"`
+	ClientID       string `json:"client_id,omitempty" example:"otp-auth-client"` // Defaults to the configured client
+	DeviceName     string `json:"device_name,omitempty" binding:"omitempty,max=100" example:"Pixel 8"` // Shown in the user's sessions list
+	DeviceID       string `json:"device_id,omitempty" binding:"omitempty,max=255" example:"3f2a9c1e-7b4d-4e8a-9f0c-2d6b8e1a5c7f"` // Stable ID chosen by native apps
+	AppVersion     string `json:"app_version,omitempty" binding:"omitempty,max=50" example:"2.4.1"` // Defaults to the X-App-Version header
+	SessionID      string `json:"session_id,omitempty" example:"abc123def456"` // From send-otp; the session_id cookie is used when absent
+	UserAgent      string `json:"-"` // Read from the request headers
+	IPAddress      string `json:"-"` // Client IP of the request
+	AcceptLanguage string `json:"-"` // Read from the request headers; selects the notification language
+	Nonce          string `json:"-"` // Copied into the ID token; set by the authorization code flow
 }
 
 // RefreshTokenRequest represents the request to refresh tokens
 type RefreshTokenRequest struct {
-	RefreshToken string `json:"refresh_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	SessionID    string `json:"-"` // This field is populated from cookies, not request body
+	RefreshToken string `json:"refresh_token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // Or the refresh_token cookie
+	SessionID    string `json:"session_id,omitempty" example:"abc123def456"` // Or the session_id cookie
+	UserAgent    string `json:"-"` // Read from the request headers
+	IPAddress    string `json:"-"` // Client IP of the request
+	AppVersion   string `json:"-"` // Read from the X-App-Version header
 }
 
 // LogoutRequest represents the request to logout
-// Both RefreshToken and SessionID are populated from cookies, not request body
+// Each field falls back to the cookie of the same name
 type LogoutRequest struct {
-	RefreshToken string `json:"-"` // Read from cookies
-	SessionID    string `json:"-"` // Read from cookies
+	AccessToken  string `json:"-"`                       // Read from the Authorization header
+	RefreshToken string `json:"refresh_token,omitempty"` // Read from the body
+	SessionID    string `json:"session_id,omitempty"`    // Read from the body
 }
 
 // GetUsersRequest represents the request to get users list
@@ -38,11 +58,91 @@ type GetUsersRequest struct {
 	DateTo       string `form:"date_to" example:"2024-12-31"`
 }
 
+// ListDevicesRequest represents the request to list devices
+type ListDevicesRequest struct {
+	Page   int    `form:"page" example:"1"`
+	Limit  int    `form:"limit" example:"10"`
+	UserID string `form:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
 // UpdateUserScopeRequest represents the request to update user scope
 type UpdateUserScopeRequest struct {
 	Scope string `json:"scope" binding:"required" example:"superadmin"`
 }
 
+// AddIPBanRequest represents the request to ban an IP address or CIDR block
+type AddIPBanRequest struct {
+	CIDR            string `json:"cidr" binding:"required" example:"203.0.113.0/24"`
+	Reason          string `json:"reason" example:"credential stuffing"`
+	DurationSeconds int    `json:"duration_seconds" example:"3600"` // 0 for a permanent ban
+}
+
+// ClientSettings holds the settings of a registered client that admins can change
+type ClientSettings struct {
+	Type                   string   `json:"type" example:"web"` // selects the session policy; empty for the default
+	Audience               []string `json:"audience" example:"otp-auth"`
+	RedirectURIs           []string `json:"redirect_uris" example:"https://partner.example.com/callback"`
+	AllowedScopes          []string `json:"allowed_scopes" example:"openid,phone"`         // omitted: openid and phone
+	AllowedGrantTypes      []string `json:"allowed_grant_types" example:"authorization_code"` // omitted: authorization_code
+	AccessTokenTTLSeconds  int64    `json:"access_token_ttl_seconds" example:"900"`         // 0 for the service default
+	RefreshTokenTTLSeconds int64    `json:"refresh_token_ttl_seconds" example:"604800"`     // 0 for the service default
+	CORSOrigins            []string `json:"cors_origins" example:"https://partner.example.com"`
+}
+
+// CreateClientRequest represents the request to register a client
+type CreateClientRequest struct {
+	ID           string `json:"id" binding:"required" example:"partner-app"`
+	Confidential bool   `json:"confidential" example:"true"` // issue a client secret
+	ClientSettings
+}
+
+// UpdateClientRequest represents the request to replace a client's settings
+type UpdateClientRequest struct {
+	RotateSecret bool `json:"rotate_secret" example:"false"` // issue a new client secret, making the client confidential
+	ClientSettings
+}
+
+// IntrospectTokenRequest represents an RFC 7662 token introspection request
+type IntrospectTokenRequest struct {
+	Token         string `form:"token" example:"eyJhbGciOiJFUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenTypeHint string `form:"token_type_hint" example:"access_token"` // access_token, refresh_token
+	ClientID      string `form:"client_id" example:"api-gateway"`        // Or HTTP Basic authentication
+	ClientSecret  string `form:"client_secret" example:"s3cret"`         // Or HTTP Basic authentication
+}
+
+// RevokeTokenRequest represents an RFC 7009 token revocation request
+type RevokeTokenRequest struct {
+	Token         string `form:"token" example:"eyJhbGciOiJFUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenTypeHint string `form:"token_type_hint" example:"refresh_token"` // access_token, refresh_token
+	ClientID      string `form:"client_id" example:"partner-app"`         // Or HTTP Basic authentication
+	ClientSecret  string `form:"client_secret" example:"s3cret"`          // Or HTTP Basic authentication
+}
+
+// AuthorizationRequest represents an RFC 6749 authorization request with PKCE (RFC 7636);
+// the hosted login page carries it through its forms
+type AuthorizationRequest struct {
+	ResponseType        string `form:"response_type" example:"code"`
+	ClientID            string `form:"client_id" example:"partner-app"`
+	RedirectURI         string `form:"redirect_uri" example:"https://app.example.com/callback"`
+	Scope               string `form:"scope" example:"openid phone"` // space separated
+	State               string `form:"state" example:"af0ifjsldkj"`
+	Nonce               string `form:"nonce" example:"n-0S6_WzA2Mj"` // copied into the ID token
+	CodeChallenge       string `form:"code_challenge" example:"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"`
+	CodeChallengeMethod string `form:"code_challenge_method" example:"S256"` // only S256
+}
+
+// OAuthTokenRequest represents an RFC 6749 token request
+type OAuthTokenRequest struct {
+	GrantType    string `form:"grant_type" example:"authorization_code"`
+	Code         string `form:"code" example:"SplxlOBeZQQYbYS6WxSbIA"`
+	RedirectURI  string `form:"redirect_uri" example:"https://app.example.com/callback"`
+	CodeVerifier string `form:"code_verifier" example:"dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"`
+	RefreshToken string `form:"refresh_token" example:"8xLOxBtZp8"`
+	Scope        string `form:"scope" example:"orders:read"`     // client_credentials only; space separated
+	ClientID     string `form:"client_id" example:"partner-app"` // Or HTTP Basic authentication
+	ClientSecret string `form:"client_secret" example:"s3cret"`  // Or HTTP Basic authentication; empty for public clients
+}
+
 // Validate validates the SendOTPRequest
 func (r *SendOTPRequest) Validate() error {
 	_, err := valueobjects.NewPhoneNumber(r.PhoneNumber)
@@ -60,7 +160,6 @@ func (r *LoginRequest) Validate() error {
 
 // Validate validates the RefreshTokenRequest
 func (r *RefreshTokenRequest) Validate() error {
-	// SessionID validation is handled separately since it comes from cookies
 	if r.SessionID != "" {
 		_, err := valueobjects.NewSessionIDFromString(r.SessionID)
 		return err
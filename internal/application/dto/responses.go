@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"strings"
 	"time"
 
 	"github.com/otp-auth/internal/domain/entities"
@@ -12,11 +13,29 @@ type SendOTPResponse struct {
 	SessionID string `json:"session_id" example:"abc123def456"`
 }
 
+// ChallengeInfo describes a challenge the client must solve before retrying
+type ChallengeInfo struct {
+	Type       string     `json:"type" example:"pow"`
+	Token      string     `json:"token,omitempty" example:"eyJ2IjoxfQ.c2lnbmF0dXJl"`
+	Difficulty int        `json:"difficulty,omitempty" example:"20"`
+	SiteKey    string     `json:"site_key,omitempty" example:"10000000-ffff-ffff-ffff-000000000001"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" example:"2024-01-01T12:00:00Z"`
+}
+
+// ChallengeRequiredResponse is returned when a request must solve a challenge first
+type ChallengeRequiredResponse struct {
+	Error     string        `json:"error" example:"Challenge required"`
+	Code      string        `json:"code" example:"CHALLENGE_REQUIRED"`
+	Details   string        `json:"details,omitempty" example:"Insufficient proof of work"`
+	Challenge ChallengeInfo `json:"challenge"`
+}
+
 // LoginResponse represents the response after successful login/register
 type LoginResponse struct {
 	Message          string    `json:"message" example:"Login successful"`
 	AccessToken      string    `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
 	RefreshToken     string    `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	IDToken          string    `json:"id_token,omitempty" example:"eyJhbGciOiJFUzI1NiIsImtpZCI6Ii4uLiJ9..."`
 	ExpiresAt        time.Time `json:"expires_at" example:"2024-01-01T12:00:00Z"`
 	RefreshExpiresAt time.Time `json:"refresh_expires_at" example:"2024-01-01T12:00:00Z"`
 	User             UserInfo  `json:"user"`
@@ -27,6 +46,7 @@ type RefreshTokenResponse struct {
 	Message          string    `json:"message" example:"Token refreshed successfully"`
 	AccessToken      string    `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
 	RefreshToken     string    `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	IDToken          string    `json:"id_token,omitempty" example:"eyJhbGciOiJFUzI1NiIsImtpZCI6Ii4uLiJ9..."`
 	ExpiresAt        time.Time `json:"expires_at" example:"2024-01-01T12:00:00Z"`
 	RefreshExpiresAt time.Time `json:"refresh_expires_at" example:"2024-01-01T12:00:00Z"`
 }
@@ -59,6 +79,171 @@ type GetUsersResponse struct {
 	TotalPages int        `json:"total_pages" example:"10"`
 }
 
+// IPBanInfo represents a banned IP range in responses
+type IPBanInfo struct {
+	CIDR      string     `json:"cidr" example:"203.0.113.0/24"`
+	Reason    string     `json:"reason" example:"credential stuffing"`
+	Source    string     `json:"source" example:"manual"`
+	CreatedAt time.Time  `json:"created_at" example:"2024-01-01T12:00:00Z"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2024-01-01T13:00:00Z"`
+}
+
+// IPBansResponse represents the response for listing IP bans
+type IPBansResponse struct {
+	Bans []IPBanInfo `json:"bans"`
+}
+
+// ClientInfo represents a registered client in responses
+type ClientInfo struct {
+	ID                     string    `json:"id" example:"partner-app"`
+	Confidential           bool      `json:"confidential" example:"true"`
+	ClientSecret           string    `json:"client_secret,omitempty" example:"Vb3kQ9..."` // only when a secret is issued
+	Type                   string    `json:"type" example:"web"`
+	Audience               []string  `json:"audience" example:"otp-auth"`
+	RedirectURIs           []string  `json:"redirect_uris" example:"https://partner.example.com/callback"`
+	AllowedScopes          []string  `json:"allowed_scopes" example:"openid,phone"`
+	AllowedGrantTypes      []string  `json:"allowed_grant_types" example:"authorization_code"`
+	AccessTokenTTLSeconds  int64     `json:"access_token_ttl_seconds" example:"900"`
+	RefreshTokenTTLSeconds int64     `json:"refresh_token_ttl_seconds" example:"604800"`
+	CORSOrigins            []string  `json:"cors_origins" example:"https://partner.example.com"`
+	CreatedAt              time.Time `json:"created_at" example:"2024-01-01T12:00:00Z"`
+	UpdatedAt              time.Time `json:"updated_at" example:"2024-01-02T08:30:00Z"`
+}
+
+// ClientsResponse represents the response for listing clients
+type ClientsResponse struct {
+	Clients []ClientInfo `json:"clients"`
+}
+
+// SessionInfo represents one of the user's active sessions
+type SessionInfo struct {
+	ID         string    `json:"id" example:"9f86d081884c7d659a2feaa0c55ad015"`
+	DeviceName string    `json:"device_name,omitempty" example:"Pixel 8"`
+	UserAgent  string    `json:"user_agent,omitempty" example:"Mozilla/5.0 (Linux; Android 14)"`
+	IPAddress  string    `json:"ip_address,omitempty" example:"203.0.113.7"`
+	ClientID   string    `json:"client_id" example:"otp-auth-client"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-01-01T12:00:00Z"`
+	LastUsedAt time.Time `json:"last_used_at" example:"2024-01-02T08:30:00Z"`
+	ExpiresAt  time.Time `json:"expires_at" example:"2024-01-09T08:30:00Z"`
+	Current    bool      `json:"current" example:"true"`
+}
+
+// SessionsResponse represents the response for listing the user's sessions
+type SessionsResponse struct {
+	Sessions []SessionInfo `json:"sessions"`
+}
+
+// DeviceInfo represents a device a user has logged in from
+type DeviceInfo struct {
+	ID          string    `json:"id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	UserID      string    `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DeclaredID  string    `json:"declared_id,omitempty" example:"3f2a9c1e-7b4d-4e8a-9f0c-2d6b8e1a5c7f"`
+	Name        string    `json:"name,omitempty" example:"Pixel 8"`
+	UserAgent   string    `json:"user_agent,omitempty" example:"Mozilla/5.0 (Linux; Android 14)"`
+	OS          string    `json:"os,omitempty" example:"Android"`
+	Browser     string    `json:"browser,omitempty" example:"Chrome"`
+	AppVersion  string    `json:"app_version,omitempty" example:"2.4.1"`
+	FirstIP     string    `json:"first_ip,omitempty" example:"203.0.113.7"`
+	LastIP      string    `json:"last_ip,omitempty" example:"198.51.100.23"`
+	FirstSeenAt time.Time `json:"first_seen_at" example:"2024-01-01T12:00:00Z"`
+	LastSeenAt  time.Time `json:"last_seen_at" example:"2024-01-02T08:30:00Z"`
+}
+
+// DevicesResponse represents the response for listing devices
+type DevicesResponse struct {
+	Devices    []DeviceInfo `json:"devices"`
+	Total      int64        `json:"total" example:"100"`
+	Page       int          `json:"page" example:"1"`
+	Limit      int          `json:"limit" example:"10"`
+	TotalPages int          `json:"total_pages" example:"10"`
+}
+
+// IntrospectTokenResponse represents an RFC 7662 token introspection response;
+// inactive tokens only have active set
+type IntrospectTokenResponse struct {
+	Active    bool   `json:"active" example:"true"`
+	Scope     string `json:"scope,omitempty" example:"user"` // space separated
+	ClientID  string `json:"client_id,omitempty" example:"otp-auth-client"`
+	Subject   string `json:"sub,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ExpiresAt int64  `json:"exp,omitempty" example:"1704110400"`
+	IssuedAt  int64  `json:"iat,omitempty" example:"1704109500"`
+	TokenID   string `json:"jti,omitempty" example:"9b2f6c1e-4a3d-4f7e-8c5b-1d2e3f4a5b6c"`
+}
+
+// OAuthTokenResponse represents an RFC 6749 access token response
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token" example:"eyJhbGciOiJFUzI1NiIsImtpZCI6Ii4uLiJ9..."`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int64  `json:"expires_in" example:"900"` // seconds
+	RefreshToken string `json:"refresh_token,omitempty" example:"8xLOxBtZp8"`
+	IDToken      string `json:"id_token,omitempty" example:"eyJhbGciOiJFUzI1NiIsImtpZCI6Ii4uLiJ9..."` // when openid was requested
+	Scope        string `json:"scope,omitempty" example:"openid phone"`
+	SessionID    string `json:"session_id,omitempty" example:"abc123def456"` // for /api/v1/auth/refresh
+}
+
+// OAuthErrorResponse represents an RFC 6749 error response of the OAuth endpoints
+type OAuthErrorResponse struct {
+	Error            string `json:"error" example:"invalid_client"`
+	ErrorDescription string `json:"error_description,omitempty" example:"Invalid client credentials"`
+}
+
+// OIDCUserInfoResponse represents an OpenID Connect UserInfo response
+type OIDCUserInfoResponse struct {
+	Subject             string `json:"sub" example:"123e4567-e89b-12d3-a456-426614174000"`
+	PhoneNumber         string `json:"phone_number" example:"+989121234567"`
+	PhoneNumberVerified bool   `json:"phone_number_verified" example:"true"`
+}
+
+// OpenIDConfiguration represents an OpenID Connect discovery document
+type OpenIDConfiguration struct {
+	Issuer                                    string   `json:"issuer" example:"https://auth.example.com"`
+	AuthorizationEndpoint                     string   `json:"authorization_endpoint" example:"https://auth.example.com/oauth/authorize"`
+	TokenEndpoint                             string   `json:"token_endpoint" example:"https://auth.example.com/oauth/token"`
+	JWKSURI                                   string   `json:"jwks_uri" example:"https://auth.example.com/.well-known/jwks.json"`
+	UserInfoEndpoint                          string   `json:"userinfo_endpoint" example:"https://auth.example.com/userinfo"`
+	IntrospectionEndpoint                     string   `json:"introspection_endpoint" example:"https://auth.example.com/oauth/introspect"`
+	RevocationEndpoint                        string   `json:"revocation_endpoint" example:"https://auth.example.com/oauth/revoke"`
+	ScopesSupported                           []string `json:"scopes_supported"`
+	ResponseTypesSupported                    []string `json:"response_types_supported"`
+	GrantTypesSupported                       []string `json:"grant_types_supported"`
+	CodeChallengeMethodsSupported             []string `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethodsSupported         []string `json:"token_endpoint_auth_methods_supported"`
+	SubjectTypesSupported                     []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported          []string `json:"id_token_signing_alg_values_supported"`
+	ClaimsSupported                           []string `json:"claims_supported"`
+	IntrospectionEndpointAuthMethodsSupported []string `json:"introspection_endpoint_auth_methods_supported"`
+	RevocationEndpointAuthMethodsSupported    []string `json:"revocation_endpoint_auth_methods_supported"`
+}
+
+// NewOpenIDConfiguration creates the discovery document of an issuer whose
+// endpoints are served under the issuer URL
+func NewOpenIDConfiguration(issuer, signingAlgorithm string) *OpenIDConfiguration {
+	base := strings.TrimSuffix(issuer, "/")
+	clientAuthMethods := []string{"client_secret_basic", "client_secret_post"}
+	return &OpenIDConfiguration{
+		Issuer:                           issuer,
+		AuthorizationEndpoint:            base + "/oauth/authorize",
+		TokenEndpoint:                    base + "/oauth/token",
+		JWKSURI:                          base + "/.well-known/jwks.json",
+		UserInfoEndpoint:                 base + "/userinfo",
+		IntrospectionEndpoint:            base + "/oauth/introspect",
+		RevocationEndpoint:               base + "/oauth/revoke",
+		ScopesSupported:                  []string{"openid", "phone"},
+		ResponseTypesSupported:           []string{"code"},
+		GrantTypesSupported:              []string{"authorization_code", "refresh_token", "client_credentials"},
+		CodeChallengeMethodsSupported:    []string{"S256"},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: []string{signingAlgorithm},
+		ClaimsSupported: []string{
+			"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce",
+			"phone_number", "phone_number_verified",
+		},
+		TokenEndpointAuthMethodsSupported:         []string{"client_secret_basic", "client_secret_post", "none"}, // none for public clients using PKCE
+		IntrospectionEndpointAuthMethodsSupported: clientAuthMethods,
+		RevocationEndpointAuthMethodsSupported:    clientAuthMethods,
+	}
+}
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error   string `json:"error" example:"Invalid phone number format"`
@@ -103,3 +288,101 @@ func NewGetUsersResponse(users []*entities.User, total int64, page, limit int) *
 		TotalPages: totalPages,
 	}
 }
+
+// NewDeviceInfo creates DeviceInfo from Device entity
+func NewDeviceInfo(device *entities.Device) DeviceInfo {
+	return DeviceInfo{
+		ID:          device.ID,
+		UserID:      device.UserID,
+		DeclaredID:  device.DeclaredID,
+		Name:        device.Name,
+		UserAgent:   device.UserAgent,
+		OS:          device.OS,
+		Browser:     device.Browser,
+		AppVersion:  device.AppVersion,
+		FirstIP:     device.FirstIP,
+		LastIP:      device.LastIP,
+		FirstSeenAt: device.FirstSeenAt,
+		LastSeenAt:  device.LastSeenAt,
+	}
+}
+
+// NewDevicesResponse creates DevicesResponse with pagination
+func NewDevicesResponse(devices []*entities.Device, total int64, page, limit int) *DevicesResponse {
+	deviceInfos := make([]DeviceInfo, len(devices))
+	for i, device := range devices {
+		deviceInfos[i] = NewDeviceInfo(device)
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	return &DevicesResponse{
+		Devices:    deviceInfos,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}
+}
+
+// NewIPBanInfo creates IPBanInfo from IPBan entity
+func NewIPBanInfo(ban *entities.IPBan) IPBanInfo {
+	return IPBanInfo{
+		CIDR:      ban.Range.String(),
+		Reason:    ban.Reason,
+		Source:    ban.Source,
+		CreatedAt: ban.CreatedAt,
+		ExpiresAt: ban.ExpiresAt,
+	}
+}
+
+// NewIPBansResponse creates IPBansResponse from IPBan entities
+func NewIPBansResponse(bans []*entities.IPBan) *IPBansResponse {
+	infos := make([]IPBanInfo, len(bans))
+	for i, ban := range bans {
+		infos[i] = NewIPBanInfo(ban)
+	}
+	return &IPBansResponse{
+		Bans: infos,
+	}
+}
+
+// NewClientInfo creates ClientInfo from Client entity, without its secret
+func NewClientInfo(client *entities.Client) ClientInfo {
+	return ClientInfo{
+		ID:                     client.ID,
+		Confidential:           client.IsConfidential(),
+		Type:                   client.Type,
+		Audience:               nonNilStrings(client.Audience),
+		RedirectURIs:           nonNilStrings(client.RedirectURIs),
+		AllowedScopes:          nonNilStrings(client.AllowedScopes),
+		AllowedGrantTypes:      nonNilStrings(client.AllowedGrantTypes),
+		AccessTokenTTLSeconds:  int64(client.AccessTokenTTL / time.Second),
+		RefreshTokenTTLSeconds: int64(client.RefreshTokenTTL / time.Second),
+		CORSOrigins:            nonNilStrings(client.CORSOrigins),
+		CreatedAt:              client.CreatedAt,
+		UpdatedAt:              client.UpdatedAt,
+	}
+}
+
+// NewClientsResponse creates ClientsResponse from Client entities
+func NewClientsResponse(clients []*entities.Client) *ClientsResponse {
+	infos := make([]ClientInfo, len(clients))
+	for i, client := range clients {
+		infos[i] = NewClientInfo(client)
+	}
+	return &ClientsResponse{
+		Clients: infos,
+	}
+}
+
+// nonNilStrings returns values, or an empty slice for nil so it encodes as [] rather than null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
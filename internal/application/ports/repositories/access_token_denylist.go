@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// AccessTokenDenylist tracks access tokens revoked before their expiry.
+// Entries only need to live as long as the tokens they reject.
+type AccessTokenDenylist interface {
+	// Deny rejects a single access token, by jti, until it expires
+	Deny(ctx context.Context, tokenID string, expiresAt time.Time) error
+
+	// DenyUserTokensBefore rejects every access token issued to the user at or
+	// before t; ttl must cover the longest access token lifetime
+	DenyUserTokensBefore(ctx context.Context, userID string, t time.Time, ttl time.Duration) error
+
+	// DenySessionTokensBefore rejects every access token of the session issued
+	// at or before t; ttl must cover the longest access token lifetime
+	DenySessionTokensBefore(ctx context.Context, sessionID string, t time.Time, ttl time.Duration) error
+
+	// IsDenied checks a token against its jti and the watermarks of its user
+	// and, if it has one, its session
+	IsDenied(ctx context.Context, tokenID, userID, sessionID string, issuedAt time.Time) (bool, error)
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+// AuthorizationCodeRepository stores the one-time codes of the authorization code flow
+type AuthorizationCodeRepository interface {
+	// Save stores a code under the hash of its value
+	Save(ctx context.Context, codeHash string, code *entities.AuthorizationCode, ttl time.Duration) error
+
+	// Consume marks the code used and returns it. A code that was used before is
+	// returned with Reused set until it expires; it fails with a not found error
+	// if the code doesn't exist or expired.
+	Consume(ctx context.Context, codeHash string) (*entities.AuthorizationCode, error)
+}
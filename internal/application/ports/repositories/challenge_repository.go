@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// ChallengeRepository tracks solved challenges so each can be redeemed only once
+type ChallengeRepository interface {
+	// MarkUsed records a challenge as redeemed; it returns false if it already was
+	MarkUsed(ctx context.Context, challengeID string, ttl time.Duration) (bool, error)
+}
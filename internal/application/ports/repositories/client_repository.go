@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+// ClientReader defines read operations for clients
+type ClientReader interface {
+	// GetByID retrieves a client by ID
+	GetByID(ctx context.Context, id string) (*entities.Client, error)
+
+	// AllowsCORSOrigin reports whether any client lists origin in its CORS origins
+	AllowsCORSOrigin(ctx context.Context, origin string) (bool, error)
+}
+
+// ClientRepository defines the operations on the client registry
+type ClientRepository interface {
+	ClientReader
+
+	// List retrieves all clients ordered by ID
+	List(ctx context.Context) ([]*entities.Client, error)
+
+	// Create stores a new client; fails with a conflict if the ID is taken
+	Create(ctx context.Context, client *entities.Client) error
+
+	// Update replaces the settings and secret hash of an existing client
+	Update(ctx context.Context, client *entities.Client) error
+
+	// Delete removes a client
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+// DeviceReader defines read operations for devices
+type DeviceReader interface {
+	// GetByID retrieves a device by ID
+	GetByID(ctx context.Context, id string) (*entities.Device, error)
+
+	// GetByFingerprint retrieves one of a user's devices by its fingerprint
+	GetByFingerprint(ctx context.Context, userID, fingerprint string) (*entities.Device, error)
+
+	// List retrieves devices, most recently seen first, optionally only those of one user
+	List(ctx context.Context, userID string, offset, limit int) ([]*entities.Device, int64, error)
+}
+
+// DeviceWriter defines write operations for devices
+type DeviceWriter interface {
+	// Save creates the device or updates the user's device with the same fingerprint,
+	// setting the device's ID to the stored one
+	Save(ctx context.Context, device *entities.Device) error
+}
+
+// DeviceRepository combines read and write operations
+type DeviceRepository interface {
+	DeviceReader
+	DeviceWriter
+}
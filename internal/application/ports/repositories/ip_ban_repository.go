@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+)
+
+// IPBanReader defines read operations for IP bans
+type IPBanReader interface {
+	// List retrieves all active bans
+	List(ctx context.Context) ([]*entities.IPBan, error)
+
+	// Get retrieves the ban for an exact range
+	Get(ctx context.Context, ipRange valueobjects.IPRange) (*entities.IPBan, error)
+}
+
+// IPBanWriter defines write operations for IP bans
+type IPBanWriter interface {
+	// Add stores a ban, keeping an existing ban for the same range if it
+	// expires later. ban is updated to the ban in effect.
+	Add(ctx context.Context, ban *entities.IPBan) error
+
+	// Remove deletes the ban for a range
+	Remove(ctx context.Context, ipRange valueobjects.IPRange) error
+}
+
+// IPBanRepository combines read and write operations
+type IPBanRepository interface {
+	IPBanReader
+	IPBanWriter
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// RevokeLinkRepository stores one-time links that let a user end a session
+// from a new-login notification
+type RevokeLinkRepository interface {
+	// Save stores a link for the session under the hash of its token
+	Save(ctx context.Context, tokenHash, userID, sessionID string, ttl time.Duration) error
+
+	// Consume deletes the link and returns the session it was for; it fails
+	// with a not found error if the link doesn't exist or was already used
+	Consume(ctx context.Context, tokenHash string) (userID, sessionID string, err error)
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+// SigningKeyReader defines read operations for the signing key ring
+type SigningKeyReader interface {
+	// List retrieves every key in the ring
+	List(ctx context.Context) ([]*entities.SigningKey, error)
+}
+
+// SigningKeyWriter defines write operations for the signing key ring
+type SigningKeyWriter interface {
+	// Save stores a key, replacing any existing key with the same ID
+	Save(ctx context.Context, key *entities.SigningKey) error
+
+	// Delete removes a key from the ring
+	Delete(ctx context.Context, id string) error
+}
+
+// SigningKeyRepository combines read and write operations
+type SigningKeyRepository interface {
+	SigningKeyReader
+	SigningKeyWriter
+
+	// WithLock runs fn while holding an exclusive lock on the ring, so replicas
+	// sharing the store don't rotate at the same time. Reads and writes through
+	// fn's context are part of the locked update
+	WithLock(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -15,6 +15,10 @@ type TokenReader interface {
 	// GetByTokenHashAndSessionID retrieves a refresh token by token hash and session ID
 	GetByTokenHashAndSessionID(ctx context.Context, tokenHash string, sessionID valueobjects.SessionID) (*entities.RefreshToken, error)
 	
+	// GetByTokenHashAndSessionIDForUpdate is GetByTokenHashAndSessionID that also locks
+	// the token until the surrounding unit of work ends
+	GetByTokenHashAndSessionIDForUpdate(ctx context.Context, tokenHash string, sessionID valueobjects.SessionID) (*entities.RefreshToken, error)
+	
 	// GetByUserID retrieves all refresh tokens for a user
 	GetByUserID(ctx context.Context, userID string) ([]*entities.RefreshToken, error)
 	
@@ -39,6 +43,16 @@ type TokenWriter interface {
 	// RevokeAllByUserID revokes all refresh tokens for a user
 	RevokeAllByUserID(ctx context.Context, userID string, reason string) error
 	
+	// RevokeSession revokes the active refresh tokens of one of a user's sessions
+	RevokeSession(ctx context.Context, userID string, sessionID string, reason string) error
+	
+	// RevokeOtherSessions revokes the refresh tokens of every session of a user but one
+	RevokeOtherSessions(ctx context.Context, userID string, keepSessionID string, reason string) error
+	
+	// RevokeFamily revokes the active refresh tokens of a token family. It fails
+	// with NotFoundError when none is left
+	RevokeFamily(ctx context.Context, familyID string, reason string) error
+	
 	// Delete deletes a refresh token by ID
 	Delete(ctx context.Context, id string) error
 	
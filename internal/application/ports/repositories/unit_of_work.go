@@ -0,0 +1,11 @@
+package repositories
+
+import "context"
+
+// UnitOfWork runs several repository calls atomically
+type UnitOfWork interface {
+	// Do runs fn in a transaction. Repository calls made with the context passed
+	// to fn take part in it; the transaction commits if fn returns nil and rolls
+	// back otherwise. Nested calls join the outer transaction.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
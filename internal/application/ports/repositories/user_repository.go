@@ -12,6 +12,10 @@ type UserReader interface {
 	// GetByID retrieves a user by ID
 	GetByID(ctx context.Context, id string) (*entities.User, error)
 	
+	// GetByIDForUpdate retrieves a user by ID and locks its row until the
+	// surrounding unit of work ends
+	GetByIDForUpdate(ctx context.Context, id string) (*entities.User, error)
+	
 	// GetByPhoneNumber retrieves a user by phone number
 	GetByPhoneNumber(ctx context.Context, phoneNumber valueobjects.PhoneNumber) (*entities.User, error)
 	
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// Challenge types
+const (
+	ChallengeTypeProofOfWork = "pow"
+	ChallengeTypeCaptcha     = "captcha"
+)
+
+// Challenge represents a challenge the client must solve before an OTP is sent
+type Challenge struct {
+	Type       string    // pow, captcha
+	Token      string    // Opaque challenge token (proof-of-work only)
+	Difficulty int       // Required leading zero bits (proof-of-work only)
+	SiteKey    string    // Public site key (captcha only)
+	ExpiresAt  time.Time // Zero when the challenge doesn't expire
+}
+
+// ChallengeSolution represents the client's answer to a challenge
+type ChallengeSolution struct {
+	Token string // Proof-of-work challenge token or captcha response token
+	Nonce string // Proof-of-work nonce (unused for captcha)
+}
+
+// ChallengeVerifier defines the interface for issuing and verifying anti-abuse challenges
+type ChallengeVerifier interface {
+	// Type returns the challenge type handled by this verifier
+	Type() string
+
+	// Issue creates a new challenge for the client to solve
+	Issue(ctx context.Context) (*Challenge, error)
+
+	// Verify checks the client's solution, returning an error if it is invalid
+	Verify(ctx context.Context, solution *ChallengeSolution, remoteIP string) error
+}
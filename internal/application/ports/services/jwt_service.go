@@ -1,18 +1,25 @@
 package services
 
 import (
+	"strings"
 	"time"
 )
 
+// ClientSubjectPrefix starts the subject of tokens issued to a client for itself,
+// e.g. "client:api-gateway"; user subjects are UUIDs
+const ClientSubjectPrefix = "client:"
+
 // JWTClaims represents the structure of JWT claims
 type JWTClaims struct {
-	Subject   string   `json:"sub"`       // User ID
-	ClientID  string   `json:"client_id"` // Client identifier
-	Scopes    []string `json:"scopes"`    // User scopes/permissions
-	IssuedAt  int64    `json:"iat"`       // Issued at timestamp
-	ExpiresAt int64    `json:"exp"`       // Expiration timestamp
-	Issuer    string   `json:"iss"`       // Token issuer
-	TokenID   string   `json:"jti"`       // JWT ID (unique token identifier)
+	Subject   string   `json:"sub"`           // User ID, or ClientSubjectPrefix and the client ID
+	ClientID  string   `json:"client_id"`     // Client identifier
+	SessionID string   `json:"sid,omitempty"` // Session of user tokens, for revoking them with it
+	Scopes    []string `json:"scopes"`        // User scopes/permissions
+	IssuedAt  int64    `json:"iat"`           // Issued at timestamp
+	ExpiresAt int64    `json:"exp"`           // Expiration timestamp
+	Issuer    string   `json:"iss"`           // Token issuer
+	TokenID   string   `json:"jti"`           // JWT ID (unique token identifier)
+	Audience  []string `json:"aud"`           // Intended recipients of the token
 }
 
 // NewJWTClaims creates new JWT claims with the given parameters
@@ -29,16 +36,75 @@ func NewJWTClaims(userID, clientID string, scopes []string, ttl time.Duration, i
 	}
 }
 
+// NewClientJWTClaims creates JWT claims for a client acting on its own behalf
+func NewClientJWTClaims(clientID string, scopes []string, ttl time.Duration, issuer, tokenID string) *JWTClaims {
+	return NewJWTClaims(ClientSubjectPrefix+clientID, clientID, scopes, ttl, issuer, tokenID)
+}
+
+// IsClient reports whether the token was issued to a client for itself rather
+// than to a user
+func (c *JWTClaims) IsClient() bool {
+	return strings.HasPrefix(c.Subject, ClientSubjectPrefix)
+}
+
 // IsExpired checks if the token has expired
 func (c *JWTClaims) IsExpired() bool {
 	return time.Now().Unix() > c.ExpiresAt
 }
 
+// IDTokenClaims represents the claims of an OpenID Connect ID token
+type IDTokenClaims struct {
+	Issuer              string   `json:"iss"`                             // OIDC issuer URL
+	Subject             string   `json:"sub"`                             // User ID
+	Audience            []string `json:"aud"`                             // Client the token was issued to
+	IssuedAt            int64    `json:"iat"`                             // Issued at timestamp
+	ExpiresAt           int64    `json:"exp"`                             // Expiration timestamp
+	AuthTime            int64    `json:"auth_time"`                       // When the user entered the OTP
+	Nonce               string   `json:"nonce,omitempty"`                 // Echoed from the authentication request
+	PhoneNumber         string   `json:"phone_number,omitempty"`          // E.164 phone number
+	PhoneNumberVerified bool     `json:"phone_number_verified,omitempty"` // Proven by the OTP
+}
+
+// VerifyOptions restricts which tokens VerifyToken accepts
+type VerifyOptions struct {
+	Issuer   string // Required "iss", if set
+	Audience string // Required entry in "aud", if set
+}
+
+// VerifyOption configures VerifyOptions
+type VerifyOption func(*VerifyOptions)
+
+// WithIssuer rejects tokens not issued by issuer
+func WithIssuer(issuer string) VerifyOption {
+	return func(o *VerifyOptions) {
+		o.Issuer = issuer
+	}
+}
+
+// WithAudience rejects tokens not intended for audience
+func WithAudience(audience string) VerifyOption {
+	return func(o *VerifyOptions) {
+		o.Audience = audience
+	}
+}
+
+// NewVerifyOptions applies opts to empty VerifyOptions
+func NewVerifyOptions(opts ...VerifyOption) VerifyOptions {
+	var options VerifyOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
 // JWTService defines the interface for JWT token operations
 type JWTService interface {
 	// GenerateToken generates a JWT token from claims
 	GenerateToken(claims *JWTClaims) (string, error)
 	
 	// VerifyToken verifies and parses a JWT token, returning the claims
-	VerifyToken(token string) (*JWTClaims, error)
+	VerifyToken(token string, opts ...VerifyOption) (*JWTClaims, error)
+
+	// GenerateIDToken signs an OpenID Connect ID token with the same key as access tokens
+	GenerateIDToken(claims *IDTokenClaims) (string, error)
 }
\ No newline at end of file
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+// JSONWebKey is the public part of a signing key as published in a JWKS (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`           // Key type (EC, RSA, OKP)
+	Use string `json:"use,omitempty"` // Public key use, always "sig" here
+	Alg string `json:"alg,omitempty"` // Signing algorithm (ES256, RS256, EdDSA...)
+	Kid string `json:"kid"`           // Key ID, matches the "kid" header of issued tokens
+	Crv string `json:"crv,omitempty"` // Curve (EC and OKP keys)
+	X   string `json:"x,omitempty"`   // X coordinate (EC) or public key (OKP)
+	Y   string `json:"y,omitempty"`   // Y coordinate (EC)
+	N   string `json:"n,omitempty"`   // Modulus (RSA)
+	E   string `json:"e,omitempty"`   // Exponent (RSA)
+}
+
+// JSONWebKeySet is the document served at /.well-known/jwks.json
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// KeySetProvider exposes the public keys tokens can be verified with
+type KeySetProvider interface {
+	// KeySet returns every public key currently accepted for verification
+	KeySet() (*JSONWebKeySet, error)
+}
+
+// SigningKeyGenerator creates new key pairs for the signing key ring
+type SigningKeyGenerator interface {
+	// GenerateSigningKey returns a new key with its ID and PEM-encoded halves set
+	GenerateSigningKey() (*entities.SigningKey, error)
+}
+
+// SigningKeyLoader is implemented by JWT services whose keys can be swapped at runtime
+type SigningKeyLoader interface {
+	// LoadSigningKeys signs with the active key and verifies with all of them
+	LoadSigningKeys(keys []*entities.SigningKey) error
+}
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/domain/valueobjects"
+)
+
+// NotificationSender defines the interface for sending account notifications to users
+type NotificationSender interface {
+	// SendNotification sends a text message to the specified phone number
+	SendNotification(ctx context.Context, phoneNumber valueobjects.PhoneNumber, message string) error
+}
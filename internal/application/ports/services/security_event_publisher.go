@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// SecurityEventType identifies the kind of security event
+type SecurityEventType string
+
+const (
+	// SecurityEventRefreshTokenReuse is raised when a rotated refresh token is presented again
+	SecurityEventRefreshTokenReuse SecurityEventType = "refresh_token_reuse"
+
+	// SecurityEventLoginDisowned is raised when a user revokes a session from a new-login notification
+	SecurityEventLoginDisowned SecurityEventType = "login_disowned"
+
+	// SecurityEventAuthorizationCodeReuse is raised when an authorization code is exchanged again
+	SecurityEventAuthorizationCodeReuse SecurityEventType = "authorization_code_reuse"
+)
+
+// SecurityEvent describes something security teams should know about
+type SecurityEvent struct {
+	Type       SecurityEventType
+	UserID     string
+	SessionID  string
+	ClientID   string
+	Details    map[string]string
+	OccurredAt time.Time
+}
+
+// SecurityEventPublisher defines the interface for raising security events
+type SecurityEventPublisher interface {
+	// Publish records or forwards a security event
+	Publish(ctx context.Context, event SecurityEvent) error
+}
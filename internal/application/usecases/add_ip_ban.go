@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// AddIPBanUseCase handles manually banning an IP or CIDR block
+type AddIPBanUseCase struct {
+	banRepo repositories.IPBanRepository
+}
+
+// NewAddIPBanUseCase creates a new AddIPBanUseCase
+func NewAddIPBanUseCase(banRepo repositories.IPBanRepository) *AddIPBanUseCase {
+	return &AddIPBanUseCase{
+		banRepo: banRepo,
+	}
+}
+
+// Execute bans the requested range
+func (uc *AddIPBanUseCase) Execute(ctx context.Context, req *dto.AddIPBanRequest) (*dto.IPBanInfo, error) {
+	ipRange, err := valueobjects.NewIPRange(req.CIDR)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid IP address or CIDR block", err)
+	}
+
+	if req.DurationSeconds < 0 {
+		return nil, errors.NewValidationError("Ban duration cannot be negative", nil)
+	}
+
+	ban := entities.NewIPBan(ipRange, req.Reason, entities.IPBanSourceManual, time.Duration(req.DurationSeconds)*time.Second)
+	if err := uc.banRepo.Add(ctx, ban); err != nil {
+		return nil, err
+	}
+
+	info := dto.NewIPBanInfo(ban)
+	return &info, nil
+}
@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// Authorization code flow parameters
+const (
+	ResponseTypeCode        = "code"
+	CodeChallengeMethodS256 = "S256"
+)
+
+// authorizationScopes are the scopes the authorization endpoint can grant
+var authorizationScopes = map[string]bool{"openid": true, "phone": true}
+
+// AuthorizationError is an error the authorization endpoint reports to the client
+// by redirecting the user back to it (RFC 6749 section 4.1.2.1). The token endpoint
+// sends the same codes in its error responses (section 5.2).
+type AuthorizationError struct {
+	Code        string // e.g. invalid_request, invalid_scope
+	Description string
+}
+
+// Error implements the error interface
+func (e *AuthorizationError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// AuthorizeUseCase checks authorization requests before and while the hosted login
+// page is shown
+type AuthorizeUseCase struct {
+	clients repositories.ClientReader
+}
+
+// NewAuthorizeUseCase creates a new AuthorizeUseCase
+func NewAuthorizeUseCase(clientRepo repositories.ClientReader) *AuthorizeUseCase {
+	return &AuthorizeUseCase{
+		clients: clientRepo,
+	}
+}
+
+// Execute returns the client of a valid request. An unknown client or redirect URI
+// is a validation error to show to the user, as there is nowhere safe to redirect
+// to; every other problem is an *AuthorizationError.
+func (uc *AuthorizeUseCase) Execute(ctx context.Context, req *dto.AuthorizationRequest) (*entities.Client, error) {
+	if req.ClientID == "" {
+		return nil, errors.NewValidationError("Client ID is required", nil)
+	}
+
+	client, err := uc.clients.GetByID(ctx, req.ClientID)
+	if err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.NotFoundError {
+			return nil, errors.NewValidationError("Unknown client", nil)
+		}
+		return nil, err
+	}
+
+	if !client.AllowsRedirectURI(req.RedirectURI) {
+		return nil, errors.NewValidationError("Redirect URI is not registered for this client", nil)
+	}
+
+	if req.ResponseType != ResponseTypeCode {
+		return nil, &AuthorizationError{Code: "unsupported_response_type", Description: "Only the code response type is supported"}
+	}
+	if !client.AllowsGrantType(GrantTypeAuthorizationCode) {
+		return nil, &AuthorizationError{Code: "unauthorized_client", Description: "Client is not allowed to use the authorization code grant"}
+	}
+
+	// PKCE is required of every client, confidential ones included
+	if req.CodeChallengeMethod != CodeChallengeMethodS256 {
+		return nil, &AuthorizationError{Code: "invalid_request", Description: "code_challenge_method must be S256"}
+	}
+	if !isPKCEValue(req.CodeChallenge) {
+		return nil, &AuthorizationError{Code: "invalid_request", Description: "code_challenge is missing or malformed"}
+	}
+
+	for _, scope := range strings.Fields(req.Scope) {
+		if !authorizationScopes[scope] {
+			return nil, &AuthorizationError{Code: "invalid_scope", Description: "Unsupported scope " + scope}
+		}
+		if !client.AllowsScope(scope) {
+			return nil, &AuthorizationError{Code: "invalid_scope", Description: "Client is not allowed to request scope " + scope}
+		}
+	}
+
+	return client, nil
+}
+
+// isPKCEValue reports whether value is a well-formed code verifier, or a code
+// challenge, which is the 43 character encoding of a SHA-256 hash (RFC 7636 section 4.1)
+func isPKCEValue(value string) bool {
+	if len(value) < 43 || len(value) > 128 {
+		return false
+	}
+	for _, r := range value {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case r == '-', r == '.', r == '_', r == '~':
+		default:
+			return false
+		}
+	}
+	return true
+}
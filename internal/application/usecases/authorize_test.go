@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+const testCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+func newAuthorizationRequest() *dto.AuthorizationRequest {
+	return &dto.AuthorizationRequest{
+		ResponseType:        ResponseTypeCode,
+		ClientID:            "partner-app",
+		RedirectURI:         "https://partner.example.com/callback",
+		Scope:               "openid phone",
+		State:               "xyz",
+		CodeChallenge:       testCodeChallenge,
+		CodeChallengeMethod: CodeChallengeMethodS256,
+	}
+}
+
+func TestAuthorizeUseCase(t *testing.T) {
+	partner := entities.NewClient("partner-app")
+	partner.RedirectURIs = []string{"https://partner.example.com/callback"}
+	openIDOnly := entities.NewClient("openid-app")
+	openIDOnly.RedirectURIs = partner.RedirectURIs
+	openIDOnly.AllowedScopes = []string{"openid"}
+	noCodeGrant := entities.NewClient("service")
+	noCodeGrant.RedirectURIs = partner.RedirectURIs
+	noCodeGrant.AllowedGrantTypes = nil
+	uc := NewAuthorizeUseCase(&staticClientRepo{clients: map[string]*entities.Client{
+		partner.ID: partner, openIDOnly.ID: openIDOnly, noCodeGrant.ID: noCodeGrant,
+	}})
+
+	tests := []struct {
+		name     string
+		modify   func(*dto.AuthorizationRequest)
+		wantPage bool   // validation error shown to the user
+		wantCode string // error sent back to the client
+	}{
+		{"valid", func(*dto.AuthorizationRequest) {}, false, ""},
+		{"unknown client", func(r *dto.AuthorizationRequest) { r.ClientID = "other-app" }, true, ""},
+		{"unregistered redirect URI", func(r *dto.AuthorizationRequest) { r.RedirectURI = "https://evil.example.com/callback" }, true, ""},
+		{"token response type", func(r *dto.AuthorizationRequest) { r.ResponseType = "token" }, false, "unsupported_response_type"},
+		{"plain PKCE", func(r *dto.AuthorizationRequest) { r.CodeChallengeMethod = "plain" }, false, "invalid_request"},
+		{"missing challenge", func(r *dto.AuthorizationRequest) { r.CodeChallenge = "" }, false, "invalid_request"},
+		{"unknown scope", func(r *dto.AuthorizationRequest) { r.Scope = "openid email" }, false, "invalid_scope"},
+		{"scope not allowed for the client", func(r *dto.AuthorizationRequest) { r.ClientID = "openid-app" }, false, "invalid_scope"},
+		{"grant not allowed for the client", func(r *dto.AuthorizationRequest) { r.ClientID = "service" }, false, "unauthorized_client"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newAuthorizationRequest()
+			tt.modify(req)
+			_, err := uc.Execute(context.Background(), req)
+
+			customErr := errors.GetCustomError(err)
+			if gotPage := customErr != nil && customErr.Type == errors.ValidationError; gotPage != tt.wantPage {
+				t.Errorf("error = %v, want validation error %v", err, tt.wantPage)
+			}
+			var authErr *AuthorizationError
+			gotCode := ""
+			if stderrors.As(err, &authErr) {
+				gotCode = authErr.Code
+			}
+			if gotCode != tt.wantCode {
+				t.Errorf("redirect error = %q, want %q", gotCode, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// clientAuthenticator checks the credentials of confidential clients calling
+// the OAuth endpoints
+type clientAuthenticator struct {
+	clients     repositories.ClientReader
+	hashService services.HashService
+}
+
+// authenticate returns the client if the secret matches; unknown clients,
+// public clients and wrong secrets all get the same unauthorized error
+func (a clientAuthenticator) authenticate(ctx context.Context, clientID, clientSecret string) (*entities.Client, error) {
+	invalid := errors.NewUnauthorizedError("Invalid client credentials", nil)
+	if clientID == "" || clientSecret == "" {
+		return nil, invalid
+	}
+
+	client, err := a.clients.GetByID(ctx, clientID)
+	if err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.NotFoundError {
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	if !client.IsConfidential() || a.hashService.VerifyPassword(clientSecret, client.SecretHash) != nil {
+		return nil, invalid
+	}
+	return client, nil
+}
+
+// identify returns the client of a token request: confidential clients must
+// authenticate, public clients only name themselves and send no secret
+func (a clientAuthenticator) identify(ctx context.Context, clientID, clientSecret string) (*entities.Client, error) {
+	if clientSecret != "" {
+		return a.authenticate(ctx, clientID, clientSecret)
+	}
+
+	invalid := errors.NewUnauthorizedError("Invalid client credentials", nil)
+	if clientID == "" {
+		return nil, invalid
+	}
+
+	client, err := a.clients.GetByID(ctx, clientID)
+	if err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.NotFoundError {
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	if client.IsConfidential() {
+		return nil, invalid
+	}
+	return client, nil
+}
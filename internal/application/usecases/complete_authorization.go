@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// CompleteAuthorizationUseCase authenticates the user on the hosted login page and
+// issues an authorization code the client exchanges for a new session's tokens
+type CompleteAuthorizationUseCase struct {
+	authorize   *AuthorizeUseCase
+	login       *LoginUseCase
+	codeRepo    repositories.AuthorizationCodeRepository
+	hashService services.HashService
+	codeTTL     time.Duration
+}
+
+// NewCompleteAuthorizationUseCase creates a new CompleteAuthorizationUseCase
+func NewCompleteAuthorizationUseCase(
+	authorize *AuthorizeUseCase,
+	login *LoginUseCase,
+	codeRepo repositories.AuthorizationCodeRepository,
+	hashService services.HashService,
+	codeTTL time.Duration,
+) *CompleteAuthorizationUseCase {
+	return &CompleteAuthorizationUseCase{
+		authorize:   authorize,
+		login:       login,
+		codeRepo:    codeRepo,
+		hashService: hashService,
+		codeTTL:     codeTTL,
+	}
+}
+
+// Execute checks the authorization request again, authenticates the user and
+// returns the client's redirect URI with the code and state added
+func (uc *CompleteAuthorizationUseCase) Execute(ctx context.Context, authReq *dto.AuthorizationRequest, loginReq *dto.LoginRequest, sessionID string) (string, error) {
+	client, err := uc.authorize.Execute(ctx, authReq)
+	if err != nil {
+		return "", err
+	}
+
+	// No session starts and no tokens exist until the code is exchanged
+	user, sessionIDObj, err := uc.login.authenticate(ctx, loginReq, sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	code, err := uc.hashService.GenerateRandomString(32)
+	if err != nil {
+		return "", errors.NewInternalError("Failed to generate authorization code", err)
+	}
+	codeHash, err := uc.hashService.HashRefreshToken(code)
+	if err != nil {
+		return "", errors.NewInternalError("Failed to hash authorization code", err)
+	}
+
+	if err := uc.codeRepo.Save(ctx, codeHash, &entities.AuthorizationCode{
+		ClientID:       client.ID,
+		RedirectURI:    authReq.RedirectURI,
+		CodeChallenge:  authReq.CodeChallenge,
+		Scope:          strings.Join(strings.Fields(authReq.Scope), " "),
+		UserID:         user.ID,
+		SessionID:      sessionIDObj.String(),
+		Nonce:          authReq.Nonce,
+		AuthTime:       time.Now(),
+		UserAgent:      loginReq.UserAgent,
+		IPAddress:      loginReq.IPAddress,
+		AcceptLanguage: loginReq.AcceptLanguage,
+	}, uc.codeTTL); err != nil {
+		return "", err
+	}
+
+	params := url.Values{"code": {code}}
+	if authReq.State != "" {
+		params.Set("state", authReq.State)
+	}
+	return AuthorizationRedirectURI(authReq.RedirectURI, params), nil
+}
+
+// AuthorizationRedirectURI adds params to a registered redirect URI, keeping its
+// own query parameters
+func AuthorizationRedirectURI(redirectURI string, params url.Values) string {
+	separator := "?"
+	if strings.Contains(redirectURI, "?") {
+		separator = "&"
+	}
+	return redirectURI + separator + params.Encode()
+}
+
+// hasScope reports whether the space separated scope list contains scope
+func hasScope(scopes, scope string) bool {
+	for _, s := range strings.Fields(scopes) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// clientGrantTypes are the OAuth grant types clients can be allowed to use
+var clientGrantTypes = map[string]bool{GrantTypeAuthorizationCode: true, GrantTypeClientCredentials: true, GrantTypeFirstParty: true}
+
+// CreateClientUseCase handles registering clients
+type CreateClientUseCase struct {
+	clientRepo   repositories.ClientRepository
+	hashService  services.HashService
+	sessions     SessionPolicies
+	maxAccessTTL time.Duration
+}
+
+// NewCreateClientUseCase creates a new CreateClientUseCase. Client types must have a
+// policy in sessions, and access tokens may not outlive maxAccessTTL, which token
+// revocation and signing key retirement rely on.
+func NewCreateClientUseCase(clientRepo repositories.ClientRepository, hashService services.HashService, sessions SessionPolicies, maxAccessTTL time.Duration) *CreateClientUseCase {
+	return &CreateClientUseCase{
+		clientRepo:   clientRepo,
+		hashService:  hashService,
+		sessions:     sessions,
+		maxAccessTTL: maxAccessTTL,
+	}
+}
+
+// Execute registers a client. The secret of a confidential client is only ever
+// returned here, in plain text.
+func (uc *CreateClientUseCase) Execute(ctx context.Context, req *dto.CreateClientRequest) (*dto.ClientInfo, error) {
+	if !isClientID(req.ID) {
+		return nil, errors.NewValidationError("Client ID must be 1-100 letters, digits, dots, dashes or underscores", nil)
+	}
+
+	client := entities.NewClient(req.ID)
+	if err := applyClientSettings(client, &req.ClientSettings, uc.sessions, uc.maxAccessTTL); err != nil {
+		return nil, err
+	}
+
+	var secret string
+	if req.Confidential {
+		var err error
+		if secret, err = issueClientSecret(client, uc.hashService); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.clientRepo.Create(ctx, client); err != nil {
+		return nil, err
+	}
+
+	info := dto.NewClientInfo(client)
+	info.ClientSecret = secret
+	return &info, nil
+}
+
+// applyClientSettings validates settings and copies them onto client. Omitted
+// allowed scopes and grant types get the defaults of entities.NewClient.
+func applyClientSettings(client *entities.Client, settings *dto.ClientSettings, sessions SessionPolicies, maxAccessTTL time.Duration) error {
+	if settings.Type != "" {
+		if _, ok := sessions.ByType[settings.Type]; !ok {
+			return errors.NewValidationError(fmt.Sprintf("Client type %s has no session policy", settings.Type), nil)
+		}
+	}
+	for _, audience := range settings.Audience {
+		if strings.TrimSpace(audience) == "" {
+			return errors.NewValidationError("Audiences must not be empty", nil)
+		}
+	}
+	for _, redirectURI := range settings.RedirectURIs {
+		parsed, err := url.Parse(redirectURI)
+		if err != nil || !parsed.IsAbs() || parsed.Fragment != "" {
+			return errors.NewValidationError(fmt.Sprintf("Redirect URI %s must be an absolute URL without fragment", redirectURI), err)
+		}
+	}
+	for _, scope := range settings.AllowedScopes {
+		if scope == "" || strings.ContainsAny(scope, " \t\r\n\"\\") {
+			return errors.NewValidationError(fmt.Sprintf("Invalid scope %q", scope), nil)
+		}
+	}
+	for _, grantType := range settings.AllowedGrantTypes {
+		if !clientGrantTypes[grantType] {
+			return errors.NewValidationError(fmt.Sprintf("Unsupported grant type %s", grantType), nil)
+		}
+	}
+	if settings.AccessTokenTTLSeconds < 0 || settings.RefreshTokenTTLSeconds < 0 {
+		return errors.NewValidationError("Token TTLs must not be negative", nil)
+	}
+	if time.Duration(settings.AccessTokenTTLSeconds)*time.Second > maxAccessTTL {
+		return errors.NewValidationError(fmt.Sprintf("Access token TTL must not exceed %v", maxAccessTTL), nil)
+	}
+	for _, origin := range settings.CORSOrigins {
+		if !isOrigin(origin) {
+			return errors.NewValidationError(fmt.Sprintf("CORS origin %s must be a scheme and host, like https://app.example.com", origin), nil)
+		}
+	}
+
+	defaults := entities.NewClient(client.ID)
+	client.Type = settings.Type
+	client.Audience = settings.Audience
+	client.RedirectURIs = settings.RedirectURIs
+	client.AllowedScopes = settings.AllowedScopes
+	if client.AllowedScopes == nil {
+		client.AllowedScopes = defaults.AllowedScopes
+	}
+	client.AllowedGrantTypes = settings.AllowedGrantTypes
+	if client.AllowedGrantTypes == nil {
+		client.AllowedGrantTypes = defaults.AllowedGrantTypes
+	}
+	client.AccessTokenTTL = time.Duration(settings.AccessTokenTTLSeconds) * time.Second
+	client.RefreshTokenTTL = time.Duration(settings.RefreshTokenTTLSeconds) * time.Second
+	client.CORSOrigins = settings.CORSOrigins
+	return nil
+}
+
+// issueClientSecret gives client a new random secret, storing only its hash, and
+// returns the secret
+func issueClientSecret(client *entities.Client, hashService services.HashService) (string, error) {
+	secret, err := hashService.GenerateRandomString(40)
+	if err != nil {
+		return "", errors.NewInternalError("Failed to generate client secret", err)
+	}
+	secretHash, err := hashService.HashPassword(secret)
+	if err != nil {
+		return "", errors.NewInternalError("Failed to hash client secret", err)
+	}
+	client.SecretHash = secretHash
+	return secret, nil
+}
+
+// isClientID reports whether id is a well-formed client ID
+func isClientID(id string) bool {
+	if id == "" || len(id) > 100 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case r == '-', r == '.', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isOrigin reports whether origin is a browser origin: an http(s) scheme and a host,
+// without path, query or fragment
+func isOrigin(origin string) bool {
+	parsed, err := url.Parse(origin)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return false
+	}
+	return parsed.Scheme+"://"+parsed.Host == origin
+}
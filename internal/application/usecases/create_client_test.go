@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+var testSessionPolicies = SessionPolicies{ByType: map[string]SessionPolicy{"web": {}, "mobile": {}}}
+
+func TestCreateClientUseCase(t *testing.T) {
+	clients := &staticClientRepo{clients: map[string]*entities.Client{}}
+	uc := NewCreateClientUseCase(clients, &fakeHashService{}, testSessionPolicies, time.Hour)
+
+	info, err := uc.Execute(context.Background(), &dto.CreateClientRequest{
+		ID:           "partner-app",
+		Confidential: true,
+		ClientSettings: dto.ClientSettings{
+			Type:                  "mobile",
+			RedirectURIs:          []string{"https://partner.example.com/callback"},
+			AccessTokenTTLSeconds: 300,
+			CORSOrigins:           []string{"https://partner.example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if info.ClientSecret == "" || !info.Confidential {
+		t.Errorf("info = %+v, want a confidential client with its secret", info)
+	}
+
+	stored := clients.clients["partner-app"]
+	if stored.SecretHash != "hash:"+info.ClientSecret {
+		t.Errorf("stored secret hash = %q, want the hash of the returned secret", stored.SecretHash)
+	}
+	if !stored.AllowsGrantType(GrantTypeAuthorizationCode) || !stored.AllowsScope("openid") || !stored.AllowsScope("phone") {
+		t.Errorf("stored grants %v and scopes %v, want the defaults", stored.AllowedGrantTypes, stored.AllowedScopes)
+	}
+	if stored.AccessTokenTTL != 5*time.Minute || stored.Type != "mobile" {
+		t.Errorf("stored access TTL %v and type %q", stored.AccessTokenTTL, stored.Type)
+	}
+
+	_, err = uc.Execute(context.Background(), &dto.CreateClientRequest{ID: "partner-app"})
+	if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.ConflictError {
+		t.Errorf("duplicate create error = %v, want conflict", err)
+	}
+}
+
+func TestCreateClientUseCaseValidatesSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		req  dto.CreateClientRequest
+	}{
+		{"malformed ID", dto.CreateClientRequest{ID: "partner app"}},
+		{"type without session policy", dto.CreateClientRequest{ID: "a", ClientSettings: dto.ClientSettings{Type: "tv"}}},
+		{"relative redirect URI", dto.CreateClientRequest{ID: "a", ClientSettings: dto.ClientSettings{RedirectURIs: []string{"/callback"}}}},
+		{"unsupported grant type", dto.CreateClientRequest{ID: "a", ClientSettings: dto.ClientSettings{AllowedGrantTypes: []string{"password"}}}},
+		{"scope with a space", dto.CreateClientRequest{ID: "a", ClientSettings: dto.ClientSettings{AllowedScopes: []string{"openid phone"}}}},
+		{"access TTL over the service's", dto.CreateClientRequest{ID: "a", ClientSettings: dto.ClientSettings{AccessTokenTTLSeconds: 7200}}},
+		{"negative TTL", dto.CreateClientRequest{ID: "a", ClientSettings: dto.ClientSettings{RefreshTokenTTLSeconds: -1}}},
+		{"origin with path", dto.CreateClientRequest{ID: "a", ClientSettings: dto.ClientSettings{CORSOrigins: []string{"https://app.example.com/"}}}},
+		{"wildcard origin", dto.CreateClientRequest{ID: "a", ClientSettings: dto.ClientSettings{CORSOrigins: []string{"*"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients := &staticClientRepo{clients: map[string]*entities.Client{}}
+			uc := NewCreateClientUseCase(clients, &fakeHashService{}, testSessionPolicies, time.Hour)
+
+			_, err := uc.Execute(context.Background(), &tt.req)
+			if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.ValidationError {
+				t.Errorf("error = %v, want a validation error", err)
+			}
+			if len(clients.clients) != 0 {
+				t.Error("invalid client was stored")
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+)
+
+// DeleteClientUseCase handles removing clients from the registry
+type DeleteClientUseCase struct {
+	clientRepo repositories.ClientRepository
+}
+
+// NewDeleteClientUseCase creates a new DeleteClientUseCase
+func NewDeleteClientUseCase(clientRepo repositories.ClientRepository) *DeleteClientUseCase {
+	return &DeleteClientUseCase{
+		clientRepo: clientRepo,
+	}
+}
+
+// Execute removes a client. Its sessions can no longer be refreshed, but access
+// tokens already issued stay valid until they expire.
+func (uc *DeleteClientUseCase) Execute(ctx context.Context, clientID string) (*dto.SuccessResponse, error) {
+	if err := uc.clientRepo.Delete(ctx, clientID); err != nil {
+		return nil, err
+	}
+
+	return &dto.SuccessResponse{
+		Message: "Client deleted",
+	}, nil
+}
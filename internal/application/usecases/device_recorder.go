@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"net"
+
+	"github.com/google/uuid"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// deviceRecorder keeps the devices table up to date on login and refresh.
+// Failures are logged and never fail the login or refresh itself.
+type deviceRecorder struct {
+	repo repositories.DeviceRepository
+}
+
+// maxKnownDevices bounds how many of a user's devices are compared against a login's network
+const maxKnownDevices = 100
+
+// loginSighting describes how familiar the device and network of a login are
+type loginSighting struct {
+	device     *entities.Device // nil if the device couldn't be recorded
+	newDevice  bool
+	newNetwork bool // none of the user's devices was seen in the login IP's network before
+	firstLogin bool // the user had no recorded devices yet
+}
+
+// unfamiliar reports whether the user should be told about the login
+func (s loginSighting) unfamiliar() bool {
+	// A first login has nothing to compare against
+	return s.device != nil && !s.firstLogin && (s.newDevice || s.newNetwork)
+}
+
+// recordLogin stores the device a login came from and reports whether it or its network is new
+func (r deviceRecorder) recordLogin(ctx context.Context, userID, declaredID, name, userAgent, ipAddress, appVersion string) loginSighting {
+	// Compare against the devices as they were before this login
+	known, _, err := r.repo.List(ctx, userID, 0, maxKnownDevices)
+	if err != nil {
+		log.Printf("[WARN] failed to list devices of user %s: %v", userID, err)
+		return loginSighting{}
+	}
+
+	sighting := loginSighting{
+		firstLogin: len(known) == 0,
+		newNetwork: !seenInNetwork(known, ipAddress),
+	}
+
+	device, err := r.repo.GetByFingerprint(ctx, userID, entities.DeviceFingerprint(declaredID, userAgent))
+	if err != nil {
+		if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.NotFoundError {
+			log.Printf("[WARN] failed to look up login device for user %s: %v", userID, err)
+			return loginSighting{}
+		}
+		device = entities.NewDevice(userID, declaredID, userAgent)
+		device.ID = uuid.New().String()
+		sighting.newDevice = true
+	}
+
+	device.Seen(name, userAgent, ipAddress, appVersion)
+	if err := r.repo.Save(ctx, device); err != nil {
+		log.Printf("[WARN] failed to save login device for user %s: %v", userID, err)
+		return loginSighting{}
+	}
+
+	sighting.device = device
+	return sighting
+}
+
+// seenInNetwork reports whether any of the devices was seen in the network of ipAddress.
+// An unparsable address counts as seen, so it never triggers a new-network notification.
+func seenInNetwork(devices []*entities.Device, ipAddress string) bool {
+	network, err := valueobjects.NewIPNetwork(ipAddress)
+	if err != nil {
+		return true
+	}
+	for _, device := range devices {
+		if network.Contains(net.ParseIP(device.FirstIP)) || network.Contains(net.ParseIP(device.LastIP)) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordRefresh updates when and from where a session's device was last seen
+func (r deviceRecorder) recordRefresh(ctx context.Context, deviceID, userAgent, ipAddress, appVersion string) {
+	if deviceID == "" {
+		// Session started before devices were recorded
+		return
+	}
+
+	device, err := r.repo.GetByID(ctx, deviceID)
+	if err != nil {
+		log.Printf("[WARN] failed to load device %s on refresh: %v", deviceID, err)
+		return
+	}
+
+	device.Seen("", userAgent, ipAddress, appVersion)
+	if err := r.repo.Save(ctx, device); err != nil {
+		log.Printf("[WARN] failed to update device %s on refresh: %v", deviceID, err)
+	}
+}
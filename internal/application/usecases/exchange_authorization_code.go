@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"log"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// GrantTypeAuthorizationCode is the grant type of code exchanges at the token endpoint
+const GrantTypeAuthorizationCode = "authorization_code"
+
+// ExchangeAuthorizationCodeUseCase starts the session of an authorization code and
+// hands its tokens to the client the code was issued to (RFC 6749 section 4.1.3,
+// RFC 7636 section 4.6)
+type ExchangeAuthorizationCodeUseCase struct {
+	clients     clientAuthenticator
+	codeRepo    repositories.AuthorizationCodeRepository
+	userRepo    repositories.UserReader
+	tokenRepo   repositories.TokenRepository
+	denylist    repositories.AccessTokenDenylist
+	hashService services.HashService
+	login       *LoginUseCase
+	events      services.SecurityEventPublisher
+	accessTTL   time.Duration
+}
+
+// NewExchangeAuthorizationCodeUseCase creates a new ExchangeAuthorizationCodeUseCase
+func NewExchangeAuthorizationCodeUseCase(
+	clientRepo repositories.ClientReader,
+	codeRepo repositories.AuthorizationCodeRepository,
+	userRepo repositories.UserReader,
+	tokenRepo repositories.TokenRepository,
+	denylist repositories.AccessTokenDenylist,
+	hashService services.HashService,
+	login *LoginUseCase,
+	events services.SecurityEventPublisher,
+	accessTTL time.Duration,
+) *ExchangeAuthorizationCodeUseCase {
+	return &ExchangeAuthorizationCodeUseCase{
+		clients:     clientAuthenticator{clients: clientRepo, hashService: hashService},
+		codeRepo:    codeRepo,
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		denylist:    denylist,
+		hashService: hashService,
+		login:       login,
+		events:      events,
+		accessTTL:   accessTTL,
+	}
+}
+
+// Execute identifies the client and exchanges the code. A code that is unknown,
+// used, issued to another client or presented with the wrong redirect URI or
+// verifier is a forbidden error (invalid_grant); it can't be retried either way.
+// A code presented again also ends the session issued from it.
+func (uc *ExchangeAuthorizationCodeUseCase) Execute(ctx context.Context, req *dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error) {
+	client, err := uc.clients.identify(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Code == "" || req.CodeVerifier == "" {
+		return nil, errors.NewValidationError("code and code_verifier are required", nil)
+	}
+	if !isPKCEValue(req.CodeVerifier) {
+		return nil, errors.NewValidationError("code_verifier is malformed", nil)
+	}
+
+	codeHash, err := uc.hashService.HashRefreshToken(req.Code)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to hash authorization code", err)
+	}
+
+	// Consumed before it is checked, so a code gets one attempt
+	code, err := uc.codeRepo.Consume(ctx, codeHash)
+	if err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.NotFoundError {
+			return nil, errors.NewForbiddenError("Invalid authorization code", nil)
+		}
+		return nil, err
+	}
+	if code.Reused {
+		return nil, uc.revokeReusedCode(ctx, code)
+	}
+
+	if code.ClientID != client.ID {
+		return nil, errors.NewForbiddenError("Authorization code was issued to another client", nil)
+	}
+	if code.RedirectURI != req.RedirectURI {
+		return nil, errors.NewForbiddenError("Redirect URI does not match the authorization request", nil)
+	}
+	challenge := sha256.Sum256([]byte(req.CodeVerifier))
+	if subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(challenge[:])), []byte(code.CodeChallenge)) != 1 {
+		return nil, errors.NewForbiddenError("Code verifier does not match the code challenge", nil)
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, code.UserID)
+	if err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.NotFoundError {
+			return nil, errors.NewForbiddenError("User of the authorization code no longer exists", nil)
+		}
+		return nil, err
+	}
+	sessionID, err := valueobjects.NewSessionIDFromString(code.SessionID)
+	if err != nil {
+		return nil, errors.NewInternalError("Malformed authorization code session", err)
+	}
+
+	// The session starts now, on the device the user logged in with
+	device := &dto.LoginRequest{
+		UserAgent:      code.UserAgent,
+		IPAddress:      code.IPAddress,
+		AcceptLanguage: code.AcceptLanguage,
+		Nonce:          code.Nonce,
+	}
+	login, err := uc.login.issue(ctx, device, client, user, sessionID, &code.Scope, code.AuthTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without the openid scope the client gets no ID token
+	idToken := login.IDToken
+	if !hasScope(code.Scope, "openid") {
+		idToken = ""
+	}
+
+	expiresIn := int64(time.Until(login.ExpiresAt).Seconds())
+	if expiresIn < 0 {
+		expiresIn = 0
+	}
+
+	return &dto.OAuthTokenResponse{
+		AccessToken:  login.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    expiresIn,
+		RefreshToken: login.RefreshToken,
+		IDToken:      idToken,
+		Scope:        code.Scope,
+		SessionID:    code.SessionID,
+	}, nil
+}
+
+// revokeReusedCode ends the session issued from a code presented a second time,
+// since someone else may hold a copy (RFC 6749 section 4.1.2), and raises a
+// security event. The session is missing if the first exchange failed.
+func (uc *ExchangeAuthorizationCodeUseCase) revokeReusedCode(ctx context.Context, code *entities.AuthorizationCode) error {
+	if err := uc.tokenRepo.RevokeSession(ctx, code.UserID, code.SessionID, entities.RevokeReasonCodeReuse); err != nil {
+		if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.NotFoundError {
+			return errors.NewInternalError("Failed to revoke session of reused authorization code", err)
+		}
+	}
+	if err := denySessionTokens(ctx, uc.denylist, uc.accessTTL, code.SessionID); err != nil {
+		return errors.NewInternalError("Failed to deny access tokens of reused authorization code", err)
+	}
+
+	event := services.SecurityEvent{
+		Type:       services.SecurityEventAuthorizationCodeReuse,
+		UserID:     code.UserID,
+		SessionID:  code.SessionID,
+		ClientID:   code.ClientID,
+		OccurredAt: time.Now(),
+	}
+	if err := uc.events.Publish(ctx, event); err != nil {
+		log.Printf("[WARN] failed to publish authorization code reuse event: %v", err)
+	}
+
+	return errors.NewForbiddenError("Authorization code was already used; its session has been revoked", nil)
+}
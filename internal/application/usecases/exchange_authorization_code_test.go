@@ -0,0 +1,222 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// memoryCodeRepo keeps used codes, like the Redis repository does until they expire
+type memoryCodeRepo struct {
+	codes map[string]*entities.AuthorizationCode
+	used  map[string]bool
+}
+
+func (r *memoryCodeRepo) Save(ctx context.Context, codeHash string, code *entities.AuthorizationCode, ttl time.Duration) error {
+	r.codes[codeHash] = code
+	return nil
+}
+
+func (r *memoryCodeRepo) Consume(ctx context.Context, codeHash string) (*entities.AuthorizationCode, error) {
+	code, ok := r.codes[codeHash]
+	if !ok {
+		return nil, errors.NewNotFoundError("Authorization code not found", nil)
+	}
+	copied := *code
+	copied.Reused = r.used[codeHash]
+	r.used[codeHash] = true
+	return &copied, nil
+}
+
+// The RFC 7636 appendix B example
+const testCodeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+type exchangeFixture struct {
+	uc        *ExchangeAuthorizationCodeUseCase
+	tokens    *memoryTokenRepo
+	denylist  *memoryDenylist
+	jwt       *recordingJWTService
+	publisher *recordingEventPublisher
+	sessionID valueobjects.SessionID
+}
+
+// newExchangeFixture stores code "code-1" for the public client partner-app, issued
+// when user-1 logged in with scope
+func newExchangeFixture(t *testing.T, scope string) *exchangeFixture {
+	t.Helper()
+
+	sessionID := newTestSessionID(t)
+	codes := &memoryCodeRepo{used: map[string]bool{}, codes: map[string]*entities.AuthorizationCode{
+		"hash:code-1": {
+			ClientID:      "partner-app",
+			RedirectURI:   "https://partner.example.com/callback",
+			CodeChallenge: testCodeChallenge,
+			Scope:         scope,
+			UserID:        "user-1",
+			SessionID:     sessionID.String(),
+			Nonce:         "n-0S6_WzA2Mj",
+			AuthTime:      time.Now().Add(-time.Minute),
+			UserAgent:     "Mozilla/5.0 (X11; Linux x86_64) Firefox/128.0",
+			IPAddress:     "203.0.113.7",
+		},
+	}}
+	clients := &staticClientRepo{clients: map[string]*entities.Client{
+		"partner-app": {ID: "partner-app"},
+		"gateway":     {ID: "gateway", SecretHash: "hash:s3cret"},
+	}}
+	users := &staticUserRepo{user: &entities.User{ID: "user-1", Scope: "admin"}}
+	tokens := &memoryTokenRepo{tokens: make(map[string]*entities.RefreshToken)}
+	denylist := &memoryDenylist{denied: map[string]bool{}}
+	jwt := &recordingJWTService{}
+	hash := &fakeHashService{}
+	publisher := &recordingEventPublisher{}
+
+	login := &LoginUseCase{
+		userRepo:    users,
+		tokenRepo:   tokens,
+		uow:         &memoryUnitOfWork{tokens: tokens},
+		denylist:    denylist,
+		jwtService:  jwt,
+		hashService: hash,
+		accessTTL:   15 * time.Minute,
+		refreshTTL:  time.Hour,
+		claims: TokenClaimsConfig{
+			DefaultAudience: []string{"otp-auth"},
+			Clients:         clients,
+			IDTokenIssuer:   "https://auth.example.com",
+			IDTokenTTL:      time.Hour,
+		},
+		devices: deviceRecorder{repo: &memoryDeviceRepo{devices: map[string]*entities.Device{}}},
+	}
+	uc := NewExchangeAuthorizationCodeUseCase(clients, codes, users, tokens, denylist, hash, login, publisher, 15*time.Minute)
+	return &exchangeFixture{uc: uc, tokens: tokens, denylist: denylist, jwt: jwt, publisher: publisher, sessionID: sessionID}
+}
+
+func newTokenRequest() *dto.OAuthTokenRequest {
+	return &dto.OAuthTokenRequest{
+		GrantType:    GrantTypeAuthorizationCode,
+		Code:         "code-1",
+		RedirectURI:  "https://partner.example.com/callback",
+		CodeVerifier: testCodeVerifier,
+		ClientID:     "partner-app",
+	}
+}
+
+func TestExchangeAuthorizationCodeUseCase(t *testing.T) {
+	f := newExchangeFixture(t, "openid")
+
+	response, err := f.uc.Execute(context.Background(), newTokenRequest())
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	if response.AccessToken == "" || response.RefreshToken == "" || response.IDToken == "" {
+		t.Errorf("response = %+v, want access, refresh and ID tokens", response)
+	}
+	if response.TokenType != "Bearer" || response.ExpiresIn <= 0 || response.SessionID != f.sessionID.String() || response.Scope != "openid" {
+		t.Errorf("response = %+v, want a bearer token with its lifetime, session and scope", response)
+	}
+	if f.jwt.claims.SessionID != f.sessionID.String() || f.jwt.claims.ClientID != "partner-app" {
+		t.Errorf("claims = %+v, want the code's session and client", f.jwt.claims)
+	}
+
+	// The admin's role stays with first-party logins, and the token isn't for the first-party API
+	if len(f.jwt.claims.Scopes) != 1 || f.jwt.claims.Scopes[0] != "openid" {
+		t.Errorf("scopes = %v, want the granted scope", f.jwt.claims.Scopes)
+	}
+	if len(f.jwt.claims.Audience) != 1 || f.jwt.claims.Audience[0] != "partner-app" {
+		t.Errorf("audience = %v, want the client", f.jwt.claims.Audience)
+	}
+
+	// The session starts at the exchange, on the device the user logged in with
+	if len(f.tokens.tokens) != 1 {
+		t.Fatalf("%d refresh tokens stored, want 1", len(f.tokens.tokens))
+	}
+	for _, token := range f.tokens.tokens {
+		if token.SessionID != f.sessionID || token.ClientID != "partner-app" || token.IPAddress != "203.0.113.7" {
+			t.Errorf("refresh token = %+v, want the code's session, client and device", token)
+		}
+		if token.Scope == nil || *token.Scope != "openid" {
+			t.Errorf("refresh token scope = %v, want the granted scope", token.Scope)
+		}
+	}
+}
+
+func TestExchangeAuthorizationCodeUseCaseWithoutOpenID(t *testing.T) {
+	f := newExchangeFixture(t, "phone")
+
+	response, err := f.uc.Execute(context.Background(), newTokenRequest())
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	if response.IDToken != "" {
+		t.Errorf("ID token issued without the openid scope")
+	}
+}
+
+func TestExchangeAuthorizationCodeUseCaseRevokesReusedCode(t *testing.T) {
+	f := newExchangeFixture(t, "openid")
+
+	if _, err := f.uc.Execute(context.Background(), newTokenRequest()); err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	// A code can only be exchanged once; the session issued from it ends
+	_, err := f.uc.Execute(context.Background(), newTokenRequest())
+	if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.Forbidden {
+		t.Fatalf("second exchange error = %v, want forbidden", err)
+	}
+	for _, token := range f.tokens.tokens {
+		if !token.Revoked || token.RevokeReason != entities.RevokeReasonCodeReuse {
+			t.Errorf("refresh token revoked = %v (%q), want revoked for code reuse", token.Revoked, token.RevokeReason)
+		}
+	}
+	if !sessionDenied(t, f.denylist, f.sessionID) {
+		t.Error("access tokens of the reused code still accepted")
+	}
+	if len(f.publisher.events) != 1 || f.publisher.events[0].Type != services.SecurityEventAuthorizationCodeReuse {
+		t.Errorf("events = %+v, want one authorization code reuse event", f.publisher.events)
+	}
+}
+
+func TestExchangeAuthorizationCodeUseCaseRejectsMismatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*dto.OAuthTokenRequest)
+		wantType errors.ErrorType
+	}{
+		{"wrong verifier", func(r *dto.OAuthTokenRequest) { r.CodeVerifier = "x" + testCodeVerifier[1:] }, errors.Forbidden},
+		{"other redirect URI", func(r *dto.OAuthTokenRequest) { r.RedirectURI = "https://partner.example.com/other" }, errors.Forbidden},
+		{"other client", func(r *dto.OAuthTokenRequest) { r.ClientID, r.ClientSecret = "gateway", "s3cret" }, errors.Forbidden},
+		{"confidential client without secret", func(r *dto.OAuthTokenRequest) { r.ClientID = "gateway" }, errors.Unauthorized},
+		{"malformed verifier", func(r *dto.OAuthTokenRequest) { r.CodeVerifier = "short" }, errors.ValidationError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newExchangeFixture(t, "openid")
+			req := newTokenRequest()
+			tt.modify(req)
+
+			_, err := f.uc.Execute(context.Background(), req)
+			if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != tt.wantType {
+				t.Fatalf("error = %v, want %s", err, tt.wantType)
+			}
+			if len(f.tokens.tokens) != 0 {
+				t.Error("session started by a failed exchange")
+			}
+
+			// The failed attempt used the code up
+			if tt.wantType == errors.Forbidden {
+				if _, err := f.uc.Execute(context.Background(), newTokenRequest()); err == nil {
+					t.Error("code exchanged after a failed exchange")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// GrantTypeRefreshToken is the grant type of refresh requests at the token endpoint
+const GrantTypeRefreshToken = "refresh_token"
+
+// ExchangeRefreshTokenUseCase rotates a refresh token for the client it was issued
+// to (RFC 6749 section 6), the same way /api/v1/auth/refresh does for first-party
+// apps. Clients don't need the session ID: it is looked up from the token.
+type ExchangeRefreshTokenUseCase struct {
+	clients     clientAuthenticator
+	tokenRepo   repositories.TokenReader
+	hashService services.HashService
+	refresh     *RefreshUseCase
+}
+
+// NewExchangeRefreshTokenUseCase creates a new ExchangeRefreshTokenUseCase
+func NewExchangeRefreshTokenUseCase(
+	clientRepo repositories.ClientReader,
+	tokenRepo repositories.TokenReader,
+	hashService services.HashService,
+	refresh *RefreshUseCase,
+) *ExchangeRefreshTokenUseCase {
+	return &ExchangeRefreshTokenUseCase{
+		clients:     clientAuthenticator{clients: clientRepo, hashService: hashService},
+		tokenRepo:   tokenRepo,
+		hashService: hashService,
+		refresh:     refresh,
+	}
+}
+
+// Execute identifies the client and rotates the refresh token. A token that is
+// unknown, issued to another client, expired or revoked is a forbidden error
+// (invalid_grant); presenting a rotated token again revokes its session.
+func (uc *ExchangeRefreshTokenUseCase) Execute(ctx context.Context, req *dto.OAuthTokenRequest) (*dto.OAuthTokenResponse, error) {
+	client, err := uc.clients.identify(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RefreshToken == "" {
+		return nil, errors.NewValidationError("refresh_token is required", nil)
+	}
+
+	tokenHash, err := uc.hashService.HashRefreshToken(req.RefreshToken)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to hash refresh token", err)
+	}
+
+	token, err := uc.tokenRepo.GetByTokenHash(ctx, tokenHash)
+	if err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.NotFoundError {
+			return nil, errors.NewForbiddenError("Invalid refresh token", nil)
+		}
+		return nil, err
+	}
+	if token.ClientID != client.ID {
+		return nil, errors.NewForbiddenError("Refresh token was issued to another client", nil)
+	}
+
+	sessionID := token.SessionID.String()
+	refreshed, err := uc.refresh.Execute(ctx, &dto.RefreshTokenRequest{
+		RefreshToken: req.RefreshToken,
+		SessionID:    sessionID,
+	})
+	if err != nil {
+		// The client is already authenticated, so a rejected token is a bad grant
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.Unauthorized {
+			return nil, errors.NewForbiddenError(customErr.Message, err)
+		}
+		return nil, err
+	}
+
+	expiresIn := int64(time.Until(refreshed.ExpiresAt).Seconds())
+	if expiresIn < 0 {
+		expiresIn = 0
+	}
+
+	response := &dto.OAuthTokenResponse{
+		AccessToken:  refreshed.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    expiresIn,
+		RefreshToken: refreshed.RefreshToken,
+		IDToken:      refreshed.IDToken,
+		SessionID:    sessionID,
+	}
+	// Sessions of the authorization code flow keep the scopes granted to them
+	if token.Scope != nil {
+		response.Scope = *token.Scope
+		if !hasScope(*token.Scope, "openid") {
+			response.IDToken = ""
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// newRefreshGrantFixture lets the public clients web and partner-app use the
+// refresh fixture's token, which was issued to web
+func newRefreshGrantFixture(t *testing.T) (*ExchangeRefreshTokenUseCase, *refreshFixture) {
+	t.Helper()
+	f := newRefreshFixture(t, 0)
+	clients := &staticClientRepo{clients: map[string]*entities.Client{
+		"web":         {ID: "web"},
+		"partner-app": {ID: "partner-app"},
+	}}
+	return NewExchangeRefreshTokenUseCase(clients, f.tokens, &fakeHashService{}, f.uc), f
+}
+
+func TestExchangeRefreshTokenUseCase(t *testing.T) {
+	uc, f := newRefreshGrantFixture(t)
+
+	req := &dto.OAuthTokenRequest{GrantType: GrantTypeRefreshToken, RefreshToken: "first", ClientID: "web"}
+	response, err := uc.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if response.AccessToken == "" || response.RefreshToken == "" || response.RefreshToken == "first" {
+		t.Errorf("response = %+v, want new access and refresh tokens", response)
+	}
+	if response.TokenType != "Bearer" || response.ExpiresIn <= 0 || response.SessionID != f.sessionID.String() {
+		t.Errorf("response = %+v, want a bearer token with its lifetime and session", response)
+	}
+
+	// The rotated token is a bad grant now, and its session is revoked
+	_, err = uc.Execute(context.Background(), req)
+	if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.Forbidden {
+		t.Fatalf("reuse error = %v, want forbidden", err)
+	}
+	for _, token := range f.tokens.tokens {
+		if !token.Revoked {
+			t.Errorf("token %s survived the reuse", token.ID)
+		}
+	}
+}
+
+func TestExchangeRefreshTokenUseCaseKeepsGrantedScope(t *testing.T) {
+	uc, f := newRefreshGrantFixture(t)
+	scope := "phone"
+	f.tokens.tokens["login"].Scope = &scope
+
+	response, err := uc.Execute(context.Background(), &dto.OAuthTokenRequest{GrantType: GrantTypeRefreshToken, RefreshToken: "first", ClientID: "web"})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if response.Scope != "phone" || response.IDToken != "" {
+		t.Errorf("response = %+v, want the granted scope and no ID token without openid", response)
+	}
+	for _, token := range f.tokens.tokens {
+		if token.Scope == nil || *token.Scope != "phone" {
+			t.Errorf("token %s scope = %v, want the granted scope", token.ID, token.Scope)
+		}
+	}
+}
+
+func TestExchangeRefreshTokenUseCaseRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      dto.OAuthTokenRequest
+		wantType errors.ErrorType
+	}{
+		{"other client", dto.OAuthTokenRequest{RefreshToken: "first", ClientID: "partner-app"}, errors.Forbidden},
+		{"unknown token", dto.OAuthTokenRequest{RefreshToken: "other", ClientID: "web"}, errors.Forbidden},
+		{"missing token", dto.OAuthTokenRequest{ClientID: "web"}, errors.ValidationError},
+		{"unknown client", dto.OAuthTokenRequest{RefreshToken: "first", ClientID: "mobile"}, errors.Unauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, f := newRefreshGrantFixture(t)
+			req := tt.req
+			req.GrantType = GrantTypeRefreshToken
+
+			_, err := uc.Execute(context.Background(), &req)
+			if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != tt.wantType {
+				t.Fatalf("error = %v, want %s", err, tt.wantType)
+			}
+			if len(f.tokens.tokens) != 1 || f.tokens.tokens["login"].Revoked {
+				t.Error("refresh token rotated or revoked by a rejected request")
+			}
+		})
+	}
+}
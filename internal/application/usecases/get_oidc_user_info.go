@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+)
+
+// GetOIDCUserInfoUseCase returns the OpenID Connect claims of the user an access token was issued to
+type GetOIDCUserInfoUseCase struct {
+	userRepo repositories.UserRepository
+}
+
+// NewGetOIDCUserInfoUseCase creates a new GetOIDCUserInfoUseCase
+func NewGetOIDCUserInfoUseCase(userRepo repositories.UserRepository) *GetOIDCUserInfoUseCase {
+	return &GetOIDCUserInfoUseCase{
+		userRepo: userRepo,
+	}
+}
+
+// Execute retrieves the claims of the user by user ID
+func (uc *GetOIDCUserInfoUseCase) Execute(ctx context.Context, userID string) (*dto.OIDCUserInfoResponse, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users only exist after proving the phone number with an OTP
+	return &dto.OIDCUserInfoResponse{
+		Subject:             user.ID,
+		PhoneNumber:         user.PhoneNumber.String(),
+		PhoneNumberVerified: true,
+	}, nil
+}
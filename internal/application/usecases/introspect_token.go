@@ -0,0 +1,176 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// Token type hints of RFC 7009 and RFC 7662 requests
+const (
+	TokenTypeHintAccessToken  = "access_token"
+	TokenTypeHintRefreshToken = "refresh_token"
+)
+
+// IntrospectTokenUseCase tells authenticated clients whether an access or
+// refresh token is active (RFC 7662), for gateways that can't verify tokens
+type IntrospectTokenUseCase struct {
+	clients       clientAuthenticator
+	tokenRepo     repositories.TokenRepository
+	userRepo      repositories.UserRepository
+	denylist      repositories.AccessTokenDenylist // optional
+	failClosed    bool                             // report tokens inactive while the denylist is unavailable
+	jwtService    services.JWTService
+	hashService   services.HashService
+	claims        TokenClaimsConfig
+	sessions      SessionPolicies
+	verifyOptions []services.VerifyOption
+}
+
+// NewIntrospectTokenUseCase creates a new IntrospectTokenUseCase; verifyOptions
+// typically pin the issuer but not the audience, since any client's tokens can
+// be introspected
+func NewIntrospectTokenUseCase(
+	clientRepo repositories.ClientReader,
+	tokenRepo repositories.TokenRepository,
+	userRepo repositories.UserRepository,
+	denylist repositories.AccessTokenDenylist,
+	failClosed bool,
+	jwtService services.JWTService,
+	hashService services.HashService,
+	claims TokenClaimsConfig,
+	sessions SessionPolicies,
+	verifyOptions ...services.VerifyOption,
+) *IntrospectTokenUseCase {
+	return &IntrospectTokenUseCase{
+		clients:       clientAuthenticator{clients: clientRepo, hashService: hashService},
+		tokenRepo:     tokenRepo,
+		userRepo:      userRepo,
+		denylist:      denylist,
+		failClosed:    failClosed,
+		jwtService:    jwtService,
+		hashService:   hashService,
+		claims:        claims,
+		sessions:      sessions,
+		verifyOptions: verifyOptions,
+	}
+}
+
+// Execute authenticates the client and introspects the token. Tokens that are
+// invalid, expired, revoked or unknown are reported as inactive, not as errors.
+func (uc *IntrospectTokenUseCase) Execute(ctx context.Context, req *dto.IntrospectTokenRequest) (*dto.IntrospectTokenResponse, error) {
+	if _, err := uc.clients.authenticate(ctx, req.ClientID, req.ClientSecret); err != nil {
+		return nil, err
+	}
+
+	if req.Token == "" {
+		return nil, errors.NewValidationError("Token is required", nil)
+	}
+
+	// The hint only decides which lookup comes first
+	lookups := []func(context.Context, string) (*dto.IntrospectTokenResponse, error){uc.introspectAccessToken, uc.introspectRefreshToken}
+	if req.TokenTypeHint == TokenTypeHintRefreshToken {
+		lookups[0], lookups[1] = lookups[1], lookups[0]
+	}
+
+	for _, lookup := range lookups {
+		response, err := lookup(ctx, req.Token)
+		if err != nil {
+			return nil, err
+		}
+		if response != nil {
+			return response, nil
+		}
+	}
+
+	return &dto.IntrospectTokenResponse{Active: false}, nil
+}
+
+// introspectAccessToken returns nil if token isn't an active access token
+func (uc *IntrospectTokenUseCase) introspectAccessToken(ctx context.Context, token string) (*dto.IntrospectTokenResponse, error) {
+	claims, err := uc.jwtService.VerifyToken(token, uc.verifyOptions...)
+	if err != nil || claims.IsExpired() {
+		return nil, nil
+	}
+
+	// Like the auth middleware, fail open or closed if the denylist can't be reached
+	if uc.denylist != nil {
+		denied, err := uc.denylist.IsDenied(ctx, claims.TokenID, claims.Subject, claims.SessionID, time.Unix(claims.IssuedAt, 0))
+		if err != nil {
+			log.Printf("[WARN] Access token denylist unavailable during introspection: %v", err)
+			if uc.failClosed {
+				return &dto.IntrospectTokenResponse{Active: false}, nil
+			}
+		} else if denied {
+			return nil, nil
+		}
+	}
+
+	return &dto.IntrospectTokenResponse{
+		Active:    true,
+		Scope:     strings.Join(claims.Scopes, " "),
+		ClientID:  claims.ClientID,
+		Subject:   claims.Subject,
+		ExpiresAt: claims.ExpiresAt,
+		IssuedAt:  claims.IssuedAt,
+		TokenID:   claims.TokenID,
+	}, nil
+}
+
+// introspectRefreshToken returns nil if token isn't an active refresh token
+func (uc *IntrospectTokenUseCase) introspectRefreshToken(ctx context.Context, token string) (*dto.IntrospectTokenResponse, error) {
+	tokenHash, err := uc.hashService.HashRefreshToken(token)
+	if err != nil {
+		return nil, nil
+	}
+
+	refreshToken, err := uc.tokenRepo.GetByTokenHash(ctx, tokenHash)
+	if err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.NotFoundError {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !refreshToken.IsValid() {
+		return nil, nil
+	}
+
+	// Active only if a refresh would succeed: the client may still use it and
+	// the session is within its lifetime limits
+	client, err := uc.claims.resolveClient(ctx, refreshToken.ClientID)
+	if err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.ValidationError {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if uc.sessions.forClient(client).expired(refreshToken, time.Now()) {
+		return nil, nil
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, refreshToken.UserID)
+	if err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.NotFoundError {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// The scopes the session's access tokens get: the granted ones, or the user's role
+	scopes, _ := uc.claims.sessionClaims(client, user, refreshToken.Scope)
+
+	return &dto.IntrospectTokenResponse{
+		Active:    true,
+		Scope:     strings.Join(scopes, " "),
+		ClientID:  refreshToken.ClientID,
+		Subject:   refreshToken.UserID,
+		ExpiresAt: refreshToken.ExpiresAt.Unix(),
+		IssuedAt:  refreshToken.CreatedAt.Unix(),
+		TokenID:   refreshToken.ID,
+	}, nil
+}
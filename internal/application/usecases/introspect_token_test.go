@@ -0,0 +1,294 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+type staticClientRepo struct {
+	clients map[string]*entities.Client
+}
+
+func (r *staticClientRepo) GetByID(ctx context.Context, id string) (*entities.Client, error) {
+	client, ok := r.clients[id]
+	if !ok {
+		return nil, errors.NewNotFoundError("Client not found", nil)
+	}
+	return client, nil
+}
+
+func (r *staticClientRepo) AllowsCORSOrigin(ctx context.Context, origin string) (bool, error) {
+	for _, client := range r.clients {
+		if client.AllowsCORSOrigin(origin) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *staticClientRepo) List(ctx context.Context) ([]*entities.Client, error) {
+	clients := make([]*entities.Client, 0, len(r.clients))
+	for _, client := range r.clients {
+		clients = append(clients, client)
+	}
+	return clients, nil
+}
+
+func (r *staticClientRepo) Create(ctx context.Context, client *entities.Client) error {
+	if _, ok := r.clients[client.ID]; ok {
+		return errors.NewConflictError("Client with this ID already exists", nil)
+	}
+	r.clients[client.ID] = client
+	return nil
+}
+
+func (r *staticClientRepo) Update(ctx context.Context, client *entities.Client) error {
+	if _, ok := r.clients[client.ID]; !ok {
+		return errors.NewNotFoundError("Client not found", nil)
+	}
+	r.clients[client.ID] = client
+	return nil
+}
+
+func (r *staticClientRepo) Delete(ctx context.Context, id string) error {
+	if _, ok := r.clients[id]; !ok {
+		return errors.NewNotFoundError("Client not found", nil)
+	}
+	delete(r.clients, id)
+	return nil
+}
+
+// claimsJWTService verifies the tokens it was given claims for
+type claimsJWTService struct {
+	fakeJWTService
+	claims map[string]*services.JWTClaims
+}
+
+func (s claimsJWTService) VerifyToken(token string, opts ...services.VerifyOption) (*services.JWTClaims, error) {
+	claims, ok := s.claims[token]
+	if !ok {
+		return nil, errors.NewUnauthorizedError("Invalid token", nil)
+	}
+	return claims, nil
+}
+
+type memoryDenylist struct {
+	repositories.AccessTokenDenylist
+	denied      map[string]bool
+	sessions    map[string]time.Time // session watermarks
+	unavailable bool                 // IsDenied fails as if Redis were down
+}
+
+func (d *memoryDenylist) Deny(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	d.denied[tokenID] = true
+	return nil
+}
+
+func (d *memoryDenylist) DenySessionTokensBefore(ctx context.Context, sessionID string, t time.Time, ttl time.Duration) error {
+	if d.sessions == nil {
+		d.sessions = make(map[string]time.Time)
+	}
+	d.sessions[sessionID] = t
+	return nil
+}
+
+func (d *memoryDenylist) IsDenied(ctx context.Context, tokenID, userID, sessionID string, issuedAt time.Time) (bool, error) {
+	if d.unavailable {
+		return false, errors.NewInternalError("Denylist unavailable", nil)
+	}
+	if before, ok := d.sessions[sessionID]; ok && issuedAt.Unix() <= before.Unix() {
+		return true, nil
+	}
+	return d.denied[tokenID], nil
+}
+
+type oauthFixture struct {
+	tokens   *memoryTokenRepo
+	denylist *memoryDenylist
+	jwt      claimsJWTService
+	hash     *fakeHashService
+	clients  *staticClientRepo
+	users    *staticUserRepo
+	sessions SessionPolicies
+}
+
+func newOAuthFixture() *oauthFixture {
+	now := time.Now()
+	refreshToken := entities.NewRefreshToken("user-1", "session-1", "hash:refresh-1", time.Hour)
+	refreshToken.ID = "rt-1"
+	refreshToken.ClientID = "mobile-app"
+
+	return &oauthFixture{
+		tokens:   &memoryTokenRepo{tokens: map[string]*entities.RefreshToken{refreshToken.ID: refreshToken}},
+		denylist: &memoryDenylist{denied: map[string]bool{}},
+		jwt: claimsJWTService{claims: map[string]*services.JWTClaims{
+			"access-1": {
+				Subject:   "user-1",
+				ClientID:  "mobile-app",
+				Scopes:    []string{"user"},
+				IssuedAt:  now.Unix(),
+				ExpiresAt: now.Add(15 * time.Minute).Unix(),
+				TokenID:   "jti-1",
+			},
+		}},
+		hash: &fakeHashService{},
+		clients: &staticClientRepo{clients: map[string]*entities.Client{
+			"gateway":    {ID: "gateway", SecretHash: "hash:s3cret"},
+			"mobile-app": {ID: "mobile-app"},
+		}},
+		users: &staticUserRepo{user: &entities.User{ID: "user-1", Scope: "admin"}},
+	}
+}
+
+func (f *oauthFixture) introspect() *IntrospectTokenUseCase {
+	return NewIntrospectTokenUseCase(f.clients, f.tokens, f.users, f.denylist, true, f.jwt, f.hash, TokenClaimsConfig{Clients: f.clients}, f.sessions)
+}
+
+func TestIntrospectTokenUseCaseAccessToken(t *testing.T) {
+	f := newOAuthFixture()
+	uc := f.introspect()
+
+	resp, err := uc.Execute(context.Background(), &dto.IntrospectTokenRequest{Token: "access-1", ClientID: "gateway", ClientSecret: "s3cret"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !resp.Active || resp.Subject != "user-1" || resp.ClientID != "mobile-app" || resp.Scope != "user" || resp.TokenID != "jti-1" || resp.ExpiresAt == 0 {
+		t.Errorf("Execute() = %+v, want the access token's claims", resp)
+	}
+
+	f.denylist.denied["jti-1"] = true
+	resp, err = uc.Execute(context.Background(), &dto.IntrospectTokenRequest{Token: "access-1", ClientID: "gateway", ClientSecret: "s3cret"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Active {
+		t.Errorf("Execute() active = true for a denylisted access token")
+	}
+}
+
+func TestIntrospectTokenUseCaseDenylistUnavailable(t *testing.T) {
+	for _, failClosed := range []bool{true, false} {
+		f := newOAuthFixture()
+		f.denylist.unavailable = true
+		uc := NewIntrospectTokenUseCase(f.clients, f.tokens, f.users, f.denylist, failClosed, f.jwt, f.hash, TokenClaimsConfig{Clients: f.clients}, f.sessions)
+
+		resp, err := uc.Execute(context.Background(), &dto.IntrospectTokenRequest{Token: "access-1", ClientID: "gateway", ClientSecret: "s3cret"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if resp.Active == failClosed {
+			t.Errorf("fail closed %v: Execute() active = %v while the denylist is unavailable", failClosed, resp.Active)
+		}
+	}
+}
+
+func TestIntrospectTokenUseCaseRefreshToken(t *testing.T) {
+	f := newOAuthFixture()
+	uc := f.introspect()
+	req := &dto.IntrospectTokenRequest{Token: "refresh-1", TokenTypeHint: TokenTypeHintRefreshToken, ClientID: "gateway", ClientSecret: "s3cret"}
+
+	resp, err := uc.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !resp.Active || resp.Subject != "user-1" || resp.ClientID != "mobile-app" || resp.Scope != "admin" || resp.TokenID != "rt-1" {
+		t.Errorf("Execute() = %+v, want the refresh token's details", resp)
+	}
+
+	f.tokens.tokens["rt-1"].Revoke(entities.RevokeReasonLogout)
+	resp, err = uc.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Active {
+		t.Errorf("Execute() active = true for a revoked refresh token")
+	}
+}
+
+func TestIntrospectTokenUseCaseRefreshTokenOfCodeFlow(t *testing.T) {
+	f := newOAuthFixture()
+	granted := "openid phone"
+	f.tokens.tokens["rt-1"].Scope = &granted
+	req := &dto.IntrospectTokenRequest{Token: "refresh-1", ClientID: "gateway", ClientSecret: "s3cret"}
+
+	// The user is an admin, but the client was only granted openid and phone
+	resp, err := f.introspect().Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !resp.Active || resp.Scope != granted {
+		t.Errorf("Execute() = %+v, want the granted scope %q", resp, granted)
+	}
+}
+
+func TestIntrospectTokenUseCaseRefreshTokenPastSessionPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy SessionPolicy
+	}{
+		{"idle timeout", SessionPolicy{IdleTimeout: 30 * time.Minute}},
+		{"absolute lifetime", SessionPolicy{AbsoluteLifetime: 24 * time.Hour}},
+	}
+
+	for _, tt := range tests {
+		f := newOAuthFixture()
+		f.sessions = SessionPolicies{Default: tt.policy}
+		token := f.tokens.tokens["rt-1"]
+		token.CreatedAt = time.Now().Add(-45 * time.Minute)
+		token.SessionStartedAt = time.Now().Add(-48 * time.Hour)
+		if tt.policy.AbsoluteLifetime > 0 {
+			token.CreatedAt = time.Now()
+		}
+
+		resp, err := f.introspect().Execute(context.Background(), &dto.IntrospectTokenRequest{Token: "refresh-1", ClientID: "gateway", ClientSecret: "s3cret"})
+		if err != nil {
+			t.Fatalf("%s: Execute() error = %v", tt.name, err)
+		}
+		if resp.Active {
+			t.Errorf("%s: Execute() active = true for a session past its limit", tt.name)
+		}
+	}
+}
+
+func TestIntrospectTokenUseCaseUnknownToken(t *testing.T) {
+	uc := newOAuthFixture().introspect()
+
+	resp, err := uc.Execute(context.Background(), &dto.IntrospectTokenRequest{Token: "garbage", ClientID: "gateway", ClientSecret: "s3cret"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if resp.Active || resp.Subject != "" {
+		t.Errorf("Execute() = %+v, want only active=false", resp)
+	}
+}
+
+func TestIntrospectTokenUseCaseAuthenticatesClient(t *testing.T) {
+	uc := newOAuthFixture().introspect()
+
+	tests := []struct {
+		name     string
+		clientID string
+		secret   string
+	}{
+		{name: "wrong secret", clientID: "gateway", secret: "guess"},
+		{name: "unknown client", clientID: "nobody", secret: "s3cret"},
+		{name: "public client", clientID: "mobile-app", secret: "anything"},
+		{name: "no credentials"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.Execute(context.Background(), &dto.IntrospectTokenRequest{Token: "access-1", ClientID: tt.clientID, ClientSecret: tt.secret})
+			if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.Unauthorized {
+				t.Errorf("Execute() error = %v, want unauthorized", err)
+			}
+		})
+	}
+}
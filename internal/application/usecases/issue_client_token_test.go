@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// recordingJWTService remembers the claims of the last token it generated
+type recordingJWTService struct {
+	fakeJWTService
+	claims *services.JWTClaims
+}
+
+func (s *recordingJWTService) GenerateToken(claims *services.JWTClaims) (string, error) {
+	s.claims = claims
+	return s.fakeJWTService.GenerateToken(claims)
+}
+
+// newClientTokenFixture registers the confidential client gateway, allowed the
+// client credentials grant, the public client partner-app and the confidential
+// client web, which may only use the authorization code grant
+func newClientTokenFixture() (*IssueClientTokenUseCase, *recordingJWTService) {
+	clients := &staticClientRepo{clients: map[string]*entities.Client{
+		"gateway": {
+			ID:                "gateway",
+			SecretHash:        "hash:s3cret",
+			Audience:          []string{"orders"},
+			AllowedScopes:     []string{"openid", "admin", "orders:read", "orders:write"},
+			AllowedGrantTypes: []string{GrantTypeClientCredentials},
+			AccessTokenTTL:    5 * time.Minute,
+		},
+		"partner-app": {ID: "partner-app", AllowedGrantTypes: []string{GrantTypeClientCredentials}},
+		"web":         {ID: "web", SecretHash: "hash:s3cret", AllowedGrantTypes: []string{GrantTypeAuthorizationCode}},
+	}}
+	jwt := &recordingJWTService{}
+	claims := TokenClaimsConfig{Issuer: "otp-auth", DefaultAudience: []string{"otp-auth"}}
+	return NewIssueClientTokenUseCase(clients, jwt, &fakeHashService{}, 15*time.Minute, claims), jwt
+}
+
+func TestIssueClientTokenUseCase(t *testing.T) {
+	uc, jwt := newClientTokenFixture()
+
+	response, err := uc.Execute(context.Background(), &dto.OAuthTokenRequest{
+		GrantType:    GrantTypeClientCredentials,
+		ClientID:     "gateway",
+		ClientSecret: "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if response.RefreshToken != "" || response.IDToken != "" || response.SessionID != "" {
+		t.Errorf("response = %+v, want only an access token", response)
+	}
+	if response.TokenType != "Bearer" || response.ExpiresIn != 300 || response.Scope != "orders:read orders:write" {
+		t.Errorf("response = %+v, want a 5m bearer token with the client's own scopes", response)
+	}
+
+	if jwt.claims.Subject != "client:gateway" || !jwt.claims.IsClient() || jwt.claims.ClientID != "gateway" {
+		t.Errorf("claims = %+v, want a client subject", jwt.claims)
+	}
+	if len(jwt.claims.Audience) != 1 || jwt.claims.Audience[0] != "orders" || jwt.claims.Issuer != "otp-auth" {
+		t.Errorf("claims = %+v, want the client's audience and the issuer", jwt.claims)
+	}
+
+	response, err = uc.Execute(context.Background(), &dto.OAuthTokenRequest{
+		GrantType:    GrantTypeClientCredentials,
+		Scope:        "orders:read orders:read",
+		ClientID:     "gateway",
+		ClientSecret: "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("issue with scope: %v", err)
+	}
+	if response.Scope != "orders:read" {
+		t.Errorf("scope = %q, want the requested scope", response.Scope)
+	}
+}
+
+func TestIssueClientTokenUseCaseRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      dto.OAuthTokenRequest
+		wantCode string           // AuthorizationError code
+		wantType errors.ErrorType // otherwise
+	}{
+		{"public client", dto.OAuthTokenRequest{ClientID: "partner-app"}, "", errors.Unauthorized},
+		{"wrong secret", dto.OAuthTokenRequest{ClientID: "gateway", ClientSecret: "wrong"}, "", errors.Unauthorized},
+		{"grant not allowed", dto.OAuthTokenRequest{ClientID: "web", ClientSecret: "s3cret"}, "unauthorized_client", ""},
+		{"scope not allowed", dto.OAuthTokenRequest{ClientID: "gateway", ClientSecret: "s3cret", Scope: "orders:delete"}, "invalid_scope", ""},
+		{"user scope", dto.OAuthTokenRequest{ClientID: "gateway", ClientSecret: "s3cret", Scope: "admin"}, "invalid_scope", ""},
+		{"authorization scope", dto.OAuthTokenRequest{ClientID: "gateway", ClientSecret: "s3cret", Scope: "openid"}, "invalid_scope", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, jwt := newClientTokenFixture()
+			req := tt.req
+			req.GrantType = GrantTypeClientCredentials
+
+			_, err := uc.Execute(context.Background(), &req)
+			if tt.wantCode != "" {
+				var authErr *AuthorizationError
+				if !stderrors.As(err, &authErr) || authErr.Code != tt.wantCode {
+					t.Fatalf("error = %v, want %s", err, tt.wantCode)
+				}
+			} else if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != tt.wantType {
+				t.Fatalf("error = %v, want %s", err, tt.wantType)
+			}
+			if jwt.claims != nil {
+				t.Error("token issued despite the error")
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+)
+
+// ListClientsUseCase handles listing registered clients
+type ListClientsUseCase struct {
+	clientRepo repositories.ClientRepository
+}
+
+// NewListClientsUseCase creates a new ListClientsUseCase
+func NewListClientsUseCase(clientRepo repositories.ClientRepository) *ListClientsUseCase {
+	return &ListClientsUseCase{
+		clientRepo: clientRepo,
+	}
+}
+
+// Execute retrieves all registered clients
+func (uc *ListClientsUseCase) Execute(ctx context.Context) (*dto.ClientsResponse, error) {
+	clients, err := uc.clientRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewClientsResponse(clients), nil
+}
@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// ListDevicesUseCase handles listing the devices users have logged in from
+type ListDevicesUseCase struct {
+	deviceRepo repositories.DeviceRepository
+}
+
+// NewListDevicesUseCase creates a new ListDevicesUseCase
+func NewListDevicesUseCase(deviceRepo repositories.DeviceRepository) *ListDevicesUseCase {
+	return &ListDevicesUseCase{
+		deviceRepo: deviceRepo,
+	}
+}
+
+// Execute retrieves devices with pagination, optionally only those of one user
+func (uc *ListDevicesUseCase) Execute(ctx context.Context, req *dto.ListDevicesRequest) (*dto.DevicesResponse, error) {
+	// Validate pagination parameters
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	if req.Limit > 100 {
+		req.Limit = 100 // Maximum limit
+	}
+
+	if req.UserID != "" {
+		if _, err := uuid.Parse(req.UserID); err != nil {
+			return nil, errors.NewValidationError("Invalid user_id", err)
+		}
+	}
+
+	offset := (req.Page - 1) * req.Limit
+
+	devices, total, err := uc.deviceRepo.List(ctx, req.UserID, offset, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewDevicesResponse(devices, total, req.Page, req.Limit), nil
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+)
+
+// ListIPBansUseCase handles listing banned IPs and CIDR blocks
+type ListIPBansUseCase struct {
+	banRepo repositories.IPBanRepository
+}
+
+// NewListIPBansUseCase creates a new ListIPBansUseCase
+func NewListIPBansUseCase(banRepo repositories.IPBanRepository) *ListIPBansUseCase {
+	return &ListIPBansUseCase{
+		banRepo: banRepo,
+	}
+}
+
+// Execute retrieves all active IP bans
+func (uc *ListIPBansUseCase) Execute(ctx context.Context) (*dto.IPBansResponse, error) {
+	bans, err := uc.banRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.NewIPBansResponse(bans), nil
+}
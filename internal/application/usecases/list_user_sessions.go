@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+)
+
+// ListUserSessionsUseCase lists where a user is logged in
+type ListUserSessionsUseCase struct {
+	tokenRepo repositories.TokenRepository
+}
+
+// NewListUserSessionsUseCase creates a new ListUserSessionsUseCase
+func NewListUserSessionsUseCase(tokenRepo repositories.TokenRepository) *ListUserSessionsUseCase {
+	return &ListUserSessionsUseCase{
+		tokenRepo: tokenRepo,
+	}
+}
+
+// Execute returns the user's active sessions, most recently used first.
+// currentSessionID marks the caller's own session and may be empty.
+func (uc *ListUserSessionsUseCase) Execute(ctx context.Context, userID string, currentSessionID string) (*dto.SessionsResponse, error) {
+	tokens, err := uc.tokenRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokens are newest first, so the first token of each session describes its latest use
+	sessions := make([]dto.SessionInfo, 0, len(tokens))
+	index := make(map[string]int, len(tokens))
+	for _, token := range tokens {
+		sessionID := token.SessionID.String()
+
+		i, seen := index[sessionID]
+		if !seen {
+			index[sessionID] = len(sessions)
+			sessions = append(sessions, dto.SessionInfo{
+				ID:         sessionID,
+				DeviceName: token.DeviceName,
+				UserAgent:  token.UserAgent,
+				IPAddress:  token.IPAddress,
+				ClientID:   token.ClientID,
+				CreatedAt:  token.SessionStartedAt,
+				LastUsedAt: token.CreatedAt,
+				ExpiresAt:  token.ExpiresAt,
+				Current:    sessionID == currentSessionID,
+			})
+			continue
+		}
+
+		// Several logins may share a session; report the earliest start and latest expiry
+		if token.SessionStartedAt.Before(sessions[i].CreatedAt) {
+			sessions[i].CreatedAt = token.SessionStartedAt
+		}
+		if token.ExpiresAt.After(sessions[i].ExpiresAt) {
+			sessions[i].ExpiresAt = token.ExpiresAt
+		}
+	}
+
+	return &dto.SessionsResponse{
+		Sessions: sessions,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+)
+
+func TestListUserSessionsUseCaseGroupsTokensBySession(t *testing.T) {
+	tokens := &memoryTokenRepo{tokens: make(map[string]*entities.RefreshToken)}
+	now := time.Now()
+
+	add := func(id string, sessionID valueobjects.SessionID, started, created time.Duration, userAgent string) {
+		token := entities.NewRefreshToken("user-1", sessionID, "hash:"+id, time.Hour)
+		token.ID = id
+		token.CreatedAt = now.Add(-created)
+		token.SessionStartedAt = now.Add(-started)
+		token.DeviceName = "phone"
+		token.RecordClient(userAgent, "203.0.113.7")
+		tokens.tokens[id] = token
+	}
+
+	phone, _ := valueobjects.NewSessionID()
+	laptop, _ := valueobjects.NewSessionID()
+	add("phone-old-login", phone, 5*time.Hour, 5*time.Hour, "old")
+	add("phone-new-login", phone, 3*time.Hour, 10*time.Minute, "new")
+	add("laptop", laptop, 2*time.Hour, time.Hour, "laptop")
+
+	// Revoked tokens are not active sessions
+	other, _ := valueobjects.NewSessionID()
+	add("logged-out", other, time.Hour, time.Minute, "gone")
+	tokens.tokens["logged-out"].Revoke(entities.RevokeReasonLogout)
+
+	response, err := NewListUserSessionsUseCase(tokens).Execute(context.Background(), "user-1", laptop.String())
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+
+	if len(response.Sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2: %+v", len(response.Sessions), response.Sessions)
+	}
+
+	first, second := response.Sessions[0], response.Sessions[1]
+	if first.ID != phone.String() || second.ID != laptop.String() {
+		t.Fatalf("sessions = %s, %s, want the phone (used last) first", first.ID, second.ID)
+	}
+	if first.UserAgent != "new" || !first.LastUsedAt.Equal(now.Add(-10*time.Minute)) {
+		t.Errorf("phone user agent, last used = %q, %v, want its latest token's", first.UserAgent, first.LastUsedAt)
+	}
+	if !first.CreatedAt.Equal(now.Add(-5 * time.Hour)) {
+		t.Errorf("phone created = %v, want its earliest login", first.CreatedAt)
+	}
+	if first.Current || !second.Current {
+		t.Errorf("current = %v, %v, want only the laptop", first.Current, second.Current)
+	}
+}
@@ -3,6 +3,7 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,15 +15,26 @@ import (
 	"github.com/otp-auth/pkg/errors"
 )
 
+// GrantTypeFirstParty lets a client other than the default one log users in
+// directly, with their role's scopes and the client's audience
+const GrantTypeFirstParty = "first_party"
+
 // LoginUseCase handles user login/registration with OTP verification
 type LoginUseCase struct {
 	userRepo    repositories.UserRepository
 	otpRepo     repositories.OTPRepository
 	tokenRepo   repositories.TokenRepository
+	uow         repositories.UnitOfWork
+	denylist    repositories.AccessTokenDenylist
 	jwtService  services.JWTService
 	hashService services.HashService
 	accessTTL   time.Duration
 	refreshTTL  time.Duration
+	claims      TokenClaimsConfig
+	sessions    SessionPolicies
+	limit       SessionLimit
+	devices     deviceRecorder
+	notify      *NotifyNewLoginUseCase // optional
 }
 
 // NewLoginUseCase creates a new LoginUseCase
@@ -30,44 +42,78 @@ func NewLoginUseCase(
 	userRepo repositories.UserRepository,
 	otpRepo repositories.OTPRepository,
 	tokenRepo repositories.TokenRepository,
+	deviceRepo repositories.DeviceRepository,
+	uow repositories.UnitOfWork,
+	denylist repositories.AccessTokenDenylist,
 	jwtService services.JWTService,
 	hashService services.HashService,
 	accessTTL time.Duration,
 	refreshTTL time.Duration,
+	claims TokenClaimsConfig,
+	sessions SessionPolicies,
+	limit SessionLimit,
+	notify *NotifyNewLoginUseCase,
 ) *LoginUseCase {
 	return &LoginUseCase{
 		userRepo:    userRepo,
 		otpRepo:     otpRepo,
 		tokenRepo:   tokenRepo,
+		uow:         uow,
+		denylist:    denylist,
 		jwtService:  jwtService,
 		hashService: hashService,
 		accessTTL:   accessTTL,
 		refreshTTL:  refreshTTL,
+		claims:      claims,
+		sessions:    sessions,
+		limit:       limit,
+		devices:     deviceRecorder{repo: deviceRepo},
+		notify:      notify,
 	}
 }
 
 // Execute performs the login/registration process
 func (uc *LoginUseCase) Execute(ctx context.Context, req *dto.LoginRequest, sessionID string) (*dto.LoginResponse, error) {
+	client, err := uc.claims.resolveClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	// Other apps log users in through the authorization code flow, limited to their scopes
+	if client.ID != uc.claims.DefaultClientID && !client.AllowsGrantType(GrantTypeFirstParty) {
+		return nil, errors.NewValidationError("Client may not log users in directly", nil)
+	}
+
+	user, sessionIDObj, err := uc.authenticate(ctx, req, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.issue(ctx, req, client, user, sessionIDObj, nil, time.Now())
+}
+
+// authenticate verifies the login's OTP and returns the user, registering
+// them on their first login, and the session the OTP was sent for
+func (uc *LoginUseCase) authenticate(ctx context.Context, req *dto.LoginRequest, sessionID string) (*entities.User, valueobjects.SessionID, error) {
 	// Validate request
 	if err := req.Validate(); err != nil {
-		return nil, errors.NewValidationError("Invalid request", err)
+		return nil, "", errors.NewValidationError("Invalid request", err)
 	}
 
 	// Convert phone number string to PhoneNumber value object
 	phoneNumber, err := valueobjects.NewPhoneNumber(req.PhoneNumber)
 	if err != nil {
-		return nil, errors.NewValidationError("Invalid phone number format", err)
+		return nil, "", errors.NewValidationError("Invalid phone number format", err)
 	}
 
 	// Get OTP from repository
 	storedOTP, err := uc.otpRepo.Get(ctx, phoneNumber)
 	if err != nil {
-		return nil, errors.NewUnauthorizedError("Invalid session ID", err)
+		return nil, "", errors.NewUnauthorizedError("Invalid session ID", err)
 	}
 
 	// Verify OTP
 	if err := uc.hashService.VerifyOTP(req.OTP, storedOTP.HashedCode); err != nil {
-		return nil, errors.NewUnauthorizedError("Invalid OTP", err)
+		return nil, "", errors.NewUnauthorizedError("Invalid OTP", err)
 	}
 
 	// Note: OTP expiration is handled by Redis TTL, so if we can retrieve it, it's still valid
@@ -75,12 +121,23 @@ func (uc *LoginUseCase) Execute(ctx context.Context, req *dto.LoginRequest, sess
 	// Convert session ID string to SessionID value object
 	sessionIDObj, err := valueobjects.NewSessionIDFromString(sessionID)
 	if err != nil {
-		return nil, errors.NewValidationError("Invalid session ID format", err)
+		return nil, "", errors.NewValidationError("Invalid session ID format", err)
 	}
 
 	// Validate session ID matches
 	if storedOTP.SessionID != sessionIDObj {
-		return nil, errors.NewUnauthorizedError("Session ID mismatch", nil)
+		return nil, "", errors.NewUnauthorizedError("Session ID mismatch", nil)
+	}
+
+	// Check if user exists
+	user, err := uc.userRepo.GetByPhoneNumber(ctx, phoneNumber)
+	userExists := err == nil
+
+	// A login the session cap refuses keeps its OTP and records no device
+	if userExists {
+		if err := uc.checkSessionLimit(ctx, user, sessionIDObj); err != nil {
+			return nil, "", err
+		}
 	}
 
 	// Delete used OTP
@@ -89,25 +146,28 @@ func (uc *LoginUseCase) Execute(ctx context.Context, req *dto.LoginRequest, sess
 		// TODO: Add proper logging
 	}
 
-	// Check if user exists
-	user, err := uc.userRepo.GetByPhoneNumber(ctx, phoneNumber)
-	if err != nil {
+	if !userExists {
 		// User doesn't exist, create new user (registration)
 		user = entities.NewUser(phoneNumber)
 		user.ID = generateUserID() // Generate unique ID
 
 		if err := uc.userRepo.Create(ctx, user); err != nil {
-			return nil, errors.NewInternalError("Failed to create user", err)
+			return nil, "", errors.NewInternalError("Failed to create user", err)
 		}
 	}
 
-	// Convert user scope to scopes array
-	var scopes []string
-	if user.Scope != "" {
-		scopes = []string{user.Scope}
-	} else {
-		scopes = []string{"user"} // Default scope
-	}
+	return user, sessionIDObj, nil
+}
+
+// issue starts the session of an authenticated user: it issues the tokens for
+// client, records the device and notifies the user of unfamiliar logins. The
+// device fields and nonce are taken from req; grantedScope is nil for
+// first-party logins and authTime is when the user authenticated.
+func (uc *LoginUseCase) issue(ctx context.Context, req *dto.LoginRequest, client *entities.Client, user *entities.User, sessionIDObj valueobjects.SessionID, grantedScope *string, authTime time.Time) (*dto.LoginResponse, error) {
+	scopes, audience := uc.claims.sessionClaims(client, user, grantedScope)
+
+	accessTTL := clientTTL(client.AccessTokenTTL, uc.accessTTL)
+	refreshTTL := clientTTL(client.RefreshTokenTTL, uc.refreshTTL)
 
 	// Generate token ID for access token
 	accessTokenID, err := uc.hashService.GenerateRandomString(16)
@@ -118,12 +178,14 @@ func (uc *LoginUseCase) Execute(ctx context.Context, req *dto.LoginRequest, sess
 	// Generate access token claims
 	accessClaims := services.NewJWTClaims(
 		user.ID,
-		"otp-auth-client", // TODO: Make this configurable
+		client.ID,
 		scopes,
-		uc.accessTTL, // Access token TTL from config
-		"otp-auth",   // TODO: Make this configurable
+		accessTTL,
+		uc.claims.Issuer,
 		accessTokenID,
 	)
+	accessClaims.Audience = audience
+	accessClaims.SessionID = sessionIDObj.String()
 
 	// Generate access token
 	fmt.Println("[DEBUG] About to call GenerateToken in login use case")
@@ -132,6 +194,11 @@ func (uc *LoginUseCase) Execute(ctx context.Context, req *dto.LoginRequest, sess
 		return nil, errors.NewInternalError("Failed to generate access token", err)
 	}
 
+	idToken, err := uc.claims.idToken(uc.jwtService, user, client.ID, authTime, req.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate refresh token as a random string (not JWT)
 	refreshToken, err := uc.hashService.GenerateRandomString(32)
 	if err != nil {
@@ -149,24 +216,45 @@ func (uc *LoginUseCase) Execute(ctx context.Context, req *dto.LoginRequest, sess
 		user.ID,
 		sessionIDObj,
 		hashedRefreshToken,
-		uc.refreshTTL,
+		refreshTTL,
 	)
 	refreshTokenEntity.ID = generateTokenID() // Generate unique ID
+	refreshTokenEntity.ClientID = client.ID
+	refreshTokenEntity.Scope = grantedScope
+	refreshTokenEntity.StartFamily()
+	sighting := uc.devices.recordLogin(ctx, user.ID, req.DeviceID, req.DeviceName, req.UserAgent, req.IPAddress, req.AppVersion)
+	if sighting.device != nil {
+		refreshTokenEntity.DeviceID = sighting.device.ID
+	}
+	refreshTokenEntity.DeviceName = req.DeviceName
+	refreshTokenEntity.RecordClient(req.UserAgent, req.IPAddress)
+	refreshTokenEntity.ExpiresAt = uc.sessions.forClient(client).refreshExpiry(
+		refreshTokenEntity.SessionStartedAt, refreshTokenEntity.CreatedAt, refreshTTL)
 
-	if err := uc.tokenRepo.Create(ctx, refreshTokenEntity); err != nil {
-		return nil, errors.NewInternalError("Failed to store refresh token", err)
+	// The session cap depends on the user's role, whatever the client was granted
+	if err := uc.startSession(ctx, user.ID, roleScopes(user)[0], refreshTokenEntity); err != nil {
+		return nil, err
+	}
+
+	// Tell the user about logins from unfamiliar devices or networks; a failure
+	// here must not fail the login
+	if uc.notify != nil && sighting.unfamiliar() {
+		if err := uc.notify.Execute(ctx, user, sighting.device, sessionIDObj.String(), req.AcceptLanguage); err != nil {
+			log.Printf("[WARN] failed to send new login notification to user %s: %v", user.ID, err)
+		}
 	}
 
 	// Calculate access token expiration
 	now := time.Now()
-	expiresAt := now.Add(uc.accessTTL)
-	refreshExpiresAt := now.Add(uc.refreshTTL)
+	expiresAt := now.Add(accessTTL)
+	refreshExpiresAt := refreshTokenEntity.ExpiresAt
 
 	// Create response
 	response := &dto.LoginResponse{
 		Message:          "Login successful",
 		AccessToken:      accessToken,
 		RefreshToken:     refreshToken,
+		IDToken:          idToken,
 		ExpiresAt:        expiresAt,
 		RefreshExpiresAt: refreshExpiresAt,
 		User:             dto.NewUserInfo(user),
@@ -175,6 +263,126 @@ func (uc *LoginUseCase) Execute(ctx context.Context, req *dto.LoginRequest, sess
 	return response, nil
 }
 
+// startSession stores the login's refresh token. The session cap is enforced in
+// the same unit of work, so evictions roll back with a failed login, and the
+// evicted sessions' access tokens are denied once it commits.
+func (uc *LoginUseCase) startSession(ctx context.Context, userID, scope string, token *entities.RefreshToken) error {
+	var evicted []string
+	err := uc.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if evicted, err = uc.enforceSessionLimit(ctx, userID, scope, token.SessionID); err != nil {
+			return err
+		}
+		if err := uc.tokenRepo.Create(ctx, token); err != nil {
+			return errors.NewInternalError("Failed to store refresh token", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The login has succeeded; a failure here leaves the evicted sessions' access
+	// tokens valid until they expire
+	if err := denySessionTokens(ctx, uc.denylist, uc.accessTTL, evicted...); err != nil {
+		log.Printf("[WARN] failed to deny access tokens of sessions evicted for user %s: %v", userID, err)
+	}
+	return nil
+}
+
+// checkSessionLimit rejects a login the session cap would refuse, before any of
+// its side effects. startSession enforces the cap again, atomically.
+func (uc *LoginUseCase) checkSessionLimit(ctx context.Context, user *entities.User, sessionID valueobjects.SessionID) error {
+	maxSessions := uc.limit.MaxPerScope[roleScopes(user)[0]]
+	if maxSessions <= 0 || uc.limit.OnExceeded != SessionLimitReject {
+		return nil
+	}
+
+	others, err := uc.otherSessions(ctx, user.ID, sessionID)
+	if err != nil {
+		return err
+	}
+	if len(others)+1 > maxSessions {
+		return sessionLimitError(maxSessions)
+	}
+	return nil
+}
+
+// enforceSessionLimit keeps the user within the session cap of their scope once the
+// login's session is added, by rejecting the login or evicting the least recently
+// used sessions. It returns the evicted session IDs and must run in a unit of
+// work: the user's row stays locked so concurrent logins count one at a time.
+func (uc *LoginUseCase) enforceSessionLimit(ctx context.Context, userID, scope string, sessionID valueobjects.SessionID) ([]string, error) {
+	maxSessions := uc.limit.MaxPerScope[scope]
+	if maxSessions <= 0 {
+		return nil, nil
+	}
+
+	if _, err := uc.userRepo.GetByIDForUpdate(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	others, err := uc.otherSessions(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	excess := len(others) + 1 - maxSessions
+	if excess <= 0 {
+		return nil, nil
+	}
+
+	if uc.limit.OnExceeded == SessionLimitReject {
+		return nil, sessionLimitError(maxSessions)
+	}
+
+	evicted := others[len(others)-excess:]
+	for _, id := range evicted {
+		if err := uc.tokenRepo.RevokeSession(ctx, userID, id, entities.RevokeReasonEvicted); err != nil {
+			// The session may have been logged out since it was listed
+			if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.NotFoundError {
+				return nil, errors.NewInternalError("Failed to evict session", err)
+			}
+		}
+	}
+
+	return evicted, nil
+}
+
+// otherSessions returns the user's active sessions other than sessionID, most
+// recently used first. Logging in again to an active session doesn't add one.
+func (uc *LoginUseCase) otherSessions(ctx context.Context, userID string, sessionID valueobjects.SessionID) ([]string, error) {
+	tokens, err := uc.tokenRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokens are newest first
+	seen := map[string]bool{sessionID.String(): true}
+	var others []string
+	for _, token := range tokens {
+		id := token.SessionID.String()
+		if !seen[id] {
+			seen[id] = true
+			others = append(others, id)
+		}
+	}
+	return others, nil
+}
+
+// sessionLimitError rejects a login over the session cap
+func sessionLimitError(maxSessions int) error {
+	return errors.NewConflictError(fmt.Sprintf("Maximum of %d active sessions reached, log out of another session first", maxSessions), nil)
+}
+
+// roleScopes returns the scopes of the user's role, carried by first-party access tokens
+func roleScopes(user *entities.User) []string {
+	if user.Scope != "" {
+		return []string{user.Scope}
+	}
+	return []string{"user"} // Default scope
+}
+
 // generateUserID generates a unique user ID
 func generateUserID() string {
 	return uuid.New().String()
@@ -0,0 +1,210 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+type sessionLimitFixture struct {
+	uc       *LoginUseCase
+	tokens   *memoryTokenRepo
+	denylist *memoryDenylist
+	sessions []*entities.RefreshToken
+}
+
+// newSessionLimitFixture stores one active session for each age, last used that long ago
+func newSessionLimitFixture(t *testing.T, limit SessionLimit, ages ...time.Duration) *sessionLimitFixture {
+	t.Helper()
+
+	tokens := &memoryTokenRepo{tokens: make(map[string]*entities.RefreshToken)}
+	var sessions []*entities.RefreshToken
+	for _, age := range ages {
+		token := entities.NewRefreshToken("user-1", newTestSessionID(t), "hash", time.Hour)
+		token.ID = token.SessionID.String()
+		token.CreatedAt = time.Now().Add(-age)
+		tokens.tokens[token.ID] = token
+		sessions = append(sessions, token)
+	}
+
+	denylist := &memoryDenylist{denied: map[string]bool{}}
+	uc := &LoginUseCase{
+		userRepo:  &staticUserRepo{user: &entities.User{ID: "user-1"}},
+		tokenRepo: tokens,
+		uow:       &memoryUnitOfWork{tokens: tokens},
+		denylist:  denylist,
+		accessTTL: 15 * time.Minute,
+		limit:     limit,
+	}
+	return &sessionLimitFixture{uc: uc, tokens: tokens, denylist: denylist, sessions: sessions}
+}
+
+func newTestSessionID(t *testing.T) valueobjects.SessionID {
+	t.Helper()
+	sessionID, err := valueobjects.NewSessionID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sessionID
+}
+
+// newLoginToken returns the refresh token of a login to a new session
+func newLoginToken(t *testing.T) *entities.RefreshToken {
+	t.Helper()
+	token := entities.NewRefreshToken("user-1", newTestSessionID(t), "hash:new", time.Hour)
+	token.ID = "new"
+	return token
+}
+
+func TestLoginUseCaseEvictsLeastRecentlyUsedSessions(t *testing.T) {
+	limit := SessionLimit{MaxPerScope: map[string]int{"user": 2}, OnExceeded: SessionLimitEvict}
+	f := newSessionLimitFixture(t, limit, 3*time.Hour, time.Hour, 2*time.Hour)
+
+	if err := f.uc.startSession(context.Background(), "user-1", "user", newLoginToken(t)); err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	if _, ok := f.tokens.tokens["new"]; !ok {
+		t.Error("new session not stored")
+	}
+
+	// Room for one more session: only the most recently used one stays
+	for i, wantRevoked := range []bool{true, false, true} {
+		session := f.tokens.tokens[f.sessions[i].ID]
+		if session.Revoked != wantRevoked {
+			t.Errorf("session %d revoked = %v, want %v", i, session.Revoked, wantRevoked)
+		}
+		if wantRevoked && session.RevokeReason != entities.RevokeReasonEvicted {
+			t.Errorf("session %d reason = %q, want %q", i, session.RevokeReason, entities.RevokeReasonEvicted)
+		}
+		if denied := sessionDenied(t, f.denylist, session.SessionID); denied != wantRevoked {
+			t.Errorf("session %d access tokens denied = %v, want %v", i, denied, wantRevoked)
+		}
+	}
+}
+
+// failingCreateTokenRepo fails to store new refresh tokens
+type failingCreateTokenRepo struct{ *memoryTokenRepo }
+
+func (r failingCreateTokenRepo) Create(ctx context.Context, token *entities.RefreshToken) error {
+	return fmt.Errorf("database unavailable")
+}
+
+func TestLoginUseCaseKeepsSessionsWhenLoginFails(t *testing.T) {
+	limit := SessionLimit{MaxPerScope: map[string]int{"user": 1}, OnExceeded: SessionLimitEvict}
+	f := newSessionLimitFixture(t, limit, time.Hour)
+	f.uc.tokenRepo = failingCreateTokenRepo{f.tokens}
+
+	if err := f.uc.startSession(context.Background(), "user-1", "user", newLoginToken(t)); err == nil {
+		t.Fatal("start session succeeded without storing the refresh token")
+	}
+	if session := f.tokens.tokens[f.sessions[0].ID]; session.Revoked {
+		t.Error("session evicted by a failed login")
+	}
+	if sessionDenied(t, f.denylist, f.sessions[0].SessionID) {
+		t.Error("access tokens denied by a failed login")
+	}
+}
+
+func TestLoginUseCaseRejectsLoginOverSessionLimit(t *testing.T) {
+	limit := SessionLimit{MaxPerScope: map[string]int{"admin": 1}, OnExceeded: SessionLimitReject}
+	f := newSessionLimitFixture(t, limit, time.Hour)
+
+	err := f.uc.startSession(context.Background(), "user-1", "admin", newLoginToken(t))
+	if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.ConflictError {
+		t.Fatalf("error = %v, want a conflict", err)
+	}
+	if f.sessions[0].Revoked {
+		t.Error("existing session was revoked on a rejected login")
+	}
+	if _, ok := f.tokens.tokens["new"]; ok {
+		t.Error("rejected session stored")
+	}
+
+	// Logging in again to the active session doesn't count as a new one
+	if _, err := f.uc.enforceSessionLimit(context.Background(), "user-1", "admin", f.sessions[0].SessionID); err != nil {
+		t.Errorf("login to the active session: %v", err)
+	}
+
+	// Scopes without a cap are unlimited
+	if _, err := f.uc.enforceSessionLimit(context.Background(), "user-1", "user", newTestSessionID(t)); err != nil {
+		t.Errorf("uncapped scope: %v", err)
+	}
+}
+
+type memoryOTPRepo struct {
+	repositories.OTPRepository
+	otps map[string]*entities.OTP
+}
+
+func (r *memoryOTPRepo) Get(ctx context.Context, phoneNumber valueobjects.PhoneNumber) (*entities.OTP, error) {
+	otp, ok := r.otps[phoneNumber.String()]
+	if !ok {
+		return nil, errors.NewNotFoundError("OTP not found", nil)
+	}
+	return otp, nil
+}
+
+func (r *memoryOTPRepo) Delete(ctx context.Context, phoneNumber valueobjects.PhoneNumber) error {
+	delete(r.otps, phoneNumber.String())
+	return nil
+}
+
+func TestLoginUseCaseKeepsOTPWhenOverSessionLimit(t *testing.T) {
+	limit := SessionLimit{MaxPerScope: map[string]int{"user": 1}, OnExceeded: SessionLimitReject}
+	f := newSessionLimitFixture(t, limit, time.Hour)
+
+	phoneNumber, err := valueobjects.NewPhoneNumber("+989123456789")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionID := newTestSessionID(t)
+	otps := &memoryOTPRepo{otps: map[string]*entities.OTP{
+		phoneNumber.String(): entities.NewOTP(phoneNumber, sessionID, "hash:123456", time.Minute),
+	}}
+	f.uc.otpRepo = otps
+	f.uc.hashService = &fakeHashService{}
+
+	req := &dto.LoginRequest{PhoneNumber: phoneNumber.String(), OTP: "123456"}
+	_, _, err = f.uc.authenticate(context.Background(), req, sessionID.String())
+	if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.ConflictError {
+		t.Fatalf("error = %v, want a conflict", err)
+	}
+	if _, ok := otps.otps[phoneNumber.String()]; !ok {
+		t.Error("OTP used up by a rejected login")
+	}
+
+	// Evicting logins go ahead and use the OTP up
+	f.uc.limit.OnExceeded = SessionLimitEvict
+	if _, _, err := f.uc.authenticate(context.Background(), req, sessionID.String()); err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if _, ok := otps.otps[phoneNumber.String()]; ok {
+		t.Error("OTP kept after a login")
+	}
+}
+
+func TestLoginUseCaseRejectsClientsWithoutFirstPartyGrant(t *testing.T) {
+	partner := entities.NewClient("partner-app")
+	service := entities.NewClient("api-gateway")
+	service.AllowedGrantTypes = []string{GrantTypeClientCredentials}
+	uc := &LoginUseCase{claims: TokenClaimsConfig{
+		DefaultClientID: "otp-auth-client",
+		Clients:         &staticClientRepo{clients: map[string]*entities.Client{partner.ID: partner, service.ID: service}},
+	}}
+
+	// Rejected before the OTP is checked, so the test needs no OTP repository
+	for _, clientID := range []string{partner.ID, service.ID} {
+		req := &dto.LoginRequest{PhoneNumber: "+989123456789", OTP: "123456", ClientID: clientID}
+		_, err := uc.Execute(context.Background(), req, newTestSessionID(t).String())
+		if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.ValidationError {
+			t.Errorf("login with %s: error = %v, want a validation error", clientID, err)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package usecases
 
 import (
 	"context"
+	"log"
+	"time"
 
 	"github.com/otp-auth/internal/application/dto"
 	"github.com/otp-auth/internal/application/ports/repositories"
@@ -13,22 +15,38 @@ import (
 // LogoutUseCase handles user logout operations
 type LogoutUseCase struct {
 	tokenRepo   repositories.TokenRepository
+	denylist    repositories.AccessTokenDenylist
+	jwtService  services.JWTService
 	hashService services.HashService
 }
 
 // NewLogoutUseCase creates a new LogoutUseCase
 func NewLogoutUseCase(
 	tokenRepo repositories.TokenRepository,
+	denylist repositories.AccessTokenDenylist,
+	jwtService services.JWTService,
 	hashService services.HashService,
 ) *LogoutUseCase {
 	return &LogoutUseCase{
 		tokenRepo:   tokenRepo,
+		denylist:    denylist,
+		jwtService:  jwtService,
 		hashService: hashService,
 	}
 }
 
-// Execute performs the logout operation by revoking the refresh token
+// Execute performs the logout operation by revoking the refresh token and the access token
 func (uc *LogoutUseCase) Execute(ctx context.Context, req *dto.LogoutRequest) (*dto.LogoutResponse, error) {
+	// Deny the access token for the rest of its lifetime; an invalid or
+	// expired one can't be used anyway
+	if req.AccessToken != "" {
+		if claims, err := uc.jwtService.VerifyToken(req.AccessToken); err == nil && claims.TokenID != "" {
+			if err := uc.denylist.Deny(ctx, claims.TokenID, time.Unix(claims.ExpiresAt, 0)); err != nil {
+				log.Printf("[WARN] Failed to deny access token on logout: %v", err)
+			}
+		}
+	}
+
 	// If no refresh token provided, just return success (idempotent operation)
 	if req.RefreshToken == "" && req.SessionID == "" {
 		return &dto.LogoutResponse{
@@ -39,7 +57,7 @@ func (uc *LogoutUseCase) Execute(ctx context.Context, req *dto.LogoutRequest) (*
 	// If refresh token is provided, try to revoke it
 	if req.RefreshToken != "" {
 		// Hash the refresh token to match stored hash
-		tokenHash, err := uc.hashService.Hash(req.RefreshToken)
+		tokenHash, err := uc.hashService.HashRefreshToken(req.RefreshToken)
 		if err != nil {
 			return nil, errors.NewInternalError("Failed to hash refresh token", err)
 		}
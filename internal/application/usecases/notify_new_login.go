@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// LoginNotificationPolicy controls new-login notifications
+type LoginNotificationPolicy struct {
+	RevokeURL     string        // link target; the one-time token is added as the "token" query parameter
+	RevokeLinkTTL time.Duration // how long the link in a notification stays usable
+	Limit         int           // notifications per user per window
+	Window        time.Duration
+	DefaultLocale string // used when the login's Accept-Language names no supported language
+}
+
+// loginNotificationText holds the new-login notification in one language
+type loginNotificationText struct {
+	message       string // formatted with the login IP, the device and the revoke link
+	device        string // formatted with the browser and the OS
+	unknownDevice string
+}
+
+// loginNotificationTexts holds the supported languages, keyed by primary language subtag
+var loginNotificationTexts = map[string]loginNotificationText{
+	"en": {
+		message:       "New login from %s on %s, not you? Revoke: %s",
+		device:        "%s on %s",
+		unknownDevice: "an unknown device",
+	},
+	"fa": {
+		message:       "ورود جدید از %s با %s، شما نبودید؟ لغو: %s",
+		device:        "%s روی %s",
+		unknownDevice: "دستگاه ناشناس",
+	},
+}
+
+// NotifyNewLoginUseCase tells users about logins from a device or network
+// they haven't used before, with a one-time link that ends the new session
+type NotifyNewLoginUseCase struct {
+	sender      services.NotificationSender
+	linkRepo    repositories.RevokeLinkRepository
+	rateLimiter repositories.RateLimiter
+	hashService services.HashService
+	policy      LoginNotificationPolicy
+}
+
+// NewNotifyNewLoginUseCase creates a new NotifyNewLoginUseCase
+func NewNotifyNewLoginUseCase(
+	sender services.NotificationSender,
+	linkRepo repositories.RevokeLinkRepository,
+	rateLimiter repositories.RateLimiter,
+	hashService services.HashService,
+	policy LoginNotificationPolicy,
+) *NotifyNewLoginUseCase {
+	return &NotifyNewLoginUseCase{
+		sender:      sender,
+		linkRepo:    linkRepo,
+		rateLimiter: rateLimiter,
+		hashService: hashService,
+		policy:      policy,
+	}
+}
+
+// Execute notifies the user of a login to the session from the device.
+// acceptLanguage is the login request's Accept-Language header.
+func (uc *NotifyNewLoginUseCase) Execute(ctx context.Context, user *entities.User, device *entities.Device, sessionID, acceptLanguage string) error {
+	allowed, _, err := uc.rateLimiter.CheckAndIncrement(ctx, "login_notification:"+user.ID, uc.policy.Limit, uc.policy.Window)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		log.Printf("[WARN] new login notifications for user %s are rate limited, skipping", user.ID)
+		return nil
+	}
+
+	link, err := uc.newRevokeLink(ctx, user.ID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	text := loginNotificationTexts[matchLocale(acceptLanguage, uc.policy.DefaultLocale)]
+	message := fmt.Sprintf(text.message, device.LastIP, text.describe(device), link)
+
+	if err := uc.sender.SendNotification(ctx, user.PhoneNumber, message); err != nil {
+		return errors.NewInternalError("Failed to send new login notification", err)
+	}
+
+	return nil
+}
+
+// newRevokeLink stores a one-time token for the session and returns the link carrying it
+func (uc *NotifyNewLoginUseCase) newRevokeLink(ctx context.Context, userID, sessionID string) (string, error) {
+	token, err := uc.hashService.GenerateRandomString(32)
+	if err != nil {
+		return "", errors.NewInternalError("Failed to generate revoke link token", err)
+	}
+
+	// Stored as a SHA-256 hash, like refresh tokens
+	tokenHash, err := uc.hashService.HashRefreshToken(token)
+	if err != nil {
+		return "", errors.NewInternalError("Failed to hash revoke link token", err)
+	}
+
+	if err := uc.linkRepo.Save(ctx, tokenHash, userID, sessionID, uc.policy.RevokeLinkTTL); err != nil {
+		return "", err
+	}
+
+	link, err := url.Parse(uc.policy.RevokeURL)
+	if err != nil {
+		return "", errors.NewInternalError("Invalid revoke link URL", err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String(), nil
+}
+
+// describe names the device by its client-given name, else by browser and OS
+func (t loginNotificationText) describe(device *entities.Device) string {
+	switch {
+	case device.Name != "":
+		return device.Name
+	case device.Browser != "" && device.OS != "":
+		return fmt.Sprintf(t.device, device.Browser, device.OS)
+	case device.Browser != "":
+		return device.Browser
+	case device.OS != "":
+		return device.OS
+	default:
+		return t.unknownDevice
+	}
+}
+
+// matchLocale picks the first supported language of an Accept-Language header,
+// falling back to defaultLocale and then English
+func matchLocale(acceptLanguage, defaultLocale string) string {
+	for _, tag := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ = strings.Cut(tag, ";") // drop the quality value
+		language, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+		language = strings.ToLower(language)
+		if _, ok := loginNotificationTexts[language]; ok {
+			return language
+		}
+	}
+	if _, ok := loginNotificationTexts[defaultLocale]; ok {
+		return defaultLocale
+	}
+	return "en"
+}
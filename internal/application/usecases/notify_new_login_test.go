@@ -0,0 +1,196 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+type recordingNotificationSender struct{ messages []string }
+
+func (s *recordingNotificationSender) SendNotification(ctx context.Context, phoneNumber valueobjects.PhoneNumber, message string) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+// memoryRevokeLinkRepo stores user and session IDs by token hash
+type memoryRevokeLinkRepo struct{ links map[string][2]string }
+
+func (r *memoryRevokeLinkRepo) Save(ctx context.Context, tokenHash, userID, sessionID string, ttl time.Duration) error {
+	r.links[tokenHash] = [2]string{userID, sessionID}
+	return nil
+}
+
+func (r *memoryRevokeLinkRepo) Consume(ctx context.Context, tokenHash string) (string, string, error) {
+	link, ok := r.links[tokenHash]
+	if !ok {
+		return "", "", errors.NewNotFoundError("Revoke link not found", nil)
+	}
+	delete(r.links, tokenHash)
+	return link[0], link[1], nil
+}
+
+// countingRateLimiter allows limit calls per key, ignoring the window
+type countingRateLimiter struct {
+	repositories.RateLimiter
+	counts map[string]int
+}
+
+func (l *countingRateLimiter) CheckAndIncrement(ctx context.Context, key string, limit int, window time.Duration) (bool, int, error) {
+	l.counts[key]++
+	return l.counts[key] <= limit, l.counts[key], nil
+}
+
+type notifyFixture struct {
+	uc     *NotifyNewLoginUseCase
+	sender *recordingNotificationSender
+	links  *memoryRevokeLinkRepo
+	user   *entities.User
+	device *entities.Device
+}
+
+func newNotifyFixture(limit int) *notifyFixture {
+	sender := &recordingNotificationSender{}
+	links := &memoryRevokeLinkRepo{links: make(map[string][2]string)}
+	uc := NewNotifyNewLoginUseCase(sender, links,
+		&countingRateLimiter{counts: make(map[string]int)}, &fakeHashService{},
+		LoginNotificationPolicy{
+			RevokeURL:     "https://auth.example.com/revoke?source=sms",
+			RevokeLinkTTL: time.Hour,
+			Limit:         limit,
+			Window:        time.Hour,
+			DefaultLocale: "en",
+		})
+
+	device := entities.NewDevice("user-1", "", "")
+	device.Seen("", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0.0.0 Safari/537.36", "203.0.113.7", "")
+
+	return &notifyFixture{
+		uc:     uc,
+		sender: sender,
+		links:  links,
+		user:   &entities.User{ID: "user-1", PhoneNumber: "+989123456789"},
+		device: device,
+	}
+}
+
+func TestNotifyNewLoginUseCaseLinkRevokesSessionOnce(t *testing.T) {
+	f := newNotifyFixture(5)
+
+	tokens := &memoryTokenRepo{tokens: make(map[string]*entities.RefreshToken)}
+	sessionID, _ := valueobjects.NewSessionID()
+	login := entities.NewRefreshToken("user-1", sessionID, "hash:login", time.Hour)
+	login.ID = "login"
+	tokens.tokens[login.ID] = login
+
+	if err := f.uc.Execute(context.Background(), f.user, f.device, sessionID.String(), "en-US,en;q=0.9"); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	want := "New login from 203.0.113.7 on Chrome on Windows, not you? Revoke: https://auth.example.com/revoke?source=sms&token=random-1"
+	if len(f.sender.messages) != 1 || f.sender.messages[0] != want {
+		t.Fatalf("messages = %q, want %q", f.sender.messages, want)
+	}
+
+	events := &recordingEventPublisher{}
+	denylist := &memoryDenylist{denied: map[string]bool{}}
+	revoke := NewRevokeSessionByLinkUseCase(f.links, tokens, denylist, &fakeHashService{}, events, 15*time.Minute)
+
+	if _, err := revoke.Execute(context.Background(), "random-1"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if !login.Revoked || login.RevokeReason != entities.RevokeReasonNotMe {
+		t.Errorf("revoked, reason = %v, %q, want a %s revocation", login.Revoked, login.RevokeReason, entities.RevokeReasonNotMe)
+	}
+	if denied, _ := denylist.IsDenied(context.Background(), "jti", "user-1", sessionID.String(), time.Now()); !denied {
+		t.Error("session's access tokens still valid")
+	}
+	if len(events.events) != 1 || events.events[0].SessionID != sessionID.String() {
+		t.Errorf("events = %+v, want one for the session", events.events)
+	}
+
+	_, err := revoke.Execute(context.Background(), "random-1")
+	if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.GoneError {
+		t.Errorf("second use error = %v, want gone", err)
+	}
+}
+
+func TestNotifyNewLoginUseCaseIsRateLimited(t *testing.T) {
+	f := newNotifyFixture(1)
+
+	for i := 0; i < 3; i++ {
+		if err := f.uc.Execute(context.Background(), f.user, f.device, "session", ""); err != nil {
+			t.Fatalf("notify: %v", err)
+		}
+	}
+
+	if len(f.sender.messages) != 1 {
+		t.Errorf("sent %d notifications, want 1", len(f.sender.messages))
+	}
+}
+
+func TestNotifyNewLoginUseCaseIsLocalized(t *testing.T) {
+	f := newNotifyFixture(5)
+	f.device.Name = "Pixel 8"
+
+	if err := f.uc.Execute(context.Background(), f.user, f.device, "session", "fa-IR,fa;q=0.9,en;q=0.8"); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	want := "ورود جدید از 203.0.113.7 با Pixel 8، شما نبودید؟ لغو: https://auth.example.com/revoke?source=sms&token=random-1"
+	if len(f.sender.messages) != 1 || f.sender.messages[0] != want {
+		t.Errorf("messages = %q, want %q", f.sender.messages, want)
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		defaultLocale  string
+		want           string
+	}{
+		{"fa-IR,fa;q=0.9,en;q=0.8", "en", "fa"},
+		{"de-DE, EN-us;q=0.7", "fa", "en"},
+		{"de-DE", "fa", "fa"},
+		{"", "xx", "en"},
+	}
+
+	for _, tt := range tests {
+		if got := matchLocale(tt.acceptLanguage, tt.defaultLocale); got != tt.want {
+			t.Errorf("matchLocale(%q, %q) = %q, want %q", tt.acceptLanguage, tt.defaultLocale, got, tt.want)
+		}
+	}
+}
+
+func TestRecordLoginReportsUnfamiliarLogins(t *testing.T) {
+	recorder := deviceRecorder{repo: &memoryDeviceRepo{devices: make(map[string]*entities.Device)}}
+	ctx := context.Background()
+	const phone = "okhttp/4.12.0"
+
+	tests := []struct {
+		name       string
+		declaredID string
+		ip         string
+		unfamiliar bool
+	}{
+		{"first login", "phone", "203.0.113.7", false},
+		{"same device, same network", "phone", "203.0.113.99", false},
+		{"same device, new network", "phone", "198.51.100.23", true},
+		{"new device, known network", "tablet", "198.51.100.24", true},
+	}
+
+	for _, tt := range tests {
+		sighting := recorder.recordLogin(ctx, "user-1", tt.declaredID, "", phone, tt.ip, "")
+		if sighting.device == nil {
+			t.Fatalf("%s: device was not recorded", tt.name)
+		}
+		if sighting.unfamiliar() != tt.unfamiliar {
+			t.Errorf("%s: unfamiliar = %v, want %v (%+v)", tt.name, sighting.unfamiliar(), tt.unfamiliar, sighting)
+		}
+	}
+}
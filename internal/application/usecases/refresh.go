@@ -17,28 +17,49 @@ import (
 type RefreshUseCase struct {
 	userRepo    repositories.UserRepository
 	tokenRepo   repositories.TokenRepository
+	devices     deviceRecorder
+	uow         repositories.UnitOfWork
+	denylist    repositories.AccessTokenDenylist
 	jwtService  services.JWTService
 	hashService services.HashService
 	accessTTL   time.Duration
 	refreshTTL  time.Duration
+	claims      TokenClaimsConfig
+	sessions    SessionPolicies
+	events      services.SecurityEventPublisher
+	reuseGrace  time.Duration
 }
 
 // NewRefreshUseCase creates a new RefreshUseCase
 func NewRefreshUseCase(
 	userRepo repositories.UserRepository,
 	tokenRepo repositories.TokenRepository,
+	deviceRepo repositories.DeviceRepository,
+	uow repositories.UnitOfWork,
+	denylist repositories.AccessTokenDenylist,
 	jwtService services.JWTService,
 	hashService services.HashService,
 	accessTTL time.Duration,
 	refreshTTL time.Duration,
+	claims TokenClaimsConfig,
+	sessions SessionPolicies,
+	events services.SecurityEventPublisher,
+	reuseGrace time.Duration,
 ) *RefreshUseCase {
 	return &RefreshUseCase{
 		userRepo:    userRepo,
 		tokenRepo:   tokenRepo,
+		devices:     deviceRecorder{repo: deviceRepo},
+		uow:         uow,
+		denylist:    denylist,
 		jwtService:  jwtService,
 		hashService: hashService,
 		accessTTL:   accessTTL,
 		refreshTTL:  refreshTTL,
+		claims:      claims,
+		sessions:    sessions,
+		events:      events,
+		reuseGrace:  reuseGrace,
 	}
 }
 
@@ -61,30 +82,72 @@ func (uc *RefreshUseCase) Execute(ctx context.Context, req *dto.RefreshTokenRequ
 		return nil, errors.NewInternalError("Failed to hash refresh token", err)
 	}
 
-	// Get refresh token from repository
-	storedToken, err := uc.tokenRepo.GetByTokenHashAndSessionID(ctx, hashedRefreshToken, sessionIDObj)
+	// Rotate in one transaction: the old token's row stays locked until the new
+	// token is stored, so parallel refreshes with the same token can't both succeed
+	var storedToken *entities.RefreshToken
+	var response *dto.RefreshTokenResponse
+	err = uc.uow.Do(ctx, func(ctx context.Context) error {
+		token, err := uc.tokenRepo.GetByTokenHashAndSessionIDForUpdate(ctx, hashedRefreshToken, sessionIDObj)
+		if err != nil {
+			return errors.NewUnauthorizedError("Invalid refresh token", err)
+		}
+		storedToken = token
+
+		// Invalid tokens are handled after the transaction, so a family
+		// revoked for reuse isn't rolled back with the rejected refresh
+		if !token.IsValid() {
+			return nil
+		}
+
+		// Keep the client the session was started with
+		client, err := uc.claims.resolveClient(ctx, token.ClientID)
+		if err != nil {
+			if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.ValidationError {
+				return errors.NewUnauthorizedError("Refresh token client is no longer allowed", err)
+			}
+			return err
+		}
+
+		// Enforce the session's lifetime limits under the current policy
+		if uc.sessions.forClient(client).expired(token, time.Now()) {
+			return errors.NewUnauthorizedError("Session has expired, please log in again", nil)
+		}
+
+		response, err = uc.rotate(ctx, req, token, client)
+		return err
+	})
 	if err != nil {
-		return nil, errors.NewUnauthorizedError("Invalid refresh token", err)
+		return nil, err
 	}
 
 	// Validate refresh token
-	if !storedToken.IsValid() {
+	if response == nil {
+		if storedToken.Revoked {
+			if err := uc.detectReuse(ctx, storedToken); err != nil {
+				return nil, err
+			}
+		}
 		return nil, errors.NewUnauthorizedError("Refresh token is expired or revoked", nil)
 	}
 
+	// Outside the transaction, so a device update failure can't roll back the rotation
+	uc.devices.recordRefresh(ctx, storedToken.DeviceID, req.UserAgent, req.IPAddress, req.AppVersion)
+
+	return response, nil
+}
+
+// rotate replaces a valid refresh token with a new one and issues a new access token
+// for client
+func (uc *RefreshUseCase) rotate(ctx context.Context, req *dto.RefreshTokenRequest, storedToken *entities.RefreshToken, client *entities.Client) (*dto.RefreshTokenResponse, error) {
 	// Get user information
 	user, err := uc.userRepo.GetByID(ctx, storedToken.UserID)
 	if err != nil {
 		return nil, errors.NewUnauthorizedError("User not found", err)
 	}
 
-	// Convert user scope to scopes array
-	var scopes []string
-	if user.Scope != "" {
-		scopes = []string{user.Scope}
-	} else {
-		scopes = []string{"user"} // Default scope
-	}
+	accessTTL := clientTTL(client.AccessTokenTTL, uc.accessTTL)
+	refreshTTL := clientTTL(client.RefreshTokenTTL, uc.refreshTTL)
+	scopes, audience := uc.claims.sessionClaims(client, user, storedToken.Scope)
 
 	// Generate new access token ID
 	accessTokenID, err := uc.hashService.GenerateRandomString(16)
@@ -95,12 +158,14 @@ func (uc *RefreshUseCase) Execute(ctx context.Context, req *dto.RefreshTokenRequ
 	// Generate new access token claims
 	accessClaims := services.NewJWTClaims(
 		user.ID,
-		"otp-auth-client", // TODO: Make this configurable
+		client.ID,
 		scopes,
-		uc.accessTTL,
-		"otp-auth", // TODO: Make this configurable
+		accessTTL,
+		uc.claims.Issuer,
 		accessTokenID,
 	)
+	accessClaims.Audience = audience
+	accessClaims.SessionID = storedToken.SessionID.String()
 
 	// Generate new access token
 	accessToken, err := uc.jwtService.GenerateToken(accessClaims)
@@ -108,6 +173,12 @@ func (uc *RefreshUseCase) Execute(ctx context.Context, req *dto.RefreshTokenRequ
 		return nil, errors.NewInternalError("Failed to generate access token", err)
 	}
 
+	// The user authenticated when the session started, not at this refresh
+	idToken, err := uc.claims.idToken(uc.jwtService, user, client.ID, storedToken.SessionStartedAt, "")
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate new refresh token
 	newRefreshToken, err := uc.hashService.GenerateRandomString(32)
 	if err != nil {
@@ -120,46 +191,93 @@ func (uc *RefreshUseCase) Execute(ctx context.Context, req *dto.RefreshTokenRequ
 		return nil, errors.NewInternalError("Failed to hash new refresh token", err)
 	}
 
-	// Revoke the old refresh token
-	if err := uc.tokenRepo.RevokeByTokenHashAndSessionID(ctx, hashedRefreshToken, sessionIDObj, entities.RevokeReasonRefresh); err != nil {
-		// Log error but don't fail the refresh operation
-		log.Printf("revoke error: %v", err)
-		// TODO: Add proper logging
+	// Record the last use, then revoke the old refresh token
+	storedToken.UpdateLastUsed()
+	if err := uc.tokenRepo.Update(ctx, storedToken); err != nil {
+		return nil, err
+	}
+	if err := uc.tokenRepo.RevokeByTokenHashAndSessionID(ctx, storedToken.TokenHash, storedToken.SessionID, entities.RevokeReasonRefresh); err != nil {
+		return nil, err
 	}
 
 	// Create new refresh token entity
 	newRefreshTokenEntity := entities.NewRefreshToken(
 		user.ID,
-		sessionIDObj,
+		storedToken.SessionID,
 		hashedNewRefreshToken,
-		uc.refreshTTL,
+		refreshTTL,
 	)
 	newRefreshTokenEntity.ID = generateTokenID() // Generate unique ID
+	newRefreshTokenEntity.ClientID = client.ID
+	newRefreshTokenEntity.InheritFamily(storedToken)
+	newRefreshTokenEntity.RecordClient(storedToken.UserAgent, storedToken.IPAddress)
+	newRefreshTokenEntity.RecordClient(req.UserAgent, req.IPAddress)
+	newRefreshTokenEntity.ExpiresAt = uc.sessions.forClient(client).refreshExpiry(
+		newRefreshTokenEntity.SessionStartedAt, newRefreshTokenEntity.CreatedAt, refreshTTL)
 
 	// Store new refresh token
 	if err := uc.tokenRepo.Create(ctx, newRefreshTokenEntity); err != nil {
 		return nil, errors.NewInternalError("Failed to store new refresh token", err)
 	}
 
-	// Update last used timestamp for the old token (before revocation)
-	storedToken.UpdateLastUsed()
-	if err := uc.tokenRepo.Update(ctx, storedToken); err != nil {
-		// Log error but don't fail the refresh operation
-		// TODO: Add proper logging
-	}
-
 	// Calculate access token expiration
-	expiresAt := time.Now().Add(uc.accessTTL)
-	refreshExpiresAt := time.Now().Add(uc.refreshTTL)
+	expiresAt := time.Now().Add(accessTTL)
+	refreshExpiresAt := newRefreshTokenEntity.ExpiresAt
 
 	// Create response
 	response := &dto.RefreshTokenResponse{
 		Message:          "Token refreshed successfully",
 		AccessToken:      accessToken,
 		RefreshToken:     newRefreshToken,
+		IDToken:          idToken,
 		ExpiresAt:        expiresAt,
 		RefreshExpiresAt: refreshExpiresAt,
 	}
 
 	return response, nil
 }
+
+// detectReuse handles a revoked refresh token being presented again. If it was
+// revoked by rotation, someone else may hold a copy: the whole family is revoked,
+// its access tokens denied and a security event raised (OAuth 2.0 Security BCP,
+// section 4.14). Within the grace window the reuse is treated as a benign client
+// race and only rejected.
+func (uc *RefreshUseCase) detectReuse(ctx context.Context, token *entities.RefreshToken) error {
+	if token.RevokeReason != entities.RevokeReasonRefresh {
+		// Revoked by logout, an admin or an earlier reuse, not rotated
+		return nil
+	}
+
+	now := time.Now()
+	if token.RevokedWithin(now, uc.reuseGrace) {
+		return errors.NewUnauthorizedError("Refresh token was already rotated", nil)
+	}
+
+	if err := uc.tokenRepo.RevokeFamily(ctx, token.FamilyID, entities.RevokeReasonReuse); err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.NotFoundError {
+			// The session already ended, e.g. when this token was reused before
+			return nil
+		}
+		return errors.NewInternalError("Failed to revoke refresh token family", err)
+	}
+	if err := denySessionTokens(ctx, uc.denylist, uc.accessTTL, token.SessionID.String()); err != nil {
+		return errors.NewInternalError("Failed to deny access tokens of reused refresh token", err)
+	}
+
+	event := services.SecurityEvent{
+		Type:      services.SecurityEventRefreshTokenReuse,
+		UserID:    token.UserID,
+		SessionID: token.SessionID.String(),
+		ClientID:  token.ClientID,
+		Details: map[string]string{
+			"family_id": token.FamilyID,
+			"token_id":  token.ID,
+		},
+		OccurredAt: now,
+	}
+	if err := uc.events.Publish(ctx, event); err != nil {
+		log.Printf("[WARN] failed to publish refresh token reuse event: %v", err)
+	}
+
+	return errors.NewUnauthorizedError("Refresh token reuse detected; session revoked", nil)
+}
@@ -0,0 +1,572 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// memoryTokenRepo implements the refresh token methods used by the refresh flow
+type memoryTokenRepo struct {
+	repositories.TokenRepository
+	tokens     map[string]*entities.RefreshToken
+	failCreate bool
+}
+
+func (r *memoryTokenRepo) GetByTokenHashAndSessionID(ctx context.Context, tokenHash string, sessionID valueobjects.SessionID) (*entities.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash && token.SessionID == sessionID {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("Refresh token not found", nil)
+}
+
+func (r *memoryTokenRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("Refresh token not found", nil)
+}
+
+func (r *memoryTokenRepo) GetByTokenHashAndSessionIDForUpdate(ctx context.Context, tokenHash string, sessionID valueobjects.SessionID) (*entities.RefreshToken, error) {
+	return r.GetByTokenHashAndSessionID(ctx, tokenHash, sessionID)
+}
+
+func (r *memoryTokenRepo) GetActiveByUserID(ctx context.Context, userID string) ([]*entities.RefreshToken, error) {
+	var tokens []*entities.RefreshToken
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.IsValid() {
+			copied := *token
+			tokens = append(tokens, &copied)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt.After(tokens[j].CreatedAt) })
+	return tokens, nil
+}
+
+func (r *memoryTokenRepo) Create(ctx context.Context, token *entities.RefreshToken) error {
+	if r.failCreate {
+		return errors.NewInternalError("Failed to create refresh token", nil)
+	}
+	copied := *token
+	r.tokens[token.ID] = &copied
+	return nil
+}
+
+func (r *memoryTokenRepo) Update(ctx context.Context, token *entities.RefreshToken) error {
+	return nil
+}
+
+func (r *memoryTokenRepo) RevokeByTokenHashAndSessionID(ctx context.Context, tokenHash string, sessionID valueobjects.SessionID, reason string) error {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash && token.SessionID == sessionID && !token.Revoked {
+			token.Revoke(reason)
+		}
+	}
+	return nil
+}
+
+func (r *memoryTokenRepo) RevokeByTokenHash(ctx context.Context, tokenHash string, reason string) error {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash && !token.Revoked {
+			token.Revoke(reason)
+			return nil
+		}
+	}
+	return errors.NewNotFoundError("Refresh token not found", nil)
+}
+
+func (r *memoryTokenRepo) RevokeSession(ctx context.Context, userID string, sessionID string, reason string) error {
+	revoked := false
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.SessionID.String() == sessionID && token.IsValid() {
+			token.Revoke(reason)
+			revoked = true
+		}
+	}
+	if !revoked {
+		return errors.NewNotFoundError("Session not found", nil)
+	}
+	return nil
+}
+
+func (r *memoryTokenRepo) RevokeOtherSessions(ctx context.Context, userID string, keepSessionID string, reason string) error {
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.SessionID.String() != keepSessionID && !token.Revoked {
+			token.Revoke(reason)
+		}
+	}
+	return nil
+}
+
+func (r *memoryTokenRepo) RevokeFamily(ctx context.Context, familyID string, reason string) error {
+	revoked := false
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && !token.Revoked {
+			token.Revoke(reason)
+			revoked = true
+		}
+	}
+	if !revoked {
+		return errors.NewNotFoundError("Token family not found", nil)
+	}
+	return nil
+}
+
+// memoryUnitOfWork runs fn directly; a failing fn discards the repository changes it made
+type memoryUnitOfWork struct{ tokens *memoryTokenRepo }
+
+func (u *memoryUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	snapshot := make(map[string]*entities.RefreshToken, len(u.tokens.tokens))
+	for id, token := range u.tokens.tokens {
+		copied := *token
+		snapshot[id] = &copied
+	}
+	if err := fn(ctx); err != nil {
+		u.tokens.tokens = snapshot
+		return err
+	}
+	return nil
+}
+
+// memoryDeviceRepo stores devices by ID
+type memoryDeviceRepo struct {
+	repositories.DeviceRepository
+	devices map[string]*entities.Device
+}
+
+func (r *memoryDeviceRepo) GetByFingerprint(ctx context.Context, userID, fingerprint string) (*entities.Device, error) {
+	for _, device := range r.devices {
+		if device.UserID == userID && device.Fingerprint == fingerprint {
+			copied := *device
+			return &copied, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("Device not found", nil)
+}
+
+func (r *memoryDeviceRepo) List(ctx context.Context, userID string, offset, limit int) ([]*entities.Device, int64, error) {
+	var devices []*entities.Device
+	for _, device := range r.devices {
+		if userID == "" || device.UserID == userID {
+			copied := *device
+			devices = append(devices, &copied)
+		}
+	}
+	return devices, int64(len(devices)), nil
+}
+
+func (r *memoryDeviceRepo) GetByID(ctx context.Context, id string) (*entities.Device, error) {
+	device, ok := r.devices[id]
+	if !ok {
+		return nil, errors.NewNotFoundError("Device not found", nil)
+	}
+	copied := *device
+	return &copied, nil
+}
+
+func (r *memoryDeviceRepo) Save(ctx context.Context, device *entities.Device) error {
+	if stored, err := r.GetByFingerprint(ctx, device.UserID, device.Fingerprint); err == nil {
+		device.ID = stored.ID
+	}
+	copied := *device
+	r.devices[device.ID] = &copied
+	return nil
+}
+
+type staticUserRepo struct {
+	repositories.UserRepository
+	user *entities.User
+}
+
+func (r *staticUserRepo) GetByID(ctx context.Context, id string) (*entities.User, error) {
+	return r.user, nil
+}
+
+func (r *staticUserRepo) GetByIDForUpdate(ctx context.Context, id string) (*entities.User, error) {
+	return r.user, nil
+}
+
+func (r *staticUserRepo) GetByPhoneNumber(ctx context.Context, phoneNumber valueobjects.PhoneNumber) (*entities.User, error) {
+	return r.user, nil
+}
+
+type fakeJWTService struct{}
+
+func (fakeJWTService) GenerateToken(claims *services.JWTClaims) (string, error) {
+	return "access-" + claims.TokenID, nil
+}
+
+func (fakeJWTService) VerifyToken(token string, opts ...services.VerifyOption) (*services.JWTClaims, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (fakeJWTService) GenerateIDToken(claims *services.IDTokenClaims) (string, error) {
+	return "id-" + claims.Subject + "-" + claims.Audience[0], nil
+}
+
+// fakeHashService hashes by prefixing and hands out predictable random strings
+type fakeHashService struct {
+	services.HashService
+	n int
+}
+
+func (h *fakeHashService) HashRefreshToken(token string) (string, error) {
+	return "hash:" + token, nil
+}
+
+func (h *fakeHashService) HashPassword(password string) (string, error) {
+	return "hash:" + password, nil
+}
+
+func (h *fakeHashService) VerifyPassword(password, hash string) error {
+	if "hash:"+password != hash {
+		return fmt.Errorf("password mismatch")
+	}
+	return nil
+}
+
+func (h *fakeHashService) VerifyOTP(otp, hash string) error {
+	if "hash:"+otp != hash {
+		return fmt.Errorf("otp mismatch")
+	}
+	return nil
+}
+
+func (h *fakeHashService) GenerateRandomString(length int) (string, error) {
+	h.n++
+	return fmt.Sprintf("random-%d", h.n), nil
+}
+
+type recordingEventPublisher struct{ events []services.SecurityEvent }
+
+func (p *recordingEventPublisher) Publish(ctx context.Context, event services.SecurityEvent) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+type refreshFixture struct {
+	uc        *RefreshUseCase
+	tokens    *memoryTokenRepo
+	devices   *memoryDeviceRepo
+	denylist  *memoryDenylist
+	publisher *recordingEventPublisher
+	sessionID valueobjects.SessionID
+}
+
+// newRefreshFixture stores one login token "login" with refresh token value "first"
+func newRefreshFixture(t *testing.T, reuseGrace time.Duration) *refreshFixture {
+	t.Helper()
+
+	sessionID, err := valueobjects.NewSessionID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := &memoryTokenRepo{tokens: make(map[string]*entities.RefreshToken)}
+	login := entities.NewRefreshToken("user-1", sessionID, "hash:first", time.Hour)
+	login.ID = "login"
+	login.ClientID = "web"
+	login.StartFamily()
+	tokens.tokens[login.ID] = login
+
+	devices := &memoryDeviceRepo{devices: make(map[string]*entities.Device)}
+	denylist := &memoryDenylist{denied: map[string]bool{}}
+	events := &recordingEventPublisher{}
+	uc := NewRefreshUseCase(
+		&staticUserRepo{user: &entities.User{ID: "user-1", Scope: "user"}},
+		tokens, devices, &memoryUnitOfWork{tokens: tokens}, denylist, fakeJWTService{}, &fakeHashService{},
+		time.Minute, time.Hour,
+		TokenClaimsConfig{Clients: &staticClientRepo{clients: map[string]*entities.Client{"web": {ID: "web", Type: "web"}}}},
+		SessionPolicies{},
+		events, reuseGrace,
+	)
+
+	return &refreshFixture{uc: uc, tokens: tokens, devices: devices, denylist: denylist, publisher: events, sessionID: sessionID}
+}
+
+func (f *refreshFixture) refresh(refreshToken string) (*dto.RefreshTokenResponse, error) {
+	return f.uc.Execute(context.Background(), &dto.RefreshTokenRequest{
+		RefreshToken: refreshToken,
+		SessionID:    f.sessionID.String(),
+	})
+}
+
+func TestRefreshUseCaseKeepsTokenFamily(t *testing.T) {
+	f := newRefreshFixture(t, 0)
+
+	first, err := f.refresh("first")
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	second, err := f.refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("second refresh: %v", err)
+	}
+
+	var latest *entities.RefreshToken
+	for _, token := range f.tokens.tokens {
+		if token.TokenHash == "hash:"+second.RefreshToken {
+			latest = token
+		}
+	}
+	if latest == nil {
+		t.Fatal("rotated token was not stored")
+	}
+	if latest.FamilyID != "login" {
+		t.Errorf("family = %q, want the login token's ID", latest.FamilyID)
+	}
+	if parent := f.tokens.tokens[latest.ParentID]; parent == nil || parent.TokenHash != "hash:"+first.RefreshToken {
+		t.Errorf("parent = %q, want the token it was rotated from", latest.ParentID)
+	}
+}
+
+func TestRefreshUseCaseRevokesFamilyOnReuse(t *testing.T) {
+	f := newRefreshFixture(t, 10*time.Second)
+
+	rotated, err := f.refresh("first")
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// Move the rotation out of the grace window
+	revokedAt := time.Now().Add(-time.Minute)
+	f.tokens.tokens["login"].RevokedAt = &revokedAt
+
+	if _, err := f.refresh("first"); err == nil {
+		t.Fatal("reusing a rotated token succeeded")
+	}
+
+	for id, token := range f.tokens.tokens {
+		if !token.Revoked {
+			t.Errorf("token %s of the reused family is still valid", id)
+		}
+	}
+	if len(f.publisher.events) != 1 || f.publisher.events[0].Type != services.SecurityEventRefreshTokenReuse {
+		t.Fatalf("events = %+v, want one refresh token reuse event", f.publisher.events)
+	}
+	if f.publisher.events[0].UserID != "user-1" || f.publisher.events[0].Details["family_id"] != "login" {
+		t.Errorf("event = %+v, want user and family of the reused token", f.publisher.events[0])
+	}
+	if !sessionDenied(t, f.denylist, f.sessionID) {
+		t.Error("access tokens of the reused family are still accepted")
+	}
+
+	if _, err := f.refresh(rotated.RefreshToken); err == nil {
+		t.Error("the successor of a reused token still refreshes")
+	}
+
+	// The family is already revoked: presenting the token again raises no new event
+	if _, err := f.refresh("first"); err == nil {
+		t.Fatal("reusing a token of a revoked family succeeded")
+	}
+	if len(f.publisher.events) != 1 {
+		t.Errorf("events = %+v, want no event for a family revoked before", f.publisher.events)
+	}
+}
+
+func TestRefreshUseCaseToleratesRaceWithinGrace(t *testing.T) {
+	f := newRefreshFixture(t, 10*time.Second)
+
+	rotated, err := f.refresh("first")
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// A parallel request with the same token loses the race but is not treated as theft
+	if _, err := f.refresh("first"); err == nil {
+		t.Fatal("reusing a rotated token succeeded")
+	}
+	if len(f.publisher.events) != 0 {
+		t.Errorf("events = %+v, want none within the grace window", f.publisher.events)
+	}
+
+	if _, err := f.refresh(rotated.RefreshToken); err != nil {
+		t.Errorf("the winner's token no longer refreshes: %v", err)
+	}
+}
+
+func TestRefreshUseCaseRollsBackFailedRotation(t *testing.T) {
+	f := newRefreshFixture(t, 0)
+	f.tokens.failCreate = true
+
+	if _, err := f.refresh("first"); err == nil {
+		t.Fatal("refresh succeeded without storing the new token")
+	}
+	if len(f.tokens.tokens) != 1 || f.tokens.tokens["login"].Revoked {
+		t.Errorf("tokens = %+v, want the original token untouched", f.tokens.tokens)
+	}
+}
+
+func TestRefreshUseCaseIgnoresLoggedOutToken(t *testing.T) {
+	f := newRefreshFixture(t, 0)
+	f.tokens.tokens["login"].Revoke(entities.RevokeReasonLogout)
+
+	if _, err := f.refresh("first"); err == nil {
+		t.Fatal("a logged out token refreshed")
+	}
+	if len(f.publisher.events) != 0 {
+		t.Errorf("events = %+v, want none for a token that was never rotated", f.publisher.events)
+	}
+}
+
+func TestRefreshUseCaseEnforcesSessionPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   SessionPolicy
+		started  time.Duration // login, before now
+		lastUsed time.Duration // creation of the presented token, before now
+		wantErr  bool
+	}{
+		{"within limits", SessionPolicy{AbsoluteLifetime: 24 * time.Hour, IdleTimeout: time.Hour}, 2 * time.Hour, 30 * time.Minute, false},
+		{"past absolute lifetime", SessionPolicy{AbsoluteLifetime: 24 * time.Hour}, 25 * time.Hour, time.Minute, true},
+		{"idle too long", SessionPolicy{IdleTimeout: time.Hour}, 2 * time.Hour, 90 * time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRefreshFixture(t, 0)
+			f.uc.sessions = SessionPolicies{ByType: map[string]SessionPolicy{"web": tt.policy}}
+
+			login := f.tokens.tokens["login"]
+			login.SessionStartedAt = time.Now().Add(-tt.started)
+			login.CreatedAt = time.Now().Add(-tt.lastUsed)
+
+			_, err := f.refresh("first")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("refresh error = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRefreshUseCaseCapsExpiryAtSessionEnd(t *testing.T) {
+	f := newRefreshFixture(t, 0)
+	f.uc.sessions = SessionPolicies{Default: SessionPolicy{AbsoluteLifetime: 24 * time.Hour}}
+
+	sessionEnd := time.Now().Add(20 * time.Minute)
+	f.tokens.tokens["login"].SessionStartedAt = sessionEnd.Add(-24 * time.Hour)
+
+	response, err := f.refresh("first")
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if !response.RefreshExpiresAt.Equal(sessionEnd) {
+		t.Errorf("refresh token expires at %v, want the session end %v", response.RefreshExpiresAt, sessionEnd)
+	}
+	for _, token := range f.tokens.tokens {
+		if token.ParentID == "login" && !token.ExpiresAt.Equal(sessionEnd) {
+			t.Errorf("stored token expires at %v, want %v", token.ExpiresAt, sessionEnd)
+		}
+	}
+}
+
+func TestRefreshUseCaseRecordsDeviceUse(t *testing.T) {
+	f := newRefreshFixture(t, 0)
+
+	device := entities.NewDevice("user-1", "", "Mozilla/5.0 (Windows NT 10.0) Chrome/120.0")
+	device.ID = "device-1"
+	device.Seen("", "Mozilla/5.0 (Windows NT 10.0) Chrome/120.0", "203.0.113.7", "")
+	f.devices.devices[device.ID] = device
+	f.tokens.tokens["login"].DeviceID = device.ID
+
+	_, err := f.uc.Execute(context.Background(), &dto.RefreshTokenRequest{
+		RefreshToken: "first",
+		SessionID:    f.sessionID.String(),
+		IPAddress:    "198.51.100.23",
+		AppVersion:   "2.4.1",
+	})
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	got := f.devices.devices["device-1"]
+	if got.FirstIP != "203.0.113.7" || got.LastIP != "198.51.100.23" || got.AppVersion != "2.4.1" {
+		t.Errorf("first IP, last IP, app version = %q, %q, %q, want the login IP, the refresh IP and 2.4.1",
+			got.FirstIP, got.LastIP, got.AppVersion)
+	}
+	if got.Browser != "Chrome" {
+		t.Errorf("browser = %q, want the one seen at login", got.Browser)
+	}
+	for _, token := range f.tokens.tokens {
+		if token.ParentID == "login" && token.DeviceID != "device-1" {
+			t.Errorf("rotated token device = %q, want device-1", token.DeviceID)
+		}
+	}
+}
+
+// idTokenRecorder keeps the claims of the last ID token it signed
+type idTokenRecorder struct {
+	fakeJWTService
+	claims *services.IDTokenClaims
+}
+
+func (r *idTokenRecorder) GenerateIDToken(claims *services.IDTokenClaims) (string, error) {
+	r.claims = claims
+	return r.fakeJWTService.GenerateIDToken(claims)
+}
+
+func TestRefreshUseCaseIssuesIDTokenForOIDC(t *testing.T) {
+	f := newRefreshFixture(t, 0)
+
+	response, err := f.refresh("first")
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if response.IDToken != "" {
+		t.Errorf("IDToken = %q without an OIDC issuer", response.IDToken)
+	}
+
+	recorder := &idTokenRecorder{}
+	f.uc.jwtService = recorder
+	f.uc.claims.IDTokenIssuer = "https://auth.example.com"
+	f.uc.claims.IDTokenTTL = time.Hour
+
+	response, err = f.refresh(response.RefreshToken)
+	if err != nil {
+		t.Fatalf("second refresh: %v", err)
+	}
+	if response.IDToken != "id-user-1-web" {
+		t.Errorf("IDToken = %q, want one for user-1 and the web client", response.IDToken)
+	}
+	claims := recorder.claims
+	if claims.Issuer != "https://auth.example.com" || !claims.PhoneNumberVerified {
+		t.Errorf("claims = %+v, want the OIDC issuer and a verified phone number", claims)
+	}
+	if want := f.tokens.tokens["login"].SessionStartedAt.Unix(); claims.AuthTime != want {
+		t.Errorf("auth_time = %d, want the session start %d", claims.AuthTime, want)
+	}
+}
+
+func TestRefreshUseCaseAppliesClientTokenTTLs(t *testing.T) {
+	f := newRefreshFixture(t, 0)
+	client := f.uc.claims.Clients.(*staticClientRepo).clients["web"]
+	client.AccessTokenTTL = 5 * time.Minute
+	client.RefreshTokenTTL = 10 * time.Minute
+
+	before := time.Now()
+	response, err := f.refresh("first")
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if got := response.ExpiresAt.Sub(before); got < 5*time.Minute || got > 6*time.Minute {
+		t.Errorf("access token lives %v, want the client's 5m", got)
+	}
+	if got := response.RefreshExpiresAt.Sub(before); got < 10*time.Minute || got > 11*time.Minute {
+		t.Errorf("refresh token lives %v, want the client's 10m", got)
+	}
+}
@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// RemoveIPBanUseCase handles lifting a ban on an IP or CIDR block
+type RemoveIPBanUseCase struct {
+	banRepo repositories.IPBanRepository
+}
+
+// NewRemoveIPBanUseCase creates a new RemoveIPBanUseCase
+func NewRemoveIPBanUseCase(banRepo repositories.IPBanRepository) *RemoveIPBanUseCase {
+	return &RemoveIPBanUseCase{
+		banRepo: banRepo,
+	}
+}
+
+// Execute removes the ban for the given range
+func (uc *RemoveIPBanUseCase) Execute(ctx context.Context, cidr string) (*dto.SuccessResponse, error) {
+	ipRange, err := valueobjects.NewIPRange(cidr)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid IP address or CIDR block", err)
+	}
+
+	if err := uc.banRepo.Remove(ctx, ipRange); err != nil {
+		return nil, err
+	}
+
+	return &dto.SuccessResponse{
+		Message: "IP ban removed",
+	}, nil
+}
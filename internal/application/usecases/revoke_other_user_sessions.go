@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// RevokeOtherUserSessionsUseCase logs a user out everywhere except the current session
+type RevokeOtherUserSessionsUseCase struct {
+	tokenRepo repositories.TokenRepository
+	denylist  repositories.AccessTokenDenylist
+	accessTTL time.Duration
+}
+
+// NewRevokeOtherUserSessionsUseCase creates a new RevokeOtherUserSessionsUseCase
+func NewRevokeOtherUserSessionsUseCase(
+	tokenRepo repositories.TokenRepository,
+	denylist repositories.AccessTokenDenylist,
+	accessTTL time.Duration,
+) *RevokeOtherUserSessionsUseCase {
+	return &RevokeOtherUserSessionsUseCase{
+		tokenRepo: tokenRepo,
+		denylist:  denylist,
+		accessTTL: accessTTL,
+	}
+}
+
+// Execute revokes the refresh tokens of every session of the user but
+// currentSessionID and rejects the access tokens issued for them so far
+func (uc *RevokeOtherUserSessionsUseCase) Execute(ctx context.Context, userID string, currentSessionID string) (*dto.SuccessResponse, error) {
+	sessionIDObj, err := valueobjects.NewSessionIDFromString(currentSessionID)
+	if err != nil {
+		return nil, errors.NewValidationError("Current session is required to keep it signed in", err)
+	}
+
+	// Listed before revoking, since revoked sessions are no longer active
+	tokens, err := uc.tokenRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{sessionIDObj.String(): true}
+	var others []string
+	for _, token := range tokens {
+		if id := token.SessionID.String(); !seen[id] {
+			seen[id] = true
+			others = append(others, id)
+		}
+	}
+
+	if err := uc.tokenRepo.RevokeOtherSessions(ctx, userID, sessionIDObj.String(), entities.RevokeReasonLogout); err != nil {
+		return nil, err
+	}
+	if err := denySessionTokens(ctx, uc.denylist, uc.accessTTL, others...); err != nil {
+		return nil, err
+	}
+
+	return &dto.SuccessResponse{
+		Message: "Other sessions revoked",
+	}, nil
+}
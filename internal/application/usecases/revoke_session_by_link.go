@@ -0,0 +1,92 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// RevokeSessionByLinkUseCase ends a session through the one-time link of a
+// new-login notification, without the user having to log in
+type RevokeSessionByLinkUseCase struct {
+	linkRepo    repositories.RevokeLinkRepository
+	tokenRepo   repositories.TokenRepository
+	denylist    repositories.AccessTokenDenylist
+	hashService services.HashService
+	events      services.SecurityEventPublisher
+	accessTTL   time.Duration
+}
+
+// NewRevokeSessionByLinkUseCase creates a new RevokeSessionByLinkUseCase
+func NewRevokeSessionByLinkUseCase(
+	linkRepo repositories.RevokeLinkRepository,
+	tokenRepo repositories.TokenRepository,
+	denylist repositories.AccessTokenDenylist,
+	hashService services.HashService,
+	events services.SecurityEventPublisher,
+	accessTTL time.Duration,
+) *RevokeSessionByLinkUseCase {
+	return &RevokeSessionByLinkUseCase{
+		linkRepo:    linkRepo,
+		tokenRepo:   tokenRepo,
+		denylist:    denylist,
+		hashService: hashService,
+		events:      events,
+		accessTTL:   accessTTL,
+	}
+}
+
+// Execute redeems the link token, revokes the session it was issued for and
+// rejects the session's access tokens
+func (uc *RevokeSessionByLinkUseCase) Execute(ctx context.Context, token string) (*dto.SuccessResponse, error) {
+	if token == "" {
+		return nil, errors.NewValidationError("Revoke link token is required", nil)
+	}
+
+	tokenHash, err := uc.hashService.HashRefreshToken(token)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to hash revoke link token", err)
+	}
+
+	userID, sessionID, err := uc.linkRepo.Consume(ctx, tokenHash)
+	if err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.NotFoundError {
+			return nil, errors.NewGoneError("Revoke link is invalid, expired or already used", nil)
+		}
+		return nil, err
+	}
+
+	message := "Session revoked"
+	if err := uc.tokenRepo.RevokeSession(ctx, userID, sessionID, entities.RevokeReasonNotMe); err != nil {
+		customErr := errors.GetCustomError(err)
+		if customErr == nil || customErr.Type != errors.NotFoundError {
+			return nil, err
+		}
+		// Logged out or expired in the meantime; still worth reporting
+		message = "Session had already ended"
+	}
+	// Its access tokens may outlive the refresh tokens either way
+	if err := denySessionTokens(ctx, uc.denylist, uc.accessTTL, sessionID); err != nil {
+		return nil, err
+	}
+
+	event := services.SecurityEvent{
+		Type:       services.SecurityEventLoginDisowned,
+		UserID:     userID,
+		SessionID:  sessionID,
+		OccurredAt: time.Now(),
+	}
+	if err := uc.events.Publish(ctx, event); err != nil {
+		log.Printf("[WARN] failed to publish disowned login event: %v", err)
+	}
+
+	return &dto.SuccessResponse{
+		Message: message,
+	}, nil
+}
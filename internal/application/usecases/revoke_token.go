@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// RevokeTokenUseCase lets clients revoke access and refresh tokens issued to
+// them (RFC 7009)
+type RevokeTokenUseCase struct {
+	clients       clientAuthenticator
+	tokenRepo     repositories.TokenRepository
+	denylist      repositories.AccessTokenDenylist
+	jwtService    services.JWTService
+	hashService   services.HashService
+	verifyOptions []services.VerifyOption
+}
+
+// NewRevokeTokenUseCase creates a new RevokeTokenUseCase; verifyOptions
+// typically pin the issuer
+func NewRevokeTokenUseCase(
+	clientRepo repositories.ClientReader,
+	tokenRepo repositories.TokenRepository,
+	denylist repositories.AccessTokenDenylist,
+	jwtService services.JWTService,
+	hashService services.HashService,
+	verifyOptions ...services.VerifyOption,
+) *RevokeTokenUseCase {
+	return &RevokeTokenUseCase{
+		clients:       clientAuthenticator{clients: clientRepo, hashService: hashService},
+		tokenRepo:     tokenRepo,
+		denylist:      denylist,
+		jwtService:    jwtService,
+		hashService:   hashService,
+		verifyOptions: verifyOptions,
+	}
+}
+
+// Execute authenticates the client and revokes the token. Tokens that are
+// invalid, already revoked, unknown or issued to another client are ignored,
+// so the only errors are client authentication and storage failures.
+func (uc *RevokeTokenUseCase) Execute(ctx context.Context, req *dto.RevokeTokenRequest) error {
+	client, err := uc.clients.authenticate(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return err
+	}
+
+	if req.Token == "" {
+		return errors.NewValidationError("Token is required", nil)
+	}
+
+	// The hint only decides which kind of token is tried first
+	revocations := []func(context.Context, string, string) (bool, error){uc.revokeAccessToken, uc.revokeRefreshToken}
+	if req.TokenTypeHint == TokenTypeHintRefreshToken {
+		revocations[0], revocations[1] = revocations[1], revocations[0]
+	}
+
+	for _, revoke := range revocations {
+		found, err := revoke(ctx, client.ID, req.Token)
+		if err != nil || found {
+			return err
+		}
+	}
+	return nil
+}
+
+// revokeAccessToken denylists token if it's a live access token of the client;
+// it reports whether token was an access token at all
+func (uc *RevokeTokenUseCase) revokeAccessToken(ctx context.Context, clientID, token string) (bool, error) {
+	claims, err := uc.jwtService.VerifyToken(token, uc.verifyOptions...)
+	if err != nil {
+		return false, nil
+	}
+	if claims.ClientID != clientID {
+		log.Printf("[WARN] Client %s tried to revoke an access token of client %s", clientID, claims.ClientID)
+		return true, nil
+	}
+	if claims.IsExpired() || claims.TokenID == "" {
+		return true, nil
+	}
+
+	if err := uc.denylist.Deny(ctx, claims.TokenID, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		return true, errors.NewInternalError("Failed to revoke access token", err)
+	}
+	return true, nil
+}
+
+// revokeRefreshToken revokes token if it's a refresh token of the client; it
+// reports whether token was a known refresh token
+func (uc *RevokeTokenUseCase) revokeRefreshToken(ctx context.Context, clientID, token string) (bool, error) {
+	tokenHash, err := uc.hashService.HashRefreshToken(token)
+	if err != nil {
+		return false, nil
+	}
+
+	refreshToken, err := uc.tokenRepo.GetByTokenHash(ctx, tokenHash)
+	if err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.NotFoundError {
+			return false, nil
+		}
+		return false, err
+	}
+	if refreshToken.ClientID != clientID {
+		log.Printf("[WARN] Client %s tried to revoke a refresh token of client %s", clientID, refreshToken.ClientID)
+		return true, nil
+	}
+	if refreshToken.Revoked {
+		return true, nil
+	}
+
+	if err := uc.tokenRepo.RevokeByTokenHash(ctx, tokenHash, entities.RevokeReasonClient); err != nil {
+		if customErr := errors.GetCustomError(err); customErr != nil && customErr.Type == errors.NotFoundError {
+			return true, nil
+		}
+		return true, errors.NewInternalError("Failed to revoke refresh token", err)
+	}
+	return true, nil
+}
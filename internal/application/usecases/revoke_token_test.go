@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// newRevokeFixture is newOAuthFixture with the tokens' client made confidential
+func newRevokeFixture() (*oauthFixture, *RevokeTokenUseCase) {
+	f := newOAuthFixture()
+	f.clients.clients["mobile-app"].SecretHash = "hash:m0bile"
+	return f, NewRevokeTokenUseCase(f.clients, f.tokens, f.denylist, f.jwt, f.hash)
+}
+
+func TestRevokeTokenUseCaseRevokesRefreshToken(t *testing.T) {
+	f, uc := newRevokeFixture()
+
+	err := uc.Execute(context.Background(), &dto.RevokeTokenRequest{Token: "refresh-1", TokenTypeHint: TokenTypeHintRefreshToken, ClientID: "mobile-app", ClientSecret: "m0bile"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if token := f.tokens.tokens["rt-1"]; !token.Revoked || token.RevokeReason != entities.RevokeReasonClient {
+		t.Errorf("refresh token revoked = %v (%s), want revoked by the client", token.Revoked, token.RevokeReason)
+	}
+
+	// Revoking again is not an error
+	if err := uc.Execute(context.Background(), &dto.RevokeTokenRequest{Token: "refresh-1", ClientID: "mobile-app", ClientSecret: "m0bile"}); err != nil {
+		t.Errorf("Execute() on a revoked token error = %v", err)
+	}
+}
+
+func TestRevokeTokenUseCaseDeniesAccessToken(t *testing.T) {
+	f, uc := newRevokeFixture()
+
+	err := uc.Execute(context.Background(), &dto.RevokeTokenRequest{Token: "access-1", ClientID: "mobile-app", ClientSecret: "m0bile"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !f.denylist.denied["jti-1"] {
+		t.Errorf("access token was not denylisted")
+	}
+	if f.tokens.tokens["rt-1"].Revoked {
+		t.Errorf("refresh token was revoked along with the access token")
+	}
+}
+
+func TestRevokeTokenUseCaseIgnoresOtherClientsTokens(t *testing.T) {
+	f, uc := newRevokeFixture()
+
+	for _, token := range []string{"access-1", "refresh-1", "unknown"} {
+		if err := uc.Execute(context.Background(), &dto.RevokeTokenRequest{Token: token, ClientID: "gateway", ClientSecret: "s3cret"}); err != nil {
+			t.Errorf("Execute(%s) error = %v, want success", token, err)
+		}
+	}
+	if f.denylist.denied["jti-1"] || f.tokens.tokens["rt-1"].Revoked {
+		t.Errorf("another client's tokens were revoked")
+	}
+}
+
+func TestRevokeTokenUseCaseAuthenticatesClient(t *testing.T) {
+	f, uc := newRevokeFixture()
+
+	err := uc.Execute(context.Background(), &dto.RevokeTokenRequest{Token: "refresh-1", ClientID: "mobile-app", ClientSecret: "guess"})
+	if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.Unauthorized {
+		t.Errorf("Execute() error = %v, want unauthorized", err)
+	}
+	if f.tokens.tokens["rt-1"].Revoked {
+		t.Errorf("token was revoked without client authentication")
+	}
+}
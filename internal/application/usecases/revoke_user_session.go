@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// RevokeUserSessionUseCase lets a user log out one of their sessions
+type RevokeUserSessionUseCase struct {
+	tokenRepo repositories.TokenRepository
+	denylist  repositories.AccessTokenDenylist
+	accessTTL time.Duration
+}
+
+// NewRevokeUserSessionUseCase creates a new RevokeUserSessionUseCase
+func NewRevokeUserSessionUseCase(
+	tokenRepo repositories.TokenRepository,
+	denylist repositories.AccessTokenDenylist,
+	accessTTL time.Duration,
+) *RevokeUserSessionUseCase {
+	return &RevokeUserSessionUseCase{
+		tokenRepo: tokenRepo,
+		denylist:  denylist,
+		accessTTL: accessTTL,
+	}
+}
+
+// Execute revokes the refresh tokens of the given session of the user and
+// rejects the access tokens issued for it so far
+func (uc *RevokeUserSessionUseCase) Execute(ctx context.Context, userID string, sessionID string) (*dto.SuccessResponse, error) {
+	sessionIDObj, err := valueobjects.NewSessionIDFromString(sessionID)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid session ID format", err)
+	}
+
+	// Revoked first, which fails for sessions of other users, so nobody can
+	// deny the access tokens of a session that isn't theirs
+	if err := uc.tokenRepo.RevokeSession(ctx, userID, sessionIDObj.String(), entities.RevokeReasonLogout); err != nil {
+		return nil, err
+	}
+	if err := denySessionTokens(ctx, uc.denylist, uc.accessTTL, sessionIDObj.String()); err != nil {
+		return nil, err
+	}
+
+	return &dto.SuccessResponse{
+		Message: "Session revoked",
+	}, nil
+}
+
+// denySessionTokens rejects the access tokens issued so far for each session
+// until they would have expired; accessTTL must cover the longest access token
+// lifetime
+func denySessionTokens(ctx context.Context, denylist repositories.AccessTokenDenylist, accessTTL time.Duration, sessionIDs ...string) error {
+	now := time.Now()
+	for _, sessionID := range sessionIDs {
+		if err := denylist.DenySessionTokensBefore(ctx, sessionID, now, accessTTL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+)
+
+// newSessionsFixture gives user-1 the sessions phone and laptop and user-2 the session tablet
+func newSessionsFixture(t *testing.T) (*memoryTokenRepo, map[string]valueobjects.SessionID) {
+	t.Helper()
+	tokens := &memoryTokenRepo{tokens: make(map[string]*entities.RefreshToken)}
+	sessions := make(map[string]valueobjects.SessionID)
+	for name, userID := range map[string]string{"phone": "user-1", "laptop": "user-1", "tablet": "user-2"} {
+		sessionID, err := valueobjects.NewSessionID()
+		if err != nil {
+			t.Fatalf("session ID: %v", err)
+		}
+		sessions[name] = sessionID
+		token := entities.NewRefreshToken(userID, sessionID, "hash:"+name, time.Hour)
+		token.ID = name
+		tokens.tokens[name] = token
+	}
+	return tokens, sessions
+}
+
+// sessionDenied reports whether an access token of the session issued now is rejected
+func sessionDenied(t *testing.T, denylist *memoryDenylist, sessionID valueobjects.SessionID) bool {
+	t.Helper()
+	denied, err := denylist.IsDenied(context.Background(), "jti", "", sessionID.String(), time.Now())
+	if err != nil {
+		t.Fatalf("denylist: %v", err)
+	}
+	return denied
+}
+
+func TestRevokeUserSessionUseCaseDeniesAccessTokens(t *testing.T) {
+	tokens, sessions := newSessionsFixture(t)
+	denylist := &memoryDenylist{denied: map[string]bool{}}
+	uc := NewRevokeUserSessionUseCase(tokens, denylist, 15*time.Minute)
+
+	if _, err := uc.Execute(context.Background(), "user-1", sessions["phone"].String()); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if !tokens.tokens["phone"].Revoked || !sessionDenied(t, denylist, sessions["phone"]) {
+		t.Error("revoked session's tokens still valid")
+	}
+	if tokens.tokens["laptop"].Revoked || sessionDenied(t, denylist, sessions["laptop"]) {
+		t.Error("other session revoked")
+	}
+
+	// Another user's session is neither revoked nor denied
+	if _, err := uc.Execute(context.Background(), "user-1", sessions["tablet"].String()); err == nil {
+		t.Error("revoked another user's session")
+	}
+	if sessionDenied(t, denylist, sessions["tablet"]) {
+		t.Error("denied another user's session")
+	}
+}
+
+func TestRevokeOtherUserSessionsUseCaseDeniesAccessTokens(t *testing.T) {
+	tokens, sessions := newSessionsFixture(t)
+	denylist := &memoryDenylist{denied: map[string]bool{}}
+	uc := NewRevokeOtherUserSessionsUseCase(tokens, denylist, 15*time.Minute)
+
+	if _, err := uc.Execute(context.Background(), "user-1", sessions["laptop"].String()); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if !tokens.tokens["phone"].Revoked || !sessionDenied(t, denylist, sessions["phone"]) {
+		t.Error("other session's tokens still valid")
+	}
+	if tokens.tokens["laptop"].Revoked || sessionDenied(t, denylist, sessions["laptop"]) {
+		t.Error("current session revoked")
+	}
+	if tokens.tokens["tablet"].Revoked || sessionDenied(t, denylist, sessions["tablet"]) {
+		t.Error("another user's session revoked")
+	}
+}
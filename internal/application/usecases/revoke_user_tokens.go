@@ -0,0 +1,54 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+// RevokeUserTokensUseCase signs a user out everywhere: refresh tokens are revoked
+// and every access token issued so far is rejected until it would have expired
+type RevokeUserTokensUseCase struct {
+	userRepo  repositories.UserRepository
+	tokenRepo repositories.TokenRepository
+	denylist  repositories.AccessTokenDenylist
+	accessTTL time.Duration
+}
+
+// NewRevokeUserTokensUseCase creates a new RevokeUserTokensUseCase
+func NewRevokeUserTokensUseCase(
+	userRepo repositories.UserRepository,
+	tokenRepo repositories.TokenRepository,
+	denylist repositories.AccessTokenDenylist,
+	accessTTL time.Duration,
+) *RevokeUserTokensUseCase {
+	return &RevokeUserTokensUseCase{
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
+		denylist:  denylist,
+		accessTTL: accessTTL,
+	}
+}
+
+// Execute revokes all tokens of the given user
+func (uc *RevokeUserTokensUseCase) Execute(ctx context.Context, userID string) (*dto.SuccessResponse, error) {
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// Watermark first: even if revoking refresh tokens fails, nothing issued so far is accepted
+	if err := uc.denylist.DenyUserTokensBefore(ctx, userID, time.Now(), uc.accessTTL); err != nil {
+		return nil, err
+	}
+
+	if err := uc.tokenRepo.RevokeAllByUserID(ctx, userID, entities.RevokeReasonAdmin); err != nil {
+		return nil, err
+	}
+
+	return &dto.SuccessResponse{
+		Message: "User tokens revoked",
+	}, nil
+}
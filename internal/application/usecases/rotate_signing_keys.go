@@ -0,0 +1,170 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+// SigningKeyRotationPolicy controls when signing keys move through the ring
+type SigningKeyRotationPolicy struct {
+	RotationInterval time.Duration // How long a key stays active
+	PublishAhead     time.Duration // Minimum time the next key is in the JWKS before it signs anything
+	MaxTokenTTL      time.Duration // Longest lifetime of a token a key may have signed
+}
+
+// RotateSigningKeysUseCase keeps the signing key ring in shape and loads it into the JWT service.
+// It is safe to run on every replica: the ring is only modified under the repository lock,
+// and replicas that lose the race simply pick up the new ring.
+type RotateSigningKeysUseCase struct {
+	keyRepo   repositories.SigningKeyRepository
+	generator services.SigningKeyGenerator
+	loader    services.SigningKeyLoader
+	policy    SigningKeyRotationPolicy
+	now       func() time.Time
+}
+
+// NewRotateSigningKeysUseCase creates a new RotateSigningKeysUseCase
+func NewRotateSigningKeysUseCase(
+	keyRepo repositories.SigningKeyRepository,
+	generator services.SigningKeyGenerator,
+	loader services.SigningKeyLoader,
+	policy SigningKeyRotationPolicy,
+) *RotateSigningKeysUseCase {
+	return &RotateSigningKeysUseCase{
+		keyRepo:   keyRepo,
+		generator: generator,
+		loader:    loader,
+		policy:    policy,
+		now:       time.Now,
+	}
+}
+
+// Execute rotates the ring if the active key is due and reloads the JWT service keys
+func (uc *RotateSigningKeysUseCase) Execute(ctx context.Context) error {
+	var keys []*entities.SigningKey
+	err := uc.keyRepo.WithLock(ctx, func(ctx context.Context) error {
+		stored, err := uc.keyRepo.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		keys, err = uc.rotate(ctx, stored)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return uc.loader.LoadSigningKeys(keys)
+}
+
+// rotate applies the rotation policy and returns the keys that can still verify tokens
+func (uc *RotateSigningKeysUseCase) rotate(ctx context.Context, stored []*entities.SigningKey) ([]*entities.SigningKey, error) {
+	now := uc.now()
+
+	var active, next *entities.SigningKey
+	keys := make([]*entities.SigningKey, 0, len(stored)+1)
+	for _, key := range stored {
+		switch key.Status {
+		case entities.SigningKeyStatusActive:
+			if active == nil {
+				active = key
+				break
+			}
+			// Only one key may sign; keep the most recently activated one
+			older := key
+			if activatedAfter(key, active) {
+				older, active = active, key
+			}
+			older.Retire(now)
+			if err := uc.keyRepo.Save(ctx, older); err != nil {
+				return nil, err
+			}
+		case entities.SigningKeyStatusNext:
+			if next == nil || key.CreatedAt.Before(next.CreatedAt) {
+				next = key
+			}
+		case entities.SigningKeyStatusRetired:
+			if !key.CanVerify(now, uc.policy.MaxTokenTTL) {
+				if err := uc.keyRepo.Delete(ctx, key.ID); err != nil {
+					return nil, err
+				}
+				log.Printf("Removed expired signing key %s", key.ID)
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+
+	// Empty ring (first start): nothing has been signed yet, so the
+	// first key can be activated without being published ahead
+	if active == nil {
+		if next != nil {
+			active, next = next, nil
+		} else {
+			generated, err := uc.generate(ctx, now)
+			if err != nil {
+				return nil, err
+			}
+			active = generated
+			keys = append(keys, active)
+		}
+		active.Activate(now)
+		if err := uc.keyRepo.Save(ctx, active); err != nil {
+			return nil, err
+		}
+		log.Printf("Activated signing key %s", active.ID)
+	}
+
+	if next != nil && active.IsDue(now, uc.policy.RotationInterval) && next.IsPublishedFor(now, uc.policy.PublishAhead) {
+		active.Retire(now)
+		if err := uc.keyRepo.Save(ctx, active); err != nil {
+			return nil, err
+		}
+		next.Activate(now)
+		if err := uc.keyRepo.Save(ctx, next); err != nil {
+			return nil, err
+		}
+		log.Printf("Rotated signing key %s -> %s", active.ID, next.ID)
+		next = nil
+	}
+
+	// Always have a successor published so verifiers learn it before it signs
+	if next == nil {
+		generated, err := uc.generate(ctx, now)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, generated)
+	}
+
+	return keys, nil
+}
+
+// generate creates and stores a new next key
+func (uc *RotateSigningKeysUseCase) generate(ctx context.Context, now time.Time) (*entities.SigningKey, error) {
+	key, err := uc.generator.GenerateSigningKey()
+	if err != nil {
+		return nil, err
+	}
+	key.Status = entities.SigningKeyStatusNext
+	key.CreatedAt = now
+
+	if err := uc.keyRepo.Save(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// activatedAfter reports whether a was activated after b
+func activatedAfter(a, b *entities.SigningKey) bool {
+	if a.ActivatedAt == nil {
+		return false
+	}
+	return b.ActivatedAt == nil || a.ActivatedAt.After(*b.ActivatedAt)
+}
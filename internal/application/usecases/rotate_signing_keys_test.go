@@ -0,0 +1,135 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+type memorySigningKeyRepo struct {
+	keys map[string]*entities.SigningKey
+}
+
+func (r *memorySigningKeyRepo) List(ctx context.Context) ([]*entities.SigningKey, error) {
+	keys := make([]*entities.SigningKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		copied := *key
+		keys = append(keys, &copied)
+	}
+	return keys, nil
+}
+
+func (r *memorySigningKeyRepo) Save(ctx context.Context, key *entities.SigningKey) error {
+	copied := *key
+	r.keys[key.ID] = &copied
+	return nil
+}
+
+func (r *memorySigningKeyRepo) Delete(ctx context.Context, id string) error {
+	delete(r.keys, id)
+	return nil
+}
+
+func (r *memorySigningKeyRepo) WithLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type sequentialKeyGenerator struct{ n int }
+
+func (g *sequentialKeyGenerator) GenerateSigningKey() (*entities.SigningKey, error) {
+	g.n++
+	return &entities.SigningKey{ID: fmt.Sprintf("k%d", g.n), Algorithm: "ES256"}, nil
+}
+
+type recordingKeyLoader struct{ keys []*entities.SigningKey }
+
+func (l *recordingKeyLoader) LoadSigningKeys(keys []*entities.SigningKey) error {
+	l.keys = keys
+	return nil
+}
+
+func (l *recordingKeyLoader) status() map[string]entities.SigningKeyStatus {
+	status := make(map[string]entities.SigningKeyStatus, len(l.keys))
+	for _, key := range l.keys {
+		status[key.ID] = key.Status
+	}
+	return status
+}
+
+func TestRotateSigningKeysUseCase(t *testing.T) {
+	repo := &memorySigningKeyRepo{keys: make(map[string]*entities.SigningKey)}
+	loader := &recordingKeyLoader{}
+	uc := NewRotateSigningKeysUseCase(repo, &sequentialKeyGenerator{}, loader, SigningKeyRotationPolicy{
+		RotationInterval: 24 * time.Hour,
+		PublishAhead:     time.Hour,
+		MaxTokenTTL:      15 * time.Minute,
+	})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		want    map[string]entities.SigningKeyStatus
+	}{
+		{"first start activates a key and publishes its successor", 0,
+			map[string]entities.SigningKeyStatus{"k1": "active", "k2": "next"}},
+		{"not due yet", 23 * time.Hour,
+			map[string]entities.SigningKeyStatus{"k1": "active", "k2": "next"}},
+		{"due: next is promoted and a new next is published", time.Hour,
+			map[string]entities.SigningKeyStatus{"k1": "retired", "k2": "active", "k3": "next"}},
+		{"retired key still verifies within the token lifetime", 10 * time.Minute,
+			map[string]entities.SigningKeyStatus{"k1": "retired", "k2": "active", "k3": "next"}},
+		{"retired key is dropped once its tokens expired", 5 * time.Minute,
+			map[string]entities.SigningKeyStatus{"k2": "active", "k3": "next"}},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		if err := uc.Execute(ctx); err != nil {
+			t.Fatalf("%s: Execute: %v", step.name, err)
+		}
+
+		got := loader.status()
+		if len(got) != len(step.want) {
+			t.Fatalf("%s: loaded %v, want %v", step.name, got, step.want)
+		}
+		for kid, status := range step.want {
+			if got[kid] != status {
+				t.Errorf("%s: %s is %q, want %q", step.name, kid, got[kid], status)
+			}
+		}
+		if len(repo.keys) != len(step.want) {
+			t.Errorf("%s: stored %d keys, want %d", step.name, len(repo.keys), len(step.want))
+		}
+	}
+}
+
+func TestRotateSigningKeysUseCaseWaitsForPublication(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	activatedAt := now.Add(-48 * time.Hour)
+	repo := &memorySigningKeyRepo{keys: map[string]*entities.SigningKey{
+		"old":   {ID: "old", Status: entities.SigningKeyStatusActive, CreatedAt: activatedAt, ActivatedAt: &activatedAt},
+		"fresh": {ID: "fresh", Status: entities.SigningKeyStatusNext, CreatedAt: now.Add(-time.Minute)},
+	}}
+	loader := &recordingKeyLoader{}
+	uc := NewRotateSigningKeysUseCase(repo, &sequentialKeyGenerator{}, loader, SigningKeyRotationPolicy{
+		RotationInterval: 24 * time.Hour,
+		PublishAhead:     time.Hour,
+		MaxTokenTTL:      15 * time.Minute,
+	})
+	uc.now = func() time.Time { return now }
+
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if got := loader.status(); got["old"] != "active" || got["fresh"] != "next" {
+		t.Errorf("next key activated before it was published long enough: %v", got)
+	}
+}
@@ -0,0 +1,133 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// Challenge policy modes
+const (
+	ChallengeModeOff      = "off"      // never require a challenge
+	ChallengeModeAlways   = "always"   // require a challenge on every send-otp
+	ChallengeModeAdaptive = "adaptive" // require a challenge once abuse signals trip
+)
+
+// ChallengePolicy controls when send-otp requires a challenge
+type ChallengePolicy struct {
+	Mode           string
+	IPThreshold    int           // send-otp requests per IP per window before challenging
+	PhoneThreshold int           // send-otp requests per phone number per window before challenging
+	Window         time.Duration // signal counting window
+}
+
+// SendOTPChallengeUseCase decides whether a send-otp request must solve a challenge
+// before an SMS is spent, and verifies the solution when one is provided
+type SendOTPChallengeUseCase struct {
+	verifier    services.ChallengeVerifier
+	rateLimiter repositories.RateLimiter
+	policy      ChallengePolicy
+}
+
+// NewSendOTPChallengeUseCase creates a new SendOTPChallengeUseCase
+func NewSendOTPChallengeUseCase(verifier services.ChallengeVerifier, rateLimiter repositories.RateLimiter, policy ChallengePolicy) *SendOTPChallengeUseCase {
+	return &SendOTPChallengeUseCase{
+		verifier:    verifier,
+		rateLimiter: rateLimiter,
+		policy:      policy,
+	}
+}
+
+// Execute returns nil when the request may proceed, or a challenge the client must solve first
+func (uc *SendOTPChallengeUseCase) Execute(ctx context.Context, clientIP string, req *dto.SendOTPRequest) (*dto.ChallengeRequiredResponse, error) {
+	required, err := uc.isRequired(ctx, clientIP, req.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if !required {
+		return nil, nil
+	}
+
+	// A solution was supplied, verify it
+	if req.Challenge != nil {
+		err := uc.verifier.Verify(ctx, &services.ChallengeSolution{
+			Token: req.Challenge.Token,
+			Nonce: req.Challenge.Nonce,
+		}, clientIP)
+		if err == nil {
+			return nil, nil
+		}
+
+		customErr := errors.GetCustomError(err)
+		if customErr == nil || customErr.Type != errors.ValidationError {
+			return nil, err
+		}
+		return uc.newChallenge(ctx, "Challenge verification failed", customErr.Message)
+	}
+
+	return uc.newChallenge(ctx, "Challenge required", "")
+}
+
+// isRequired evaluates the policy for the request
+func (uc *SendOTPChallengeUseCase) isRequired(ctx context.Context, clientIP, phoneNumber string) (bool, error) {
+	switch uc.policy.Mode {
+	case ChallengeModeAlways:
+		return true, nil
+	case ChallengeModeAdaptive:
+		// Check both signals so each counter keeps growing
+		ipTripped := uc.signalTripped(ctx, fmt.Sprintf("challenge:ip:%s", clientIP), uc.policy.IPThreshold)
+		phoneKey := phoneNumber
+		if phone, err := valueobjects.NewPhoneNumber(phoneNumber); err == nil {
+			phoneKey = phone.String()
+		}
+		phoneTripped := uc.signalTripped(ctx, fmt.Sprintf("challenge:phone:%s", phoneKey), uc.policy.PhoneThreshold)
+		return ipTripped || phoneTripped, nil
+	default:
+		return false, nil
+	}
+}
+
+// signalTripped counts a request against an abuse signal and reports whether it exceeded its threshold
+func (uc *SendOTPChallengeUseCase) signalTripped(ctx context.Context, key string, threshold int) bool {
+	if threshold <= 0 {
+		return false
+	}
+
+	allowed, _, err := uc.rateLimiter.CheckAndIncrement(ctx, key, threshold, uc.policy.Window)
+	if err != nil {
+		// Can't evaluate the signal, err on the side of challenging
+		return true
+	}
+	return !allowed
+}
+
+// newChallenge issues a fresh challenge for the client
+func (uc *SendOTPChallengeUseCase) newChallenge(ctx context.Context, message, details string) (*dto.ChallengeRequiredResponse, error) {
+	challenge, err := uc.verifier.Issue(ctx)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to issue challenge", err)
+	}
+
+	info := dto.ChallengeInfo{
+		Type:       challenge.Type,
+		Token:      challenge.Token,
+		Difficulty: challenge.Difficulty,
+		SiteKey:    challenge.SiteKey,
+	}
+	if !challenge.ExpiresAt.IsZero() {
+		info.ExpiresAt = &challenge.ExpiresAt
+	}
+
+	return &dto.ChallengeRequiredResponse{
+		Error:     message,
+		Code:      "CHALLENGE_REQUIRED",
+		Details:   details,
+		Challenge: info,
+	}, nil
+}
@@ -0,0 +1,122 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// stubChallengeVerifier issues one fixed challenge and accepts the nonce "solved"
+type stubChallengeVerifier struct {
+	issued int
+}
+
+func (v *stubChallengeVerifier) Type() string {
+	return services.ChallengeTypeProofOfWork
+}
+
+func (v *stubChallengeVerifier) Issue(ctx context.Context) (*services.Challenge, error) {
+	v.issued++
+	return &services.Challenge{Type: services.ChallengeTypeProofOfWork, Token: "challenge", Difficulty: 8}, nil
+}
+
+func (v *stubChallengeVerifier) Verify(ctx context.Context, solution *services.ChallengeSolution, remoteIP string) error {
+	if solution.Token != "challenge" || solution.Nonce != "solved" {
+		return errors.NewValidationError("Invalid challenge solution", nil)
+	}
+	return nil
+}
+
+func newChallengeUseCase(policy ChallengePolicy) (*SendOTPChallengeUseCase, *stubChallengeVerifier) {
+	verifier := &stubChallengeVerifier{}
+	policy.Window = time.Hour
+	return NewSendOTPChallengeUseCase(verifier, &countingRateLimiter{counts: make(map[string]int)}, policy), verifier
+}
+
+func sendOTPRequest(phoneNumber string, nonce string) *dto.SendOTPRequest {
+	req := &dto.SendOTPRequest{PhoneNumber: phoneNumber}
+	if nonce != "" {
+		req.Challenge = &dto.ChallengeSolution{Token: "challenge", Nonce: nonce}
+	}
+	return req
+}
+
+func TestSendOTPChallengeUseCaseAlways(t *testing.T) {
+	uc, verifier := newChallengeUseCase(ChallengePolicy{Mode: ChallengeModeAlways})
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		nonce       string
+		wantMessage string // "" when the request may proceed
+	}{
+		{"missing solution", "", "Challenge required"},
+		{"bad solution", "wrong", "Challenge verification failed"},
+		{"valid solution", "solved", ""},
+	}
+
+	for _, tt := range tests {
+		challenge, err := uc.Execute(ctx, "1.2.3.4", sendOTPRequest("+989123456789", tt.nonce))
+		if err != nil {
+			t.Fatalf("%s: Execute() error = %v", tt.name, err)
+		}
+		if tt.wantMessage == "" {
+			if challenge != nil {
+				t.Errorf("%s: challenged again: %+v", tt.name, challenge)
+			}
+			continue
+		}
+		if challenge == nil || challenge.Error != tt.wantMessage || challenge.Code != "CHALLENGE_REQUIRED" || challenge.Challenge.Token != "challenge" {
+			t.Errorf("%s: Execute() = %+v, want a new challenge with %q", tt.name, challenge, tt.wantMessage)
+		}
+	}
+	if verifier.issued != 2 {
+		t.Errorf("challenges issued = %d, want one per rejected request", verifier.issued)
+	}
+}
+
+func TestSendOTPChallengeUseCaseAdaptive(t *testing.T) {
+	uc, _ := newChallengeUseCase(ChallengePolicy{Mode: ChallengeModeAdaptive, IPThreshold: 2, PhoneThreshold: 2})
+	ctx := context.Background()
+
+	// Under both thresholds no challenge is needed
+	for i := 0; i < 2; i++ {
+		if challenge, err := uc.Execute(ctx, "1.2.3.4", sendOTPRequest("+989123456789", "")); err != nil || challenge != nil {
+			t.Fatalf("request %d: Execute() = %+v, %v, want no challenge", i+1, challenge, err)
+		}
+	}
+
+	// The phone number trips its threshold, also when written differently
+	challenge, err := uc.Execute(ctx, "5.6.7.8", sendOTPRequest("09123456789", ""))
+	if err != nil || challenge == nil {
+		t.Fatalf("third request for the phone: Execute() = %+v, %v, want a challenge", challenge, err)
+	}
+	if challenge, err := uc.Execute(ctx, "5.6.7.8", sendOTPRequest("+989123456789", "wrong")); err != nil || challenge == nil {
+		t.Errorf("bad solution: Execute() = %+v, %v, want a new challenge", challenge, err)
+	}
+	if challenge, err := uc.Execute(ctx, "5.6.7.8", sendOTPRequest("+989123456789", "solved")); err != nil || challenge != nil {
+		t.Errorf("valid solution: Execute() = %+v, %v, want the request to proceed", challenge, err)
+	}
+
+	// The IP trips its threshold for any phone number
+	if challenge, err := uc.Execute(ctx, "1.2.3.4", sendOTPRequest("+989111111111", "")); err != nil || challenge == nil {
+		t.Errorf("third request from the IP: Execute() = %+v, %v, want a challenge", challenge, err)
+	}
+	if challenge, err := uc.Execute(ctx, "9.9.9.9", sendOTPRequest("+989122222222", "")); err != nil || challenge != nil {
+		t.Errorf("fresh IP and phone: Execute() = %+v, %v, want no challenge", challenge, err)
+	}
+}
+
+func TestSendOTPChallengeUseCaseOff(t *testing.T) {
+	uc, _ := newChallengeUseCase(ChallengePolicy{Mode: ChallengeModeOff, IPThreshold: 1, PhoneThreshold: 1})
+
+	for i := 0; i < 3; i++ {
+		if challenge, err := uc.Execute(context.Background(), "1.2.3.4", sendOTPRequest("+989123456789", "")); err != nil || challenge != nil {
+			t.Fatalf("request %d: Execute() = %+v, %v, want no challenge", i+1, challenge, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+// SessionPolicy limits how long a login session can be kept alive by refreshing
+type SessionPolicy struct {
+	AbsoluteLifetime time.Duration // Measured from the original login; 0 means no limit
+	IdleTimeout      time.Duration // Measured from the last login or refresh; 0 means no limit
+}
+
+// SessionPolicies holds the session policy of each client type
+type SessionPolicies struct {
+	Default SessionPolicy            // Used for clients without a type or with an unknown one
+	ByType  map[string]SessionPolicy // Client type -> policy
+}
+
+// Actions taken when a login would exceed the session limit
+const (
+	SessionLimitReject = "reject" // refuse the new login
+	SessionLimitEvict  = "evict"  // revoke the least recently used sessions
+)
+
+// SessionLimit caps how many active sessions a user may hold at once
+type SessionLimit struct {
+	MaxPerScope map[string]int // User scope -> cap; missing or 0 means unlimited
+	OnExceeded  string         // SessionLimitReject or SessionLimitEvict
+}
+
+// forClient returns the session policy of a client
+func (p SessionPolicies) forClient(client *entities.Client) SessionPolicy {
+	if policy, ok := p.ByType[client.Type]; ok {
+		return policy
+	}
+	return p.Default
+}
+
+// expired reports whether the session of a stored refresh token is past its
+// absolute lifetime or has been idle too long
+func (p SessionPolicy) expired(token *entities.RefreshToken, now time.Time) bool {
+	if p.AbsoluteLifetime > 0 && !now.Before(token.SessionStartedAt.Add(p.AbsoluteLifetime)) {
+		return true
+	}
+	if p.IdleTimeout > 0 && !now.Before(token.CreatedAt.Add(p.IdleTimeout)) {
+		return true
+	}
+	return false
+}
+
+// refreshExpiry returns when a refresh token issued now should expire: after
+// refreshTTL, but never past the idle timeout or the end of the session
+func (p SessionPolicy) refreshExpiry(sessionStartedAt, now time.Time, refreshTTL time.Duration) time.Time {
+	expiresAt := now.Add(refreshTTL)
+	if p.IdleTimeout > 0 && now.Add(p.IdleTimeout).Before(expiresAt) {
+		expiresAt = now.Add(p.IdleTimeout)
+	}
+	if p.AbsoluteLifetime > 0 && sessionStartedAt.Add(p.AbsoluteLifetime).Before(expiresAt) {
+		expiresAt = sessionStartedAt.Add(p.AbsoluteLifetime)
+	}
+	return expiresAt
+}
@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+func TestTokenClaimsConfigSessionClaims(t *testing.T) {
+	claims := TokenClaimsConfig{DefaultAudience: []string{"otp-auth"}}
+	admin := &entities.User{ID: "user-1", Scope: "admin"}
+	granted := "openid phone"
+
+	tests := []struct {
+		name         string
+		client       *entities.Client
+		grantedScope *string
+		wantScopes   []string
+		wantAudience []string
+	}{
+		{"first-party login", &entities.Client{ID: "web"}, nil, []string{"admin"}, []string{"otp-auth"}},
+		{"first-party login with client audience", &entities.Client{ID: "web", Audience: []string{"orders"}}, nil, []string{"admin"}, []string{"orders"}},
+		{"authorization code flow", &entities.Client{ID: "partner-app"}, &granted, []string{"openid", "phone"}, []string{"partner-app"}},
+		{"client audience", &entities.Client{ID: "partner-app", Audience: []string{"otp-auth", "partner-api"}}, &granted, []string{"openid", "phone"}, []string{"partner-api"}},
+		{"only the first-party audience", &entities.Client{ID: "partner-app", Audience: []string{"otp-auth"}}, &granted, []string{"openid", "phone"}, []string{"partner-app"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scopes, audience := claims.sessionClaims(tt.client, admin, tt.grantedScope)
+			if !slices.Equal(scopes, tt.wantScopes) || !slices.Equal(audience, tt.wantAudience) {
+				t.Errorf("sessionClaims = %v, %v, want %v, %v", scopes, audience, tt.wantScopes, tt.wantAudience)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+)
+
+// UpdateClientUseCase handles changing the settings of registered clients
+type UpdateClientUseCase struct {
+	clientRepo   repositories.ClientRepository
+	hashService  services.HashService
+	sessions     SessionPolicies
+	maxAccessTTL time.Duration
+}
+
+// NewUpdateClientUseCase creates a new UpdateClientUseCase, with the same limits
+// on settings as NewCreateClientUseCase
+func NewUpdateClientUseCase(clientRepo repositories.ClientRepository, hashService services.HashService, sessions SessionPolicies, maxAccessTTL time.Duration) *UpdateClientUseCase {
+	return &UpdateClientUseCase{
+		clientRepo:   clientRepo,
+		hashService:  hashService,
+		sessions:     sessions,
+		maxAccessTTL: maxAccessTTL,
+	}
+}
+
+// Execute replaces the settings of a client, keeping its secret unless a new one
+// is requested. Sessions already started keep their refresh tokens; changes apply
+// from their next refresh.
+func (uc *UpdateClientUseCase) Execute(ctx context.Context, clientID string, req *dto.UpdateClientRequest) (*dto.ClientInfo, error) {
+	client, err := uc.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyClientSettings(client, &req.ClientSettings, uc.sessions, uc.maxAccessTTL); err != nil {
+		return nil, err
+	}
+
+	var secret string
+	if req.RotateSecret {
+		if secret, err = issueClientSecret(client, uc.hashService); err != nil {
+			return nil, err
+		}
+	}
+
+	client.UpdatedAt = time.Now()
+	if err := uc.clientRepo.Update(ctx, client); err != nil {
+		return nil, err
+	}
+
+	info := dto.NewClientInfo(client)
+	info.ClientSecret = secret
+	return &info, nil
+}
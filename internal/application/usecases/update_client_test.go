@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+func TestUpdateClientUseCase(t *testing.T) {
+	existing := entities.NewClient("partner-app")
+	existing.SecretHash = "hash:old"
+	existing.RedirectURIs = []string{"https://partner.example.com/callback"}
+	clients := &staticClientRepo{clients: map[string]*entities.Client{existing.ID: existing}}
+	uc := NewUpdateClientUseCase(clients, &fakeHashService{}, testSessionPolicies, time.Hour)
+
+	info, err := uc.Execute(context.Background(), "partner-app", &dto.UpdateClientRequest{
+		ClientSettings: dto.ClientSettings{AllowedScopes: []string{"openid"}},
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	stored := clients.clients["partner-app"]
+	if info.ClientSecret != "" || stored.SecretHash != "hash:old" {
+		t.Errorf("secret changed without rotate_secret")
+	}
+	if stored.AllowsScope("phone") || len(stored.RedirectURIs) != 0 {
+		t.Errorf("scopes %v and redirect URIs %v, want the settings replaced", stored.AllowedScopes, stored.RedirectURIs)
+	}
+
+	info, err = uc.Execute(context.Background(), "partner-app", &dto.UpdateClientRequest{RotateSecret: true})
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if info.ClientSecret == "" || clients.clients["partner-app"].SecretHash != "hash:"+info.ClientSecret {
+		t.Errorf("rotated secret %q not stored", info.ClientSecret)
+	}
+
+	_, err = uc.Execute(context.Background(), "other-app", &dto.UpdateClientRequest{})
+	if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.NotFoundError {
+		t.Errorf("unknown client error = %v, want not found", err)
+	}
+}
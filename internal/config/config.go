@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
@@ -11,15 +12,19 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	OTP      OTPConfig      `mapstructure:"otp"`
-	Hash     HashConfig     `mapstructure:"hash"`
-	Logging  LoggingConfig  `mapstructure:"logging"`
-	CORS     CORSConfig     `mapstructure:"cors"`
-	Security SecurityConfig `mapstructure:"security"`
+	Server        ServerConfig        `mapstructure:"server"`
+	Database      DatabaseConfig      `mapstructure:"database"`
+	Redis         RedisConfig         `mapstructure:"redis"`
+	JWT           JWTConfig           `mapstructure:"jwt"`
+	Auth          AuthConfig          `mapstructure:"auth"`
+	OIDC          OIDCConfig          `mapstructure:"oidc"`
+	OAuth         OAuthConfig         `mapstructure:"oauth"`
+	OTP           OTPConfig           `mapstructure:"otp"`
+	Hash          HashConfig          `mapstructure:"hash"`
+	Logging       LoggingConfig       `mapstructure:"logging"`
+	CORS          CORSConfig          `mapstructure:"cors"`
+	Security      SecurityConfig      `mapstructure:"security"`
+	Notifications NotificationsConfig `mapstructure:"notifications"`
 }
 
 // ServerConfig holds server configuration
@@ -30,6 +35,8 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+	// Internal listener serving /metrics, kept off the public port; empty disables it
+	MetricsAddress string `mapstructure:"metrics_address"`
 }
 
 // DatabaseConfig holds database configuration
@@ -61,11 +68,104 @@ type RedisConfig struct {
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
+	Algorithm       string        `mapstructure:"algorithm"` // ES256, RS256, EdDSA
 	PrivateKeyPEM   string        `mapstructure:"private_key_pem"`
 	PublicKeyPEM    string        `mapstructure:"public_key_pem"`
+	KeysDir         string        `mapstructure:"keys_dir"` // used when both PEMs are empty
 	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
 	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
-	Issuer          string        `mapstructure:"issuer"`
+	// Reuse of a rotated refresh token within this window is treated as a client race
+	RefreshReuseGrace time.Duration `mapstructure:"refresh_reuse_grace"`
+	Issuer            string        `mapstructure:"issuer"`
+	// Audience of this service's own API; protected routes require it in "aud"
+	Audience        string            `mapstructure:"audience"`
+	DefaultClientID string            `mapstructure:"default_client_id"`
+	Clients         []JWTClientConfig `mapstructure:"clients"`
+	// Where clients are registered: "" for only the clients above, or postgres
+	// for the clients table, seeded with the clients above and managed by admins
+	ClientStore string `mapstructure:"client_store"`
+	// Extra public keys accepted for verification and published in the JWKS
+	VerificationKeysPEM []string      `mapstructure:"verification_keys_pem"`
+	KeyRing             KeyRingConfig `mapstructure:"key_ring"`
+	// Session lifetime limits per client type, e.g. web and mobile
+	Sessions map[string]SessionPolicyConfig `mapstructure:"sessions"`
+	// Concurrent session cap per user scope
+	SessionLimit SessionLimitConfig `mapstructure:"session_limit"`
+}
+
+// DefaultClientType is the type of clients that don't set one
+const DefaultClientType = "web"
+
+// JWTClientConfig holds the access token audience for one client
+type JWTClientConfig struct {
+	ID         string   `mapstructure:"id"`
+	Audience   []string `mapstructure:"audience"`    // defaults to jwt.audience
+	Type       string   `mapstructure:"type"`        // selects jwt.sessions; defaults to web
+	SecretHash string   `mapstructure:"secret_hash"` // bcrypt hash; required to call the /oauth endpoints
+	// Exact URIs the authorization code flow may redirect to
+	RedirectURIs      []string `mapstructure:"redirect_uris"`
+	AllowedScopes     []string `mapstructure:"allowed_scopes"`      // defaults to openid and phone
+	AllowedGrantTypes []string `mapstructure:"allowed_grant_types"` // authorization_code and/or client_credentials; defaults to authorization_code
+	// Token lifetime overrides; 0 uses jwt.access_token_ttl and jwt.refresh_token_ttl.
+	// Access tokens can only be made shorter-lived.
+	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
+	// Origins of the client's browser apps, allowed by CORS
+	CORSOrigins []string `mapstructure:"cors_origins"`
+}
+
+// SessionPolicyConfig holds the session lifetime limits of one client type
+type SessionPolicyConfig struct {
+	AbsoluteLifetime time.Duration `mapstructure:"absolute_lifetime"` // since login; 0 disables
+	IdleTimeout      time.Duration `mapstructure:"idle_timeout"`      // since the last refresh; 0 disables
+}
+
+// SessionLimitConfig caps how many active sessions a user may hold at once
+type SessionLimitConfig struct {
+	MaxPerScope map[string]int `mapstructure:"max_per_scope"` // scope -> cap; missing or 0 means unlimited
+	OnExceeded  string         `mapstructure:"on_exceeded"`   // reject, evict
+}
+
+// AuthConfig holds configuration for how clients present their tokens
+type AuthConfig struct {
+	TokenPrecedence string       `mapstructure:"token_precedence"` // header, cookie; wins when a request carries both
+	TokenOnly       bool         `mapstructure:"token_only"`       // return tokens in response bodies without setting cookies
+	Cookie          CookieConfig `mapstructure:"cookie"`
+	CSRF            CSRFConfig   `mapstructure:"csrf"`
+}
+
+// CookieConfig holds the attributes of the cookies the API sets
+type CookieConfig struct {
+	Secure   bool   `mapstructure:"secure"`
+	SameSite string `mapstructure:"same_site"` // lax, strict, none
+	Domain   string `mapstructure:"domain"`    // empty for host-only cookies
+	Path     string `mapstructure:"path"`
+}
+
+// CSRFConfig holds double-submit CSRF token configuration
+type CSRFConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// OIDCConfig holds OpenID Connect provider configuration
+type OIDCConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Issuer     string        `mapstructure:"issuer"` // public base URL of this service; must differ from jwt.issuer
+	IDTokenTTL time.Duration `mapstructure:"id_token_ttl"`
+}
+
+// OAuthConfig holds OAuth 2.0 authorization code flow configuration
+type OAuthConfig struct {
+	AuthorizationCodeTTL time.Duration `mapstructure:"authorization_code_ttl"`
+}
+
+// KeyRingConfig holds signing key ring and rotation configuration
+type KeyRingConfig struct {
+	Store            string        `mapstructure:"store"` // "" (static keys), directory, postgres
+	Directory        string        `mapstructure:"directory"`
+	RotationInterval time.Duration `mapstructure:"rotation_interval"`
+	PublishAhead     time.Duration `mapstructure:"publish_ahead"`
+	CheckInterval    time.Duration `mapstructure:"check_interval"`
 }
 
 // OTPConfig holds OTP configuration
@@ -75,6 +175,23 @@ type OTPConfig struct {
 	SenderType string        `mapstructure:"sender_type"` // console, sms, etc.
 }
 
+// NotificationsConfig holds user notification configuration
+type NotificationsConfig struct {
+	SenderType string                     `mapstructure:"sender_type"` // console, sms, etc.
+	NewLogin   NewLoginNotificationConfig `mapstructure:"new_login"`
+}
+
+// NewLoginNotificationConfig holds configuration for notifications about logins
+// from a device or network the user hasn't used before
+type NewLoginNotificationConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	RevokeURL     string        `mapstructure:"revoke_url"` // the link's one-time token is added as ?token=
+	RevokeLinkTTL time.Duration `mapstructure:"revoke_link_ttl"`
+	Limit         int           `mapstructure:"limit"` // notifications per user per window
+	Window        time.Duration `mapstructure:"window"`
+	DefaultLocale string        `mapstructure:"default_locale"` // en, fa
+}
+
 // HashConfig holds hash configuration
 type HashConfig struct {
 	Cost int `mapstructure:"cost"`
@@ -105,15 +222,59 @@ type CORSConfig struct {
 // SecurityConfig holds security configuration
 type SecurityConfig struct {
 	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Challenge    ChallengeConfig    `mapstructure:"challenge"`
+	IPReputation IPReputationConfig `mapstructure:"ip_reputation"`
+	// Reject access tokens while the revocation denylist (Redis) is down; admin routes always do
+	DenylistFailClosed bool `mapstructure:"denylist_fail_closed"`
+}
+
+// IPReputationConfig holds IP allow/deny list and automatic ban configuration
+type IPReputationConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	AllowCIDRs       []string      `mapstructure:"allow_cidrs"`
+	DenyCIDRs        []string      `mapstructure:"deny_cidrs"`
+	FailureThreshold int           `mapstructure:"failure_threshold"` // failed login/send-otp requests per window before a ban
+	FailureWindow    time.Duration `mapstructure:"failure_window"`
+	BanTTL           time.Duration `mapstructure:"ban_ttl"`
+	CacheTTL         time.Duration `mapstructure:"cache_ttl"` // how long each instance caches the ban list
+}
+
+// ChallengeConfig holds send-otp challenge configuration
+type ChallengeConfig struct {
+	Mode           string                 `mapstructure:"mode"` // off, always, adaptive
+	Type           string                 `mapstructure:"type"` // pow, captcha
+	IPThreshold    int                    `mapstructure:"ip_threshold"`
+	PhoneThreshold int                    `mapstructure:"phone_threshold"`
+	Window         time.Duration          `mapstructure:"window"`
+	PoW            PoWChallengeConfig     `mapstructure:"pow"`
+	Captcha        CaptchaChallengeConfig `mapstructure:"captcha"`
+}
+
+// PoWChallengeConfig holds proof-of-work challenge configuration
+type PoWChallengeConfig struct {
+	Secret     string        `mapstructure:"secret"` // shared by all replicas; random per process when empty
+	Difficulty int           `mapstructure:"difficulty"`
+	TTL        time.Duration `mapstructure:"ttl"`
+}
+
+// CaptchaChallengeConfig holds hCaptcha/Turnstile challenge configuration
+type CaptchaChallengeConfig struct {
+	VerifyURL string        `mapstructure:"verify_url"`
+	Secret    string        `mapstructure:"secret"`
+	SiteKey   string        `mapstructure:"site_key"`
+	Timeout   time.Duration `mapstructure:"timeout"`
 }
 
 // RateLimitConfig holds rate limiting configuration
 type RateLimitConfig struct {
-	Enabled    bool          `mapstructure:"enabled"`
-	Requests   int           `mapstructure:"requests"`
-	Window     time.Duration `mapstructure:"window"`
-	OTPLimit   int           `mapstructure:"otp_limit"`
-	OTPWindow  time.Duration `mapstructure:"otp_window"`
+	Enabled               bool          `mapstructure:"enabled"`
+	Requests              int           `mapstructure:"requests"`
+	Window                time.Duration `mapstructure:"window"`
+	OTPLimit              int           `mapstructure:"otp_limit"`
+	OTPWindow             time.Duration `mapstructure:"otp_window"`
+	OTPFailClosed         bool          `mapstructure:"otp_fail_closed"`         // reject send-otp while Redis is down
+	FallbackEnabled       bool          `mapstructure:"fallback_enabled"`        // use in-process limiter while Redis is down
+	FallbackProbeInterval time.Duration `mapstructure:"fallback_probe_interval"` // how often to retry Redis during an outage
 }
 
 // Load loads configuration from file and environment variables
@@ -164,6 +325,7 @@ func setDefaults() {
 	viper.SetDefault("server.read_timeout", "30s")
 	viper.SetDefault("server.write_timeout", "30s")
 	viper.SetDefault("server.idle_timeout", "120s")
+	viper.SetDefault("server.metrics_address", "127.0.0.1:9090")
 
 	// Database defaults
 	viper.SetDefault("database.host", "localhost")
@@ -191,13 +353,58 @@ func setDefaults() {
 	// JWT defaults
 	viper.SetDefault("jwt.access_token_ttl", "15m")
 	viper.SetDefault("jwt.refresh_token_ttl", "168h") // 7 days
+	viper.SetDefault("jwt.refresh_reuse_grace", "10s")
 	viper.SetDefault("jwt.issuer", "otp-auth-service")
+	viper.SetDefault("jwt.algorithm", "ES256")
+	viper.SetDefault("jwt.audience", "otp-auth")
+	viper.SetDefault("jwt.default_client_id", "otp-auth-client")
+	viper.SetDefault("jwt.keys_dir", "./keys")
+	viper.SetDefault("jwt.client_store", "")
+	viper.SetDefault("jwt.key_ring.store", "")
+	viper.SetDefault("jwt.key_ring.directory", "./keys/ring")
+	viper.SetDefault("jwt.key_ring.rotation_interval", "720h") // 30 days
+	viper.SetDefault("jwt.key_ring.publish_ahead", "24h")
+	viper.SetDefault("jwt.key_ring.check_interval", "1m")
+	viper.SetDefault("jwt.sessions.web.absolute_lifetime", "720h")     // 30 days
+	viper.SetDefault("jwt.sessions.web.idle_timeout", "168h")          // 7 days
+	viper.SetDefault("jwt.sessions.mobile.absolute_lifetime", "2160h") // 90 days
+	viper.SetDefault("jwt.sessions.mobile.idle_timeout", "720h")       // 30 days
+	viper.SetDefault("jwt.session_limit.max_per_scope.user", 5)
+	viper.SetDefault("jwt.session_limit.max_per_scope.admin", 1)
+	viper.SetDefault("jwt.session_limit.max_per_scope.superadmin", 1)
+	viper.SetDefault("jwt.session_limit.on_exceeded", "evict")
+
+	// Auth defaults
+	viper.SetDefault("auth.token_precedence", "header")
+	viper.SetDefault("auth.token_only", false)
+	viper.SetDefault("auth.cookie.secure", false)
+	viper.SetDefault("auth.cookie.same_site", "lax")
+	viper.SetDefault("auth.cookie.domain", "")
+	viper.SetDefault("auth.cookie.path", "/")
+	viper.SetDefault("auth.csrf.enabled", true)
+
+	// OIDC defaults
+	viper.SetDefault("oidc.enabled", true)
+	viper.SetDefault("oidc.issuer", "http://localhost:8080")
+	viper.SetDefault("oidc.id_token_ttl", "1h")
+
+	// OAuth defaults
+	viper.SetDefault("oauth.authorization_code_ttl", "1m")
 
 	// OTP defaults
 	viper.SetDefault("otp.length", 6)
 	viper.SetDefault("otp.ttl", "5m")
 	viper.SetDefault("otp.sender_type", "console")
 
+	// Notification defaults
+	viper.SetDefault("notifications.sender_type", "console")
+	viper.SetDefault("notifications.new_login.enabled", true)
+	viper.SetDefault("notifications.new_login.revoke_url", "http://localhost:8080/api/v1/auth/revoke-session")
+	viper.SetDefault("notifications.new_login.revoke_link_ttl", "72h")
+	viper.SetDefault("notifications.new_login.limit", 5)
+	viper.SetDefault("notifications.new_login.window", "1h")
+	viper.SetDefault("notifications.new_login.default_locale", "en")
+
 	// Hash defaults
 	viper.SetDefault("hash.cost", 10)
 
@@ -211,7 +418,7 @@ func setDefaults() {
 	viper.SetDefault("logging.compress", true)
 
 	// CORS defaults
-	viper.SetDefault("cors.allow_origins", []string{"*"})
+	viper.SetDefault("cors.allow_origins", []string{})
 	viper.SetDefault("cors.allow_methods", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
 	viper.SetDefault("cors.allow_headers", []string{"*"})
 	viper.SetDefault("cors.allow_credentials", true)
@@ -223,6 +430,26 @@ func setDefaults() {
 	viper.SetDefault("security.rate_limit.window", "1m")
 	viper.SetDefault("security.rate_limit.otp_limit", 5)
 	viper.SetDefault("security.rate_limit.otp_window", "1h")
+	viper.SetDefault("security.rate_limit.otp_fail_closed", true)
+	viper.SetDefault("security.rate_limit.fallback_enabled", true)
+	viper.SetDefault("security.rate_limit.fallback_probe_interval", "5s")
+	viper.SetDefault("security.denylist_fail_closed", true)
+	viper.SetDefault("security.challenge.mode", "adaptive")
+	viper.SetDefault("security.challenge.type", "pow")
+	viper.SetDefault("security.challenge.ip_threshold", 10)
+	viper.SetDefault("security.challenge.phone_threshold", 2)
+	viper.SetDefault("security.challenge.window", "1h")
+	viper.SetDefault("security.challenge.pow.difficulty", 20)
+	viper.SetDefault("security.challenge.pow.ttl", "2m")
+	viper.SetDefault("security.challenge.captcha.verify_url", "https://api.hcaptcha.com/siteverify")
+	viper.SetDefault("security.challenge.captcha.timeout", "5s")
+	viper.SetDefault("security.ip_reputation.enabled", true)
+	viper.SetDefault("security.ip_reputation.allow_cidrs", []string{})
+	viper.SetDefault("security.ip_reputation.deny_cidrs", []string{})
+	viper.SetDefault("security.ip_reputation.failure_threshold", 30)
+	viper.SetDefault("security.ip_reputation.failure_window", "10m")
+	viper.SetDefault("security.ip_reputation.ban_ttl", "1h")
+	viper.SetDefault("security.ip_reputation.cache_ttl", "10s")
 }
 
 // validateConfig validates the configuration
@@ -251,6 +478,173 @@ func validateConfig(config *Config) error {
 		return errors.NewValidationError("Hash cost must be between 4 and 31", nil)
 	}
 
+	switch config.JWT.Algorithm {
+	case "ES256", "RS256", "EdDSA":
+	default:
+		return errors.NewValidationError("JWT algorithm must be one of ES256, RS256, EdDSA", nil)
+	}
+
+	if config.JWT.RefreshReuseGrace < 0 {
+		return errors.NewValidationError("JWT refresh_reuse_grace must not be negative", nil)
+	}
+
+	if config.JWT.Issuer == "" {
+		return errors.NewValidationError("JWT issuer is required", nil)
+	}
+
+	for _, client := range config.JWT.Clients {
+		if client.ID == "" {
+			return errors.NewValidationError("JWT client ID is required", nil)
+		}
+		if client.SecretHash != "" && !strings.HasPrefix(client.SecretHash, "$2") {
+			return errors.NewValidationError(fmt.Sprintf("JWT client %s secret_hash must be a bcrypt hash", client.ID), nil)
+		}
+		for _, redirectURI := range client.RedirectURIs {
+			parsed, err := url.Parse(redirectURI)
+			if err != nil || !parsed.IsAbs() || parsed.Fragment != "" {
+				return errors.NewValidationError(fmt.Sprintf("JWT client %s redirect URI %s must be an absolute URL without fragment", client.ID, redirectURI), err)
+			}
+		}
+		if client.Type != "" {
+			if _, ok := config.JWT.Sessions[client.Type]; !ok {
+				return errors.NewValidationError(fmt.Sprintf("JWT client %s has type %s without a session policy", client.ID, client.Type), nil)
+			}
+		}
+		for _, grantType := range client.AllowedGrantTypes {
+			if grantType != "authorization_code" && grantType != "client_credentials" && grantType != "first_party" {
+				return errors.NewValidationError(fmt.Sprintf("JWT client %s has unsupported grant type %s", client.ID, grantType), nil)
+			}
+		}
+		if client.AccessTokenTTL < 0 || client.RefreshTokenTTL < 0 {
+			return errors.NewValidationError(fmt.Sprintf("JWT client %s token TTLs must not be negative", client.ID), nil)
+		}
+		// Token revocation and signing key retirement assume no access token outlives jwt.access_token_ttl
+		if client.AccessTokenTTL > config.JWT.AccessTokenTTL {
+			return errors.NewValidationError(fmt.Sprintf("JWT client %s access_token_ttl must not exceed jwt.access_token_ttl", client.ID), nil)
+		}
+		for _, origin := range client.CORSOrigins {
+			parsed, err := url.Parse(origin)
+			if err != nil || parsed.Scheme+"://"+parsed.Host != origin || parsed.Host == "" {
+				return errors.NewValidationError(fmt.Sprintf("JWT client %s CORS origin %s must be a scheme and host", client.ID, origin), err)
+			}
+		}
+	}
+
+	// The production router allows credentials, so a wildcard would let any
+	// site read cookie-authenticated responses
+	for _, origin := range config.CORS.AllowOrigins {
+		if origin == "*" {
+			return errors.NewValidationError("CORS allow_origins must list origins; * is not allowed with credentials", nil)
+		}
+	}
+
+	switch config.JWT.ClientStore {
+	case "", "postgres":
+	default:
+		return errors.NewValidationError("JWT client_store must be postgres or empty", nil)
+	}
+
+	for clientType, policy := range config.JWT.Sessions {
+		if policy.AbsoluteLifetime < 0 || policy.IdleTimeout < 0 {
+			return errors.NewValidationError(fmt.Sprintf("Session limits for %s clients must not be negative", clientType), nil)
+		}
+	}
+
+	switch config.JWT.SessionLimit.OnExceeded {
+	case "reject", "evict":
+	default:
+		return errors.NewValidationError("Session limit on_exceeded must be one of reject, evict", nil)
+	}
+	for scope, maxSessions := range config.JWT.SessionLimit.MaxPerScope {
+		if maxSessions < 0 {
+			return errors.NewValidationError(fmt.Sprintf("Session limit for scope %s must not be negative", scope), nil)
+		}
+	}
+
+	switch config.Auth.TokenPrecedence {
+	case "header", "cookie":
+	default:
+		return errors.NewValidationError("Auth token_precedence must be one of header, cookie", nil)
+	}
+
+	switch config.Auth.Cookie.SameSite {
+	case "lax", "strict":
+	case "none":
+		if !config.Auth.Cookie.Secure {
+			return errors.NewValidationError("Auth cookie same_site none requires secure", nil)
+		}
+	default:
+		return errors.NewValidationError("Auth cookie same_site must be one of lax, strict, none", nil)
+	}
+
+	if !strings.HasPrefix(config.Auth.Cookie.Path, "/") {
+		return errors.NewValidationError("Auth cookie path must start with /", nil)
+	}
+
+	if oidc := config.OIDC; oidc.Enabled {
+		issuerURL, err := url.Parse(oidc.Issuer)
+		if err != nil || !issuerURL.IsAbs() || issuerURL.RawQuery != "" || issuerURL.Fragment != "" {
+			return errors.NewValidationError("OIDC issuer must be an absolute URL without query or fragment", err)
+		}
+		// Protected routes pin jwt.issuer, which keeps ID tokens from passing as access tokens
+		if oidc.Issuer == config.JWT.Issuer {
+			return errors.NewValidationError("OIDC issuer must differ from the JWT issuer", nil)
+		}
+		if oidc.IDTokenTTL <= 0 {
+			return errors.NewValidationError("OIDC id_token_ttl must be positive", nil)
+		}
+	}
+
+	if config.OAuth.AuthorizationCodeTTL <= 0 || config.OAuth.AuthorizationCodeTTL > 10*time.Minute {
+		return errors.NewValidationError("OAuth authorization_code_ttl must be positive and at most 10m", nil)
+	}
+
+	switch config.JWT.KeyRing.Store {
+	case "":
+	case "directory", "postgres":
+		if config.JWT.KeyRing.PublishAhead >= config.JWT.KeyRing.RotationInterval {
+			return errors.NewValidationError("Key ring publish_ahead must be shorter than rotation_interval", nil)
+		}
+		if config.JWT.KeyRing.CheckInterval <= 0 {
+			return errors.NewValidationError("Key ring check_interval must be positive", nil)
+		}
+	default:
+		return errors.NewValidationError("Key ring store must be one of directory, postgres or empty", nil)
+	}
+
+	switch config.Security.Challenge.Mode {
+	case "off", "always", "adaptive":
+	default:
+		return errors.NewValidationError("Challenge mode must be one of off, always, adaptive", nil)
+	}
+
+	if config.Security.Challenge.Mode != "off" {
+		switch config.Security.Challenge.Type {
+		case "pow":
+			if config.Security.Challenge.PoW.Secret == "" {
+				return errors.NewValidationError("Proof-of-work secret is required when challenge type is pow", nil)
+			}
+		case "captcha":
+			if config.Security.Challenge.Captcha.Secret == "" {
+				return errors.NewValidationError("Captcha secret is required when challenge type is captcha", nil)
+			}
+		default:
+			return errors.NewValidationError("Challenge type must be one of pow, captcha", nil)
+		}
+	}
+
+	if newLogin := config.Notifications.NewLogin; newLogin.Enabled {
+		if revokeURL, err := url.Parse(newLogin.RevokeURL); err != nil || !revokeURL.IsAbs() {
+			return errors.NewValidationError("New login notification revoke_url must be an absolute URL", err)
+		}
+		if newLogin.RevokeLinkTTL <= 0 || newLogin.Window <= 0 {
+			return errors.NewValidationError("New login notification revoke_link_ttl and window must be positive", nil)
+		}
+		if newLogin.Limit < 1 {
+			return errors.NewValidationError("New login notification limit must be at least 1", nil)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"time"
+)
+
+// AuthorizationCode is a one-time code of the OAuth 2.0 authorization code flow. It
+// records who logged in on the hosted page; the session and its tokens are only
+// created when the client exchanges the code.
+type AuthorizationCode struct {
+	ClientID      string    `json:"client_id"`
+	RedirectURI   string    `json:"redirect_uri"`   // must be repeated in the token request
+	CodeChallenge string    `json:"code_challenge"` // PKCE S256 challenge
+	Scope         string    `json:"scope"`          // space separated, as granted
+	UserID        string    `json:"user_id"`
+	SessionID     string    `json:"session_id"`
+	Nonce         string    `json:"nonce,omitempty"` // copied into the ID token
+	AuthTime      time.Time `json:"auth_time"`       // when the user logged in
+
+	// The browser the user logged in with, recorded as the session's device
+	// instead of the client's backend that exchanges the code
+	UserAgent      string `json:"user_agent,omitempty"`
+	IPAddress      string `json:"ip_address,omitempty"`
+	AcceptLanguage string `json:"accept_language,omitempty"`
+
+	// Reused is set by the repository when the code was exchanged before
+	Reused bool `json:"-"`
+}
@@ -0,0 +1,70 @@
+package entities
+
+import "time"
+
+// Client represents an application that obtains or inspects tokens
+type Client struct {
+	ID         string   `json:"id"`
+	SecretHash string   `json:"-"`        // bcrypt hash of the client secret, empty for public clients
+	Type       string   `json:"type"`     // selects the session policy, e.g. web or mobile
+	Audience   []string `json:"audience"` // access token audience, empty for the default
+	// Where the authorization endpoint may send the user back to; matched exactly
+	RedirectURIs      []string `json:"redirect_uris"`
+	AllowedScopes     []string `json:"allowed_scopes"`      // scopes the client may request
+	AllowedGrantTypes []string `json:"allowed_grant_types"` // OAuth grant types the client may use
+	// Token lifetimes for this client; zero uses the service default
+	AccessTokenTTL  time.Duration `json:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `json:"refresh_token_ttl"`
+	// Browser origins allowed to call the API on behalf of this client
+	CORSOrigins []string  `json:"cors_origins"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewClient creates a client that may use the authorization code grant with the
+// openid and phone scopes, the settings clients get unless configured otherwise
+func NewClient(id string) *Client {
+	now := time.Now()
+	return &Client{
+		ID:                id,
+		AllowedScopes:     []string{"openid", "phone"},
+		AllowedGrantTypes: []string{"authorization_code"},
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+}
+
+// IsConfidential reports whether the client has a secret to authenticate with
+func (c *Client) IsConfidential() bool {
+	return c.SecretHash != ""
+}
+
+// AllowsRedirectURI reports whether uri is one of the client's registered redirect URIs
+func (c *Client) AllowsRedirectURI(uri string) bool {
+	return contains(c.RedirectURIs, uri)
+}
+
+// AllowsScope reports whether the client may request scope
+func (c *Client) AllowsScope(scope string) bool {
+	return contains(c.AllowedScopes, scope)
+}
+
+// AllowsGrantType reports whether the client may use the OAuth grant type
+func (c *Client) AllowsGrantType(grantType string) bool {
+	return contains(c.AllowedGrantTypes, grantType)
+}
+
+// AllowsCORSOrigin reports whether browsers at origin may call the API for the client
+func (c *Client) AllowsCORSOrigin(origin string) bool {
+	return contains(c.CORSOrigins, origin)
+}
+
+// contains reports whether values holds value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/otp-auth/internal/domain/valueobjects"
+)
+
+// Device represents a client device a user has logged in from
+type Device struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	Fingerprint string    `json:"fingerprint"` // see DeviceFingerprint
+	DeclaredID  string    `json:"declared_id"` // device ID sent by the client, empty for browsers
+	Name        string    `json:"name"`
+	UserAgent   string    `json:"user_agent"`
+	OS          string    `json:"os"`      // parsed from UserAgent
+	Browser     string    `json:"browser"` // parsed from UserAgent
+	AppVersion  string    `json:"app_version"`
+	FirstIP     string    `json:"first_ip"`
+	LastIP      string    `json:"last_ip"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// DeviceFingerprint identifies a user's device: the client-declared device ID
+// when there is one, otherwise the User-Agent
+func DeviceFingerprint(declaredID, userAgent string) string {
+	source := "ua:" + truncateUserAgent(userAgent)
+	if declaredID != "" {
+		source = "id:" + declaredID
+	}
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewDevice creates a device seen for the first time
+func NewDevice(userID, declaredID, userAgent string) *Device {
+	now := time.Now()
+	return &Device{
+		UserID:      userID,
+		Fingerprint: DeviceFingerprint(declaredID, userAgent),
+		DeclaredID:  declaredID,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}
+}
+
+// Seen records a login or refresh from the device, keeping earlier values for unknown fields
+func (d *Device) Seen(name, userAgent, ipAddress, appVersion string) {
+	if name != "" {
+		d.Name = name
+	}
+	if userAgent != "" {
+		d.UserAgent = truncateUserAgent(userAgent)
+		ua := valueobjects.UserAgent(d.UserAgent)
+		d.OS = ua.OS()
+		d.Browser = ua.Browser()
+	}
+	if ipAddress != "" {
+		if d.FirstIP == "" {
+			d.FirstIP = ipAddress
+		}
+		d.LastIP = ipAddress
+	}
+	if appVersion != "" {
+		d.AppVersion = appVersion
+	}
+	d.LastSeenAt = time.Now()
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/otp-auth/internal/domain/valueobjects"
+)
+
+// IPBan represents a banned IP address or CIDR block
+type IPBan struct {
+	Range     valueobjects.IPRange `json:"range"`
+	Reason    string               `json:"reason"`
+	Source    string               `json:"source"` // manual, auto
+	CreatedAt time.Time            `json:"created_at"`
+	ExpiresAt *time.Time           `json:"expires_at"` // nil for permanent bans
+}
+
+// IPBanSource constants
+const (
+	IPBanSourceManual = "manual"
+	IPBanSourceAuto   = "auto"
+)
+
+// NewIPBan creates a new IP ban; a zero ttl makes the ban permanent
+func NewIPBan(ipRange valueobjects.IPRange, reason, source string, ttl time.Duration) *IPBan {
+	now := time.Now()
+	ban := &IPBan{
+		Range:     ipRange,
+		Reason:    reason,
+		Source:    source,
+		CreatedAt: now,
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		ban.ExpiresAt = &expiresAt
+	}
+	return ban
+}
+
+// IsExpired checks if the ban has expired
+func (b *IPBan) IsExpired() bool {
+	return b.ExpiresAt != nil && time.Now().After(*b.ExpiresAt)
+}
@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"strings"
 	"time"
 
 	"github.com/otp-auth/internal/domain/valueobjects"
@@ -11,6 +12,15 @@ type RefreshToken struct {
 	ID               string                    `json:"id"`
 	UserID           string                    `json:"user_id"`
 	SessionID        valueobjects.SessionID    `json:"session_id"`
+	ClientID         string                    `json:"client_id"`
+	FamilyID         string                    `json:"family_id"` // ID of the login's first token; shared by every rotation
+	ParentID         string                    `json:"parent_id"` // token this one was rotated from, empty for the first
+	SessionStartedAt time.Time                 `json:"session_started_at"` // login time of the family's first token
+	DeviceID         string                    `json:"device_id"` // device the session was started on, empty if unknown
+	DeviceName       string                    `json:"device_name"` // name given by the client at login
+	UserAgent        string                    `json:"user_agent"` // User-Agent of the login or refresh that issued the token
+	IPAddress        string                    `json:"ip_address"` // client IP of the login or refresh that issued the token
+	Scope            *string                   `json:"scope"` // space separated scopes granted through the authorization code flow; nil for first-party logins
 	TokenHash        string                    `json:"token_hash"`
 	CreatedAt        time.Time                 `json:"created_at"`
 	ExpiresAt        time.Time                 `json:"expires_at"`
@@ -22,10 +32,15 @@ type RefreshToken struct {
 
 // RevokeReason constants
 const (
-	RevokeReasonRefresh = "REFRESH"
-	RevokeReasonLogout  = "LOGOUT"
-	RevokeReasonExpired = "EXPIRED"
-	RevokeReasonAdmin   = "ADMIN"
+	RevokeReasonRefresh   = "REFRESH"
+	RevokeReasonLogout    = "LOGOUT"
+	RevokeReasonExpired   = "EXPIRED"
+	RevokeReasonAdmin     = "ADMIN"
+	RevokeReasonReuse     = "REUSE"
+	RevokeReasonNotMe     = "NOT_ME"     // user followed the revoke link of a new-login notification
+	RevokeReasonEvicted   = "EVICTED"    // least recently used session, ended to stay within the session limit
+	RevokeReasonClient    = "CLIENT"     // revoked by the client it was issued to, through /oauth/revoke
+	RevokeReasonCodeReuse = "CODE_REUSE" // the session's authorization code was exchanged again
 )
 
 // NewRefreshToken creates a new refresh token
@@ -63,4 +78,53 @@ func (rt *RefreshToken) Revoke(reason string) {
 func (rt *RefreshToken) UpdateLastUsed() {
 	now := time.Now()
 	rt.LastUsed = &now
-}
\ No newline at end of file
+}
+
+// StartFamily makes the token the first of a new token family
+func (rt *RefreshToken) StartFamily() {
+	rt.FamilyID = rt.ID
+	rt.ParentID = ""
+	rt.SessionStartedAt = rt.CreatedAt
+}
+
+// InheritFamily links the token to the one it was rotated from
+func (rt *RefreshToken) InheritFamily(parent *RefreshToken) {
+	rt.FamilyID = parent.FamilyID
+	if rt.FamilyID == "" {
+		rt.FamilyID = parent.ID
+	}
+	rt.ParentID = parent.ID
+	rt.SessionStartedAt = parent.SessionStartedAt
+	if rt.SessionStartedAt.IsZero() {
+		rt.SessionStartedAt = parent.CreatedAt
+	}
+	rt.DeviceID = parent.DeviceID
+	rt.DeviceName = parent.DeviceName
+	rt.Scope = parent.Scope
+}
+
+// MaxUserAgentLength is the longest User-Agent stored with a refresh token
+const MaxUserAgentLength = 512
+
+// RecordClient stores where the token was issued to, keeping earlier values for unknown fields
+func (rt *RefreshToken) RecordClient(userAgent, ipAddress string) {
+	if userAgent != "" {
+		rt.UserAgent = truncateUserAgent(userAgent)
+	}
+	if ipAddress != "" {
+		rt.IPAddress = ipAddress
+	}
+}
+
+// truncateUserAgent cuts a User-Agent to MaxUserAgentLength bytes without splitting a character
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) > MaxUserAgentLength {
+		return strings.ToValidUTF8(userAgent[:MaxUserAgentLength], "")
+	}
+	return userAgent
+}
+
+// RevokedWithin reports whether the token was revoked less than d before now
+func (rt *RefreshToken) RevokedWithin(now time.Time, d time.Duration) bool {
+	return rt.RevokedAt != nil && now.Sub(*rt.RevokedAt) < d
+}
@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"time"
+)
+
+// SigningKeyStatus is the position of a key in the signing key ring
+type SigningKeyStatus string
+
+// SigningKeyStatus constants
+const (
+	// SigningKeyStatusNext keys are published in the JWKS but not used for signing yet
+	SigningKeyStatusNext SigningKeyStatus = "next"
+	// SigningKeyStatusActive is the single key new tokens are signed with
+	SigningKeyStatusActive SigningKeyStatus = "active"
+	// SigningKeyStatusRetired keys only verify tokens issued before they were retired
+	SigningKeyStatusRetired SigningKeyStatus = "retired"
+)
+
+// SigningKey represents a JWT signing key pair in the key ring
+type SigningKey struct {
+	ID            string           `json:"kid"`
+	Algorithm     string           `json:"alg"`
+	PrivateKeyPEM string           `json:"private_key_pem"`
+	PublicKeyPEM  string           `json:"public_key_pem"`
+	Status        SigningKeyStatus `json:"status"`
+	CreatedAt     time.Time        `json:"created_at"`
+	ActivatedAt   *time.Time       `json:"activated_at,omitempty"`
+	RetiredAt     *time.Time       `json:"retired_at,omitempty"`
+}
+
+// Activate makes the key the one new tokens are signed with
+func (k *SigningKey) Activate(now time.Time) {
+	k.Status = SigningKeyStatusActive
+	k.ActivatedAt = &now
+}
+
+// Retire stops the key from signing; it keeps verifying until its tokens expire
+func (k *SigningKey) Retire(now time.Time) {
+	k.Status = SigningKeyStatusRetired
+	k.RetiredAt = &now
+}
+
+// IsDue checks if an active key has been in use for at least the rotation interval
+func (k *SigningKey) IsDue(now time.Time, rotationInterval time.Duration) bool {
+	return k.Status == SigningKeyStatusActive && k.ActivatedAt != nil && !now.Before(k.ActivatedAt.Add(rotationInterval))
+}
+
+// IsPublishedFor checks if the key has been published for at least the given duration
+func (k *SigningKey) IsPublishedFor(now time.Time, d time.Duration) bool {
+	return !now.Before(k.CreatedAt.Add(d))
+}
+
+// CanVerify checks if tokens signed by the key may still be valid,
+// given the longest lifetime of a token it could have signed
+func (k *SigningKey) CanVerify(now time.Time, maxTokenTTL time.Duration) bool {
+	if k.Status != SigningKeyStatusRetired {
+		return true
+	}
+	return k.RetiredAt == nil || now.Before(k.RetiredAt.Add(maxTokenTTL))
+}
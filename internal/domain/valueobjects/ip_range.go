@@ -0,0 +1,80 @@
+package valueobjects
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// IPRange represents a validated IP address or CIDR block in canonical CIDR notation
+type IPRange string
+
+// NewIPRange creates and validates an IP range from an IP address or CIDR block.
+// Single addresses are normalized to /32 (IPv4) or /128 (IPv6).
+func NewIPRange(value string) (IPRange, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", errors.New("IP range cannot be empty")
+	}
+
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return "", errors.New("invalid IP address")
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return IPRange(ip4.String() + "/32"), nil
+		}
+		return IPRange(ip.String() + "/128"), nil
+	}
+
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return "", errors.New("invalid CIDR block")
+	}
+	return IPRange(network.String()), nil
+}
+
+// Network prefix lengths used by NewIPNetwork
+const (
+	ipv4NetworkBits = 24
+	ipv6NetworkBits = 48
+)
+
+// NewIPNetwork returns the network an address belongs to: its /24 for IPv4 or
+// its /48 for IPv6, roughly one subscriber line or site
+func NewIPNetwork(address string) (IPRange, error) {
+	ip := net.ParseIP(strings.TrimSpace(address))
+	if ip == nil {
+		return "", errors.New("invalid IP address")
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		network := &net.IPNet{IP: ip4.Mask(net.CIDRMask(ipv4NetworkBits, 32)), Mask: net.CIDRMask(ipv4NetworkBits, 32)}
+		return IPRange(network.String()), nil
+	}
+	network := &net.IPNet{IP: ip.Mask(net.CIDRMask(ipv6NetworkBits, 128)), Mask: net.CIDRMask(ipv6NetworkBits, 128)}
+	return IPRange(network.String()), nil
+}
+
+// String returns the CIDR notation of the range
+func (r IPRange) String() string {
+	return string(r)
+}
+
+// Network parses the range, for callers that match many addresses against it
+func (r IPRange) Network() (*net.IPNet, error) {
+	_, network, err := net.ParseCIDR(string(r))
+	if err != nil {
+		return nil, errors.New("invalid CIDR block")
+	}
+	return network, nil
+}
+
+// Contains checks if the IP address falls within the range
+func (r IPRange) Contains(ip net.IP) bool {
+	network, err := r.Network()
+	if err != nil || ip == nil {
+		return false
+	}
+	return network.Contains(ip)
+}
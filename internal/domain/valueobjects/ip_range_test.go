@@ -0,0 +1,116 @@
+package valueobjects
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIPRange_NewIPRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "single IPv4 address should be normalized to /32",
+			input:    "203.0.113.7",
+			expected: "203.0.113.7/32",
+		},
+		{
+			name:     "single IPv6 address should be normalized to /128",
+			input:    "2001:db8::1",
+			expected: "2001:db8::1/128",
+		},
+		{
+			name:     "CIDR block should be normalized to its network address",
+			input:    "10.1.2.3/8",
+			expected: "10.0.0.0/8",
+		},
+		{
+			name:    "invalid address should return error",
+			input:   "not-an-ip",
+			wantErr: true,
+		},
+		{
+			name:    "empty value should return error",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ipRange, err := NewIPRange(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewIPRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && ipRange.String() != tt.expected {
+				t.Errorf("NewIPRange() = %v, want %v", ipRange.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestIPRange_Contains(t *testing.T) {
+	ipRange, _ := NewIPRange("192.168.0.0/16")
+
+	if !ipRange.Contains(net.ParseIP("192.168.10.20")) {
+		t.Error("Contains() should match an address inside the block")
+	}
+	if ipRange.Contains(net.ParseIP("10.0.0.1")) {
+		t.Error("Contains() should not match an address outside the block")
+	}
+
+	network, err := ipRange.Network()
+	if err != nil || network.String() != "192.168.0.0/16" {
+		t.Errorf("Network() = %v, %v, want 192.168.0.0/16", network, err)
+	}
+	if _, err := IPRange("not-a-range").Network(); err == nil {
+		t.Error("Network() should reject a malformed range")
+	}
+}
+
+func TestIPRange_NewIPNetwork(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "IPv4 address should map to its /24",
+			input:    "203.0.113.7",
+			expected: "203.0.113.0/24",
+		},
+		{
+			name:     "IPv6 address should map to its /48",
+			input:    "2001:db8:1234:5678::1",
+			expected: "2001:db8:1234::/48",
+		},
+		{
+			name:     "IPv4-mapped IPv6 address should map to the IPv4 /24",
+			input:    "::ffff:198.51.100.23",
+			expected: "198.51.100.0/24",
+		},
+		{
+			name:    "CIDR block should return error",
+			input:   "203.0.113.0/24",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, err := NewIPNetwork(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewIPNetwork() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && network.String() != tt.expected {
+				t.Errorf("NewIPNetwork() = %v, want %v", network.String(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package valueobjects
+
+import (
+	"strings"
+)
+
+// UserAgent represents an HTTP User-Agent header
+type UserAgent string
+
+// uaRule maps a User-Agent token to a display name; the first matching rule wins
+type uaRule struct {
+	token string
+	name  string
+}
+
+// Order matters: e.g. Edge and Opera also send "Chrome", and Chrome also sends "Safari"
+var (
+	osRules = []uaRule{
+		{"Windows", "Windows"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Android", "Android"},
+		{"CrOS", "ChromeOS"},
+		{"Mac OS X", "macOS"},
+		{"Macintosh", "macOS"},
+		{"Linux", "Linux"},
+	}
+	browserRules = []uaRule{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"SamsungBrowser/", "Samsung Internet"},
+		{"Firefox/", "Firefox"},
+		{"FxiOS/", "Firefox"},
+		{"CriOS/", "Chrome"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"okhttp/", "Android app"},
+		{"CFNetwork/", "iOS app"},
+	}
+)
+
+// OS returns the operating system named by the User-Agent, or "" if unknown
+func (u UserAgent) OS() string {
+	return matchUARule(string(u), osRules)
+}
+
+// Browser returns the browser or HTTP client named by the User-Agent, or "" if unknown
+func (u UserAgent) Browser() string {
+	return matchUARule(string(u), browserRules)
+}
+
+// String returns the raw header value
+func (u UserAgent) String() string {
+	return string(u)
+}
+
+func matchUARule(value string, rules []uaRule) string {
+	for _, rule := range rules {
+		if strings.Contains(value, rule.token) {
+			return rule.name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package valueobjects
+
+import "testing"
+
+func TestUserAgent_OSAndBrowser(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		os      string
+		browser string
+	}{
+		{
+			name:    "Chrome on Windows",
+			input:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			os:      "Windows",
+			browser: "Chrome",
+		},
+		{
+			name:    "Edge is not reported as Chrome",
+			input:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+			os:      "Windows",
+			browser: "Edge",
+		},
+		{
+			name:    "Safari on iPhone is not reported as macOS",
+			input:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			os:      "iOS",
+			browser: "Safari",
+		},
+		{
+			name:    "Firefox on Android is not reported as Linux",
+			input:   "Mozilla/5.0 (Android 14; Mobile; rv:121.0) Gecko/121.0 Firefox/121.0",
+			os:      "Android",
+			browser: "Firefox",
+		},
+		{
+			name:  "unknown client",
+			input: "curl/8.4.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ua := UserAgent(tt.input)
+			if ua.OS() != tt.os {
+				t.Errorf("OS() = %q, want %q", ua.OS(), tt.os)
+			}
+			if ua.Browser() != tt.browser {
+				t.Errorf("Browser() = %q, want %q", ua.Browser(), tt.browser)
+			}
+		})
+	}
+}
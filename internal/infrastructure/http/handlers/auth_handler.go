@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"io"
+	"log"
 	"net/http"
 	"time"
 
@@ -8,24 +10,30 @@ import (
 
 	"github.com/otp-auth/internal/application/dto"
 	"github.com/otp-auth/internal/application/usecases"
+	"github.com/otp-auth/internal/infrastructure/http/middleware"
 	"github.com/otp-auth/pkg/errors"
 )
 
 // AuthHandler handles authentication-related HTTP requests
 type AuthHandler struct {
-	sendOTPUseCase *usecases.SendOTPUseCase
-	loginUseCase   *usecases.LoginUseCase
-	refreshUseCase *usecases.RefreshUseCase
-	logoutUseCase  *usecases.LogoutUseCase
+	sendOTPUseCase          *usecases.SendOTPUseCase
+	sendOTPChallengeUseCase *usecases.SendOTPChallengeUseCase
+	loginUseCase            *usecases.LoginUseCase
+	refreshUseCase          *usecases.RefreshUseCase
+	logoutUseCase           *usecases.LogoutUseCase
+	transport               middleware.TokenTransport
 }
 
 // NewAuthHandler creates a new AuthHandler
-func NewAuthHandler(sendOTPUseCase *usecases.SendOTPUseCase, loginUseCase *usecases.LoginUseCase, refreshUseCase *usecases.RefreshUseCase, logoutUseCase *usecases.LogoutUseCase) *AuthHandler {
+// sendOTPChallengeUseCase may be nil, in which case send-otp never requires a challenge
+func NewAuthHandler(sendOTPUseCase *usecases.SendOTPUseCase, sendOTPChallengeUseCase *usecases.SendOTPChallengeUseCase, loginUseCase *usecases.LoginUseCase, refreshUseCase *usecases.RefreshUseCase, logoutUseCase *usecases.LogoutUseCase, transport middleware.TokenTransport) *AuthHandler {
 	return &AuthHandler{
-		sendOTPUseCase: sendOTPUseCase,
-		loginUseCase:   loginUseCase,
-		refreshUseCase: refreshUseCase,
-		logoutUseCase:  logoutUseCase,
+		sendOTPUseCase:          sendOTPUseCase,
+		sendOTPChallengeUseCase: sendOTPChallengeUseCase,
+		loginUseCase:            loginUseCase,
+		refreshUseCase:          refreshUseCase,
+		logoutUseCase:           logoutUseCase,
+		transport:               transport,
 	}
 }
 
@@ -38,6 +46,7 @@ func NewAuthHandler(sendOTPUseCase *usecases.SendOTPUseCase, loginUseCase *useca
 // @Param request body dto.SendOTPRequest true "Send OTP request"
 // @Success 200 {object} dto.SendOTPResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 428 {object} dto.ChallengeRequiredResponse
 // @Failure 429 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /auth/send-otp [post]
@@ -48,11 +57,8 @@ func (h *AuthHandler) SendOTP(c *gin.Context) {
 		return
 	}
 
-	// Check for existing session_id in cookies
-	if sessionIDCookie, err := c.Cookie("session_id"); err == nil && sessionIDCookie != "" {
-		// Use existing session_id from cookie
-		req.SessionID = sessionIDCookie
-	}
+	// Reuse an existing session from the body or cookie
+	req.SessionID = h.transport.Pick(c, "session_id", req.SessionID)
 
 	// Validate request
 	if err := req.Validate(); err != nil {
@@ -60,6 +66,19 @@ func (h *AuthHandler) SendOTP(c *gin.Context) {
 		return
 	}
 
+	// Require a solved challenge before spending an SMS when the policy says so
+	if h.sendOTPChallengeUseCase != nil {
+		challenge, err := h.sendOTPChallengeUseCase.Execute(c.Request.Context(), c.ClientIP(), &req)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		if challenge != nil {
+			c.JSON(http.StatusPreconditionRequired, challenge)
+			return
+		}
+	}
+
 	// Execute use case
 	response, err := h.sendOTPUseCase.Execute(c.Request.Context(), &req)
 	if err != nil {
@@ -68,7 +87,7 @@ func (h *AuthHandler) SendOTP(c *gin.Context) {
 	}
 
 	// Set session ID as httpOnly cookie
-	c.SetCookie("session_id", response.SessionID, 259200, "/", "", false, true)
+	h.setCookie(c, "session_id", response.SessionID, 259200)
 
 	c.JSON(http.StatusOK, response)
 }
@@ -98,13 +117,20 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Get session ID from cookie
-	sessionID, err := c.Cookie("session_id")
-	if err != nil {
-		h.handleError(c, errors.NewUnauthorizedError("Session ID not found in cookies", err))
+	// Get session ID from the body or cookie
+	sessionID := h.transport.Pick(c, "session_id", req.SessionID)
+	if sessionID == "" {
+		h.handleError(c, errors.NewUnauthorizedError("Session ID not provided", nil))
 		return
 	}
 
+	req.UserAgent = c.Request.UserAgent()
+	req.IPAddress = c.ClientIP()
+	req.AcceptLanguage = c.GetHeader("Accept-Language")
+	if req.AppVersion == "" {
+		req.AppVersion = c.GetHeader("X-App-Version")
+	}
+
 	// Execute login use case
 	response, err := h.loginUseCase.Execute(c.Request.Context(), &req, sessionID)
 	if err != nil {
@@ -112,14 +138,20 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	// Issue the CSRF token cookie-authenticated requests must echo back
+	if err := middleware.IssueCSRFToken(c, h.transport, int(response.RefreshExpiresAt.Sub(time.Now()).Seconds())); err != nil {
+		h.handleError(c, errors.NewInternalError("Failed to issue CSRF token", err))
+		return
+	}
+
 	// Set access token as HTTP-only cookie
-	c.SetCookie("access_token", response.AccessToken, int(response.ExpiresAt.Sub(time.Now()).Seconds()), "/", "", false, true)
+	h.setCookie(c, "access_token", response.AccessToken, int(response.ExpiresAt.Sub(time.Now()).Seconds()))
 
 	// Set refresh token as HTTP-only cookie
-	c.SetCookie("refresh_token", response.RefreshToken, int(response.RefreshExpiresAt.Sub(time.Now()).Seconds()), "/", "", false, true)
+	h.setCookie(c, "refresh_token", response.RefreshToken, int(response.RefreshExpiresAt.Sub(time.Now()).Seconds()))
 
 	// Set session ID as HTTP-only cookie
-	c.SetCookie("session_id", sessionID, int(response.RefreshExpiresAt.Sub(time.Now()).Seconds()), "/", "", false, true)
+	h.setCookie(c, "session_id", sessionID, int(response.RefreshExpiresAt.Sub(time.Now()).Seconds()))
 
 	c.JSON(http.StatusOK, response)
 }
@@ -130,29 +162,43 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param request body dto.RefreshTokenRequest true "Refresh token request"
+// @Param request body dto.RefreshTokenRequest false "Refresh token request; fields default to cookies"
 // @Success 200 {object} dto.RefreshTokenResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	refreshToken, err := c.Cookie("refresh_token")
-	if err != nil {
-		h.handleError(c, errors.NewUnauthorizedError("Refresh token not found in cookies", err))
+	var req dto.RefreshTokenRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		h.handleError(c, errors.NewValidationError("Invalid request format", err))
 		return
 	}
 
-	sessionID, err := c.Cookie("session_id")
-	if err != nil {
-		h.handleError(c, errors.NewUnauthorizedError("Session ID not found in cookies", err))
+	var refreshCookie, sessionCookie bool
+	req.RefreshToken, refreshCookie = h.transport.PickCredential(c, "refresh_token", req.RefreshToken)
+	if req.RefreshToken == "" {
+		h.handleError(c, errors.NewUnauthorizedError("Refresh token not provided", nil))
+		return
+	}
+
+	req.SessionID, sessionCookie = h.transport.PickCredential(c, "session_id", req.SessionID)
+	if req.SessionID == "" {
+		h.handleError(c, errors.NewUnauthorizedError("Session ID not provided", nil))
 		return
 	}
 
-	req := dto.RefreshTokenRequest{
-		refreshToken,
-		sessionID,
+	// Cookies are sent with cross-site requests too, whatever the Authorization header
+	if (refreshCookie || sessionCookie) && !middleware.ValidCSRFToken(c, h.transport) {
+		h.handleError(c, errors.NewForbiddenError("Missing or invalid CSRF token", nil))
+		return
 	}
+
+	req.UserAgent = c.Request.UserAgent()
+	req.IPAddress = c.ClientIP()
+	req.AppVersion = c.GetHeader("X-App-Version")
+
 	// Validate request
 	if err := req.Validate(); err != nil {
 		h.handleError(c, errors.NewValidationError("Invalid request data", err))
@@ -166,11 +212,17 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
+	// Rotate the CSRF token along with the refresh token; the previous one
+	// stays valid if that fails
+	if err := middleware.IssueCSRFToken(c, h.transport, int(response.RefreshExpiresAt.Sub(time.Now()).Seconds())); err != nil {
+		log.Printf("[WARN] Failed to rotate CSRF token: %v", err)
+	}
+
 	// Set new access token as HTTP-only cookie
-	c.SetCookie("access_token", response.AccessToken, int(response.ExpiresAt.Sub(time.Now()).Seconds()), "/", "", false, true)
+	h.setCookie(c, "access_token", response.AccessToken, int(response.ExpiresAt.Sub(time.Now()).Seconds()))
 
 	// Set new refresh token as HTTP-only cookie
-	c.SetCookie("refresh_token", response.RefreshToken, int(response.RefreshExpiresAt.Sub(time.Now()).Seconds()), "/", "", false, true)
+	h.setCookie(c, "refresh_token", response.RefreshToken, int(response.RefreshExpiresAt.Sub(time.Now()).Seconds()))
 
 	c.JSON(http.StatusOK, response)
 }
@@ -181,20 +233,29 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param request body dto.LogoutRequest true "Logout request"
+// @Param request body dto.LogoutRequest false "Logout request; fields default to cookies"
 // @Success 200 {object} dto.SuccessResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// Read refresh token and session ID from cookies
-	refreshToken, _ := c.Cookie("refresh_token")
-	sessionID, _ := c.Cookie("session_id")
+	var req dto.LogoutRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		h.handleError(c, errors.NewValidationError("Invalid request format", err))
+		return
+	}
 
-	// Create logout request with data from cookies
-	req := dto.LogoutRequest{
-		RefreshToken: refreshToken,
-		SessionID:    sessionID,
+	// Fill in tokens and session ID from the header, body or cookies
+	var accessCookie, refreshCookie, sessionCookie bool
+	req.AccessToken, accessCookie = h.transport.PickCredential(c, "access_token", middleware.BearerToken(c))
+	req.RefreshToken, refreshCookie = h.transport.PickCredential(c, "refresh_token", req.RefreshToken)
+	req.SessionID, sessionCookie = h.transport.PickCredential(c, "session_id", req.SessionID)
+
+	// Cookies are sent with cross-site requests too, whatever the Authorization header
+	if (accessCookie || refreshCookie || sessionCookie) && !middleware.ValidCSRFToken(c, h.transport) {
+		h.handleError(c, errors.NewForbiddenError("Missing or invalid CSRF token", nil))
+		return
 	}
 
 	// Execute logout use case
@@ -205,14 +266,28 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	}
 
 	// Clear all authentication cookies
-	c.SetCookie("session_id", "", -1, "/", "", false, true)
-	c.SetCookie("access_token", "", -1, "/", "", false, true)
-	c.SetCookie("refresh_token", "", -1, "/", "", false, true)
+	h.setCookie(c, "session_id", "", -1)
+	h.setCookie(c, "access_token", "", -1)
+	h.setCookie(c, "refresh_token", "", -1)
+	h.transport.SetCookie(c, middleware.CSRFCookieName, "", -1, false)
 
 	// Return success response
 	c.JSON(http.StatusOK, response)
 }
 
+// setCookie sets an HTTP-only auth cookie unless the API runs in token-only mode
+func (h *AuthHandler) setCookie(c *gin.Context, name, value string, maxAge int) {
+	h.transport.SetCookie(c, name, value, maxAge, true)
+}
+
+// bindOptionalJSON binds a JSON body if the request has one
+func bindOptionalJSON(c *gin.Context, obj interface{}) error {
+	if err := c.ShouldBindJSON(obj); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
 // handleError handles errors and sends appropriate HTTP responses
 func (h *AuthHandler) handleError(c *gin.Context, err error) {
 	if customErr, ok := err.(*errors.CustomError); ok {
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"embed"
+	stderrors "errors"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/usecases"
+	"github.com/otp-auth/internal/infrastructure/http/middleware"
+	"github.com/otp-auth/pkg/errors"
+	"github.com/otp-auth/pkg/utils"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var authorizeTemplate = template.Must(template.ParseFS(templateFS, "templates/authorize.html"))
+
+const (
+	// formTokenCookieName binds the hosted login forms to the browser that opened
+	// them, so another site can't log a user in as someone else
+	formTokenCookieName = "oauth_form"
+	formTokenCookiePath = "/oauth/authorize"
+	formTokenMaxAge     = 1800 // seconds
+)
+
+// Steps of the hosted login page
+const (
+	authorizeStepPhone = "phone"
+	authorizeStepOTP   = "otp"
+	authorizeStepError = "error"
+)
+
+// authorizePage is the data of the hosted login page template
+type authorizePage struct {
+	Step        string
+	Request     *dto.AuthorizationRequest
+	FormToken   string
+	PhoneNumber string
+	SessionID   string
+	Error       string
+}
+
+// AuthorizationHandler serves the hosted login page of the OAuth 2.0 authorization
+// code flow: the user enters a phone number, then the OTP, and is sent back to the
+// client with an authorization code
+type AuthorizationHandler struct {
+	authorizeUseCase             *usecases.AuthorizeUseCase
+	sendOTPUseCase               *usecases.SendOTPUseCase
+	sendOTPChallengeUseCase      *usecases.SendOTPChallengeUseCase // optional
+	completeAuthorizationUseCase *usecases.CompleteAuthorizationUseCase
+	cookie                       middleware.CookieConfig
+}
+
+// NewAuthorizationHandler creates a new AuthorizationHandler; cookie supplies the
+// Secure and Domain attributes of the form cookie
+func NewAuthorizationHandler(
+	authorizeUseCase *usecases.AuthorizeUseCase,
+	sendOTPUseCase *usecases.SendOTPUseCase,
+	sendOTPChallengeUseCase *usecases.SendOTPChallengeUseCase,
+	completeAuthorizationUseCase *usecases.CompleteAuthorizationUseCase,
+	cookie middleware.CookieConfig,
+) *AuthorizationHandler {
+	return &AuthorizationHandler{
+		authorizeUseCase:             authorizeUseCase,
+		sendOTPUseCase:               sendOTPUseCase,
+		sendOTPChallengeUseCase:      sendOTPChallengeUseCase,
+		completeAuthorizationUseCase: completeAuthorizationUseCase,
+		cookie:                       cookie,
+	}
+}
+
+// Authorize handles the authorization request
+// @Summary Authorize
+// @Description Start the authorization code flow (RFC 6749 with PKCE S256). Renders the hosted login page, or redirects back to the client with an error.
+// @Tags oauth
+// @Produce html
+// @Param response_type query string true "code"
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string true "Registered redirect URI"
+// @Param scope query string false "openid, phone"
+// @Param state query string false "Returned to the client unchanged"
+// @Param nonce query string false "Copied into the ID token"
+// @Param code_challenge query string true "PKCE code challenge"
+// @Param code_challenge_method query string true "S256"
+// @Success 200 {string} string "Login page"
+// @Success 302 {string} string "Redirect to the client with an error"
+// @Failure 400 {string} string "Error page for an unknown client or redirect URI"
+// @Router /oauth/authorize [get]
+func (h *AuthorizationHandler) Authorize(c *gin.Context) {
+	var req dto.AuthorizationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.renderError(c, &req, errors.NewValidationError("Invalid authorization request", err))
+		return
+	}
+
+	if _, err := h.authorizeUseCase.Execute(c.Request.Context(), &req); err != nil {
+		h.renderError(c, &req, err)
+		return
+	}
+
+	formToken, err := utils.GenerateRandomString(32)
+	if err != nil {
+		h.renderError(c, &req, errors.NewInternalError("Failed to start sign-in", err))
+		return
+	}
+	h.setFormCookie(c, formToken, formTokenMaxAge)
+
+	h.render(c, http.StatusOK, &authorizePage{Step: authorizeStepPhone, Request: &req, FormToken: formToken})
+}
+
+// SendOTP handles the phone number form of the hosted login page
+// @Summary Authorize: send OTP
+// @Description Phone number step of the hosted login page; renders the OTP form
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Success 200 {string} string "OTP form"
+// @Failure 400 {string} string "Login page with an error"
+// @Router /oauth/authorize/send-otp [post]
+func (h *AuthorizationHandler) SendOTP(c *gin.Context) {
+	page, ok := h.bindForm(c)
+	if !ok {
+		return
+	}
+
+	sendReq := &dto.SendOTPRequest{PhoneNumber: page.PhoneNumber}
+	if err := sendReq.Validate(); err != nil {
+		h.renderStepError(c, page, errors.NewValidationError("Invalid phone number format", err))
+		return
+	}
+
+	// The page can't present challenges, so it sends nothing while one is required
+	if h.sendOTPChallengeUseCase != nil {
+		challenge, err := h.sendOTPChallengeUseCase.Execute(c.Request.Context(), c.ClientIP(), sendReq)
+		if err != nil {
+			h.renderStepError(c, page, err)
+			return
+		}
+		if challenge != nil {
+			h.renderStepError(c, page, errors.NewRateLimitError("Too many sign-in attempts, please try again later"))
+			return
+		}
+	}
+
+	response, err := h.sendOTPUseCase.Execute(c.Request.Context(), sendReq)
+	if err != nil {
+		h.renderStepError(c, page, err)
+		return
+	}
+
+	page.Step = authorizeStepOTP
+	page.SessionID = response.SessionID
+	h.render(c, http.StatusOK, page)
+}
+
+// Verify handles the OTP form of the hosted login page
+// @Summary Authorize: verify OTP
+// @Description OTP step of the hosted login page; redirects back to the client with an authorization code
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Success 302 {string} string "Redirect to the client with the code and state"
+// @Failure 401 {string} string "OTP form with an error"
+// @Router /oauth/authorize/verify [post]
+func (h *AuthorizationHandler) Verify(c *gin.Context) {
+	page, ok := h.bindForm(c)
+	if !ok {
+		return
+	}
+	page.Step = authorizeStepOTP
+	page.SessionID = c.PostForm("session_id")
+
+	loginReq := &dto.LoginRequest{
+		PhoneNumber:    page.PhoneNumber,
+		OTP:            c.PostForm("otp"),
+		UserAgent:      c.Request.UserAgent(),
+		IPAddress:      c.ClientIP(),
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+	}
+	redirectURI, err := h.completeAuthorizationUseCase.Execute(c.Request.Context(), page.Request, loginReq, page.SessionID)
+	if err != nil {
+		h.renderStepError(c, page, err)
+		return
+	}
+
+	h.setFormCookie(c, "", -1)
+	c.Redirect(http.StatusFound, redirectURI)
+}
+
+// bindForm reads a form of the hosted login page and checks it came from the page
+// this browser was shown and still carries a valid authorization request; on
+// failure the response is already written
+func (h *AuthorizationHandler) bindForm(c *gin.Context) (*authorizePage, bool) {
+	var req dto.AuthorizationRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.renderError(c, &req, errors.NewValidationError("Invalid authorization request", err))
+		return nil, false
+	}
+
+	formToken := c.PostForm("form_token")
+	cookie, _ := c.Cookie(formTokenCookieName)
+	if cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(formToken)) != 1 {
+		h.renderError(c, &req, errors.NewValidationError("The sign-in form expired, please start again from the application", nil))
+		return nil, false
+	}
+
+	if _, err := h.authorizeUseCase.Execute(c.Request.Context(), &req); err != nil {
+		h.renderError(c, &req, err)
+		return nil, false
+	}
+
+	return &authorizePage{
+		Step:        authorizeStepPhone,
+		Request:     &req,
+		FormToken:   formToken,
+		PhoneNumber: c.PostForm("phone_number"),
+	}, true
+}
+
+// setFormCookie sets or, with a negative maxAge, clears the form token cookie. It is
+// only sent to the hosted login page and never on cross-site requests.
+func (h *AuthorizationHandler) setFormCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(formTokenCookieName, value, maxAge, formTokenCookiePath, h.cookie.Domain, h.cookie.Secure, true)
+}
+
+// renderStepError shows err on the current step of the page so the user can try
+// again; internal errors end the flow instead
+func (h *AuthorizationHandler) renderStepError(c *gin.Context, page *authorizePage, err error) {
+	var authErr *usecases.AuthorizationError
+	customErr := errors.GetCustomError(err)
+	if stderrors.As(err, &authErr) || customErr == nil || customErr.Type == errors.InternalError {
+		h.renderError(c, page.Request, err)
+		return
+	}
+
+	page.Error = customErr.Message
+	if customErr.Type == errors.Unauthorized {
+		page.Error = "The code is wrong or has expired."
+	}
+	h.render(c, customErr.StatusCode, page)
+}
+
+// renderError ends the flow: errors the client should see are sent to its redirect
+// URI, anything else is shown on an error page
+func (h *AuthorizationHandler) renderError(c *gin.Context, req *dto.AuthorizationRequest, err error) {
+	var authErr *usecases.AuthorizationError
+	if stderrors.As(err, &authErr) {
+		params := url.Values{"error": {authErr.Code}, "error_description": {authErr.Description}}
+		if req.State != "" {
+			params.Set("state", req.State)
+		}
+		c.Redirect(http.StatusFound, usecases.AuthorizationRedirectURI(req.RedirectURI, params))
+		return
+	}
+
+	customErr := errors.GetCustomError(err)
+	if customErr == nil || customErr.Type == errors.InternalError {
+		log.Printf("[WARN] authorization request for client %q failed: %v", req.ClientID, err)
+		h.render(c, http.StatusInternalServerError, &authorizePage{Step: authorizeStepError, Request: req, Error: "Something went wrong, please try again later."})
+		return
+	}
+	h.render(c, customErr.StatusCode, &authorizePage{Step: authorizeStepError, Request: req, Error: customErr.Message})
+}
+
+// render writes the hosted login page; it must not be framed or cached
+func (h *AuthorizationHandler) render(c *gin.Context, status int, page *authorizePage) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	if err := authorizeTemplate.Execute(c.Writer, page); err != nil {
+		log.Printf("[WARN] failed to render authorization page: %v", err)
+	}
+}
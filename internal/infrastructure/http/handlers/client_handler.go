@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/usecases"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// ClientHandler handles client registry administration HTTP requests
+type ClientHandler struct {
+	listClientsUseCase  *usecases.ListClientsUseCase
+	createClientUseCase *usecases.CreateClientUseCase
+	updateClientUseCase *usecases.UpdateClientUseCase
+	deleteClientUseCase *usecases.DeleteClientUseCase
+}
+
+// NewClientHandler creates a new ClientHandler
+func NewClientHandler(listClientsUseCase *usecases.ListClientsUseCase, createClientUseCase *usecases.CreateClientUseCase, updateClientUseCase *usecases.UpdateClientUseCase, deleteClientUseCase *usecases.DeleteClientUseCase) *ClientHandler {
+	return &ClientHandler{
+		listClientsUseCase:  listClientsUseCase,
+		createClientUseCase: createClientUseCase,
+		updateClientUseCase: updateClientUseCase,
+		deleteClientUseCase: deleteClientUseCase,
+	}
+}
+
+// ListClients handles the list clients request (admin only)
+// @Summary List Clients
+// @Description List registered OAuth clients and their settings (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.ClientsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/clients [get]
+func (h *ClientHandler) ListClients(c *gin.Context) {
+	response, err := h.listClientsUseCase.Execute(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateClient handles the create client request (admin only)
+// @Summary Create Client
+// @Description Register an OAuth client. Confidential clients get a secret, returned only in this response (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateClientRequest true "Create client request"
+// @Security BearerAuth
+// @Success 201 {object} dto.ClientInfo
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/clients [post]
+func (h *ClientHandler) CreateClient(c *gin.Context) {
+	var req dto.CreateClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, errors.NewValidationError("Invalid request format", err))
+		return
+	}
+
+	response, err := h.createClientUseCase.Execute(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdateClient handles the update client request (admin only)
+// @Summary Update Client
+// @Description Replace the settings of an OAuth client, optionally issuing a new secret (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Client ID"
+// @Param request body dto.UpdateClientRequest true "Update client request"
+// @Security BearerAuth
+// @Success 200 {object} dto.ClientInfo
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/clients/{id} [put]
+func (h *ClientHandler) UpdateClient(c *gin.Context) {
+	var req dto.UpdateClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, errors.NewValidationError("Invalid request format", err))
+		return
+	}
+
+	response, err := h.updateClientUseCase.Execute(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteClient handles the delete client request (admin only)
+// @Summary Delete Client
+// @Description Remove an OAuth client; its sessions can no longer be refreshed (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "Client ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/clients/{id} [delete]
+func (h *ClientHandler) DeleteClient(c *gin.Context) {
+	response, err := h.deleteClientUseCase.Execute(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// handleError handles errors and sends appropriate HTTP responses
+func (h *ClientHandler) handleError(c *gin.Context, err error) {
+	if customErr, ok := err.(*errors.CustomError); ok {
+		c.JSON(customErr.StatusCode, dto.ErrorResponse{
+			Error:   customErr.Message,
+			Code:    string(customErr.Type),
+			Details: customErr.Details,
+		})
+		return
+	}
+
+	// Default to internal server error
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "An internal error occurred",
+		Code:    "INTERNAL_ERROR",
+		Details: err.Error(),
+	})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/usecases"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// DeviceHandler handles device administration HTTP requests
+type DeviceHandler struct {
+	listDevicesUseCase *usecases.ListDevicesUseCase
+}
+
+// NewDeviceHandler creates a new DeviceHandler
+func NewDeviceHandler(listDevicesUseCase *usecases.ListDevicesUseCase) *DeviceHandler {
+	return &DeviceHandler{
+		listDevicesUseCase: listDevicesUseCase,
+	}
+}
+
+// ListDevices handles the list devices request (admin only)
+// @Summary List Devices
+// @Description List the devices users have logged in from, most recently seen first (admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param user_id query string false "Only devices of this user"
+// @Security BearerAuth
+// @Success 200 {object} dto.DevicesResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/devices [get]
+func (h *DeviceHandler) ListDevices(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	req := &dto.ListDevicesRequest{
+		Page:   page,
+		Limit:  limit,
+		UserID: c.Query("user_id"),
+	}
+
+	response, err := h.listDevicesUseCase.Execute(c.Request.Context(), req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// handleError handles errors and sends appropriate HTTP responses
+func (h *DeviceHandler) handleError(c *gin.Context, err error) {
+	if customErr, ok := err.(*errors.CustomError); ok {
+		c.JSON(customErr.StatusCode, dto.ErrorResponse{
+			Error:   customErr.Message,
+			Code:    string(customErr.Type),
+			Details: customErr.Details,
+		})
+		return
+	}
+
+	// Default to internal server error
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "An internal error occurred",
+		Code:    "INTERNAL_ERROR",
+		Details: err.Error(),
+	})
+}
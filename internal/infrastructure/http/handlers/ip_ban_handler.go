@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/usecases"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// IPBanHandler handles IP ban administration HTTP requests
+type IPBanHandler struct {
+	listIPBansUseCase  *usecases.ListIPBansUseCase
+	addIPBanUseCase    *usecases.AddIPBanUseCase
+	removeIPBanUseCase *usecases.RemoveIPBanUseCase
+}
+
+// NewIPBanHandler creates a new IPBanHandler
+func NewIPBanHandler(listIPBansUseCase *usecases.ListIPBansUseCase, addIPBanUseCase *usecases.AddIPBanUseCase, removeIPBanUseCase *usecases.RemoveIPBanUseCase) *IPBanHandler {
+	return &IPBanHandler{
+		listIPBansUseCase:  listIPBansUseCase,
+		addIPBanUseCase:    addIPBanUseCase,
+		removeIPBanUseCase: removeIPBanUseCase,
+	}
+}
+
+// ListBans handles the list IP bans request (admin only)
+// @Summary List IP Bans
+// @Description List active IP and CIDR bans (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.IPBansResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/ip-bans [get]
+func (h *IPBanHandler) ListBans(c *gin.Context) {
+	response, err := h.listIPBansUseCase.Execute(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AddBan handles the add IP ban request (admin only)
+// @Summary Add IP Ban
+// @Description Ban an IP address or CIDR block, optionally for a limited duration (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body dto.AddIPBanRequest true "Add IP ban request"
+// @Security BearerAuth
+// @Success 201 {object} dto.IPBanInfo
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/ip-bans [post]
+func (h *IPBanHandler) AddBan(c *gin.Context) {
+	var req dto.AddIPBanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, errors.NewValidationError("Invalid request format", err))
+		return
+	}
+
+	response, err := h.addIPBanUseCase.Execute(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// RemoveBan handles the remove IP ban request (admin only)
+// @Summary Remove IP Ban
+// @Description Lift the ban on an IP address or CIDR block (admin only)
+// @Tags admin
+// @Produce json
+// @Param cidr query string true "Banned IP address or CIDR block"
+// @Security BearerAuth
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/ip-bans [delete]
+func (h *IPBanHandler) RemoveBan(c *gin.Context) {
+	cidr := c.Query("cidr")
+	if cidr == "" {
+		h.handleError(c, errors.NewValidationError("cidr query parameter is required", nil))
+		return
+	}
+
+	response, err := h.removeIPBanUseCase.Execute(c.Request.Context(), cidr)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// handleError handles errors and sends appropriate HTTP responses
+func (h *IPBanHandler) handleError(c *gin.Context, err error) {
+	if customErr, ok := err.(*errors.CustomError); ok {
+		c.JSON(customErr.StatusCode, dto.ErrorResponse{
+			Error:   customErr.Message,
+			Code:    string(customErr.Type),
+			Details: customErr.Details,
+		})
+		return
+	}
+
+	// Default to internal server error
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "An internal error occurred",
+		Code:    "INTERNAL_ERROR",
+		Details: err.Error(),
+	})
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/services"
+)
+
+// JWKSHandler serves the public signing keys so other services can verify tokens locally
+type JWKSHandler struct {
+	keySetProvider services.KeySetProvider
+}
+
+// NewJWKSHandler creates a new JWKSHandler
+func NewJWKSHandler(keySetProvider services.KeySetProvider) *JWKSHandler {
+	return &JWKSHandler{
+		keySetProvider: keySetProvider,
+	}
+}
+
+// GetKeySet handles the JWKS request
+// @Summary JSON Web Key Set
+// @Description Public keys for verifying access tokens, selected by the token's kid header
+// @Tags well-known
+// @Produce json
+// @Success 200 {object} services.JSONWebKeySet
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetKeySet(c *gin.Context) {
+	keySet, err := h.keySetProvider.KeySet()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: "Failed to load signing keys",
+			Code:  "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	// Verifiers cache the set and refetch when they see an unknown kid
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, keySet)
+}
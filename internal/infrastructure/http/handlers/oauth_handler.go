@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/usecases"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// OAuthHandler handles the OAuth 2.0 endpoints used by other services
+type OAuthHandler struct {
+	introspectTokenUseCase           *usecases.IntrospectTokenUseCase
+	revokeTokenUseCase               *usecases.RevokeTokenUseCase
+	exchangeAuthorizationCodeUseCase *usecases.ExchangeAuthorizationCodeUseCase
+	exchangeRefreshTokenUseCase      *usecases.ExchangeRefreshTokenUseCase
+	issueClientTokenUseCase          *usecases.IssueClientTokenUseCase
+}
+
+// NewOAuthHandler creates a new OAuthHandler
+func NewOAuthHandler(introspectTokenUseCase *usecases.IntrospectTokenUseCase, revokeTokenUseCase *usecases.RevokeTokenUseCase, exchangeAuthorizationCodeUseCase *usecases.ExchangeAuthorizationCodeUseCase, exchangeRefreshTokenUseCase *usecases.ExchangeRefreshTokenUseCase, issueClientTokenUseCase *usecases.IssueClientTokenUseCase) *OAuthHandler {
+	return &OAuthHandler{
+		introspectTokenUseCase:           introspectTokenUseCase,
+		revokeTokenUseCase:               revokeTokenUseCase,
+		exchangeAuthorizationCodeUseCase: exchangeAuthorizationCodeUseCase,
+		exchangeRefreshTokenUseCase:      exchangeRefreshTokenUseCase,
+		issueClientTokenUseCase:          issueClientTokenUseCase,
+	}
+}
+
+// Token handles the token request
+// @Summary Token
+// @Description Exchange an authorization code for tokens (RFC 6749 section 4.1.3) with the PKCE code_verifier, rotate a refresh token issued to the client (section 6), or get an access token for the client itself with the client_credentials grant (section 4.4). Confidential clients authenticate with HTTP Basic or client_id and client_secret form fields; public clients send only client_id and can't use client_credentials.
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "authorization_code, refresh_token or client_credentials"
+// @Param code formData string false "Authorization code (authorization_code)"
+// @Param redirect_uri formData string false "Redirect URI of the authorization request (authorization_code)"
+// @Param code_verifier formData string false "PKCE code verifier (authorization_code)"
+// @Param refresh_token formData string false "Refresh token (refresh_token)"
+// @Param scope formData string false "Space separated scopes (client_credentials); defaults to all the client is allowed"
+// @Success 200 {object} dto.OAuthTokenResponse
+// @Failure 400 {object} dto.OAuthErrorResponse
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
+// @Router /oauth/token [post]
+func (h *OAuthHandler) Token(c *gin.Context) {
+	var req dto.OAuthTokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.handleError(c, errors.NewValidationError("Invalid request format", err))
+		return
+	}
+	if err := h.bindClientCredentials(c, &req.ClientID, &req.ClientSecret); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	var response *dto.OAuthTokenResponse
+	var err error
+	switch req.GrantType {
+	case usecases.GrantTypeAuthorizationCode:
+		response, err = h.exchangeAuthorizationCodeUseCase.Execute(c.Request.Context(), &req)
+	case usecases.GrantTypeRefreshToken:
+		response, err = h.exchangeRefreshTokenUseCase.Execute(c.Request.Context(), &req)
+	case usecases.GrantTypeClientCredentials:
+		response, err = h.issueClientTokenUseCase.Execute(c.Request.Context(), &req)
+	default:
+		h.writeError(c, http.StatusBadRequest, "unsupported_grant_type", "Unsupported grant type")
+		return
+	}
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(http.StatusOK, response)
+}
+
+// Introspect handles the token introspection request
+// @Summary Introspect Token
+// @Description Report whether an access or refresh token is active (RFC 7662). Clients authenticate with HTTP Basic or client_id and client_secret form fields.
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Access or refresh token"
+// @Param token_type_hint formData string false "access_token or refresh_token"
+// @Success 200 {object} dto.IntrospectTokenResponse
+// @Failure 400 {object} dto.OAuthErrorResponse
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
+// @Router /oauth/introspect [post]
+func (h *OAuthHandler) Introspect(c *gin.Context) {
+	var req dto.IntrospectTokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.handleError(c, errors.NewValidationError("Invalid request format", err))
+		return
+	}
+	if err := h.bindClientCredentials(c, &req.ClientID, &req.ClientSecret); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response, err := h.introspectTokenUseCase.Execute(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
+
+// Revoke handles the token revocation request
+// @Summary Revoke Token
+// @Description Revoke an access or refresh token issued to the calling client (RFC 7009). Unknown, invalid and already revoked tokens are accepted as well.
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Param token formData string true "Access or refresh token"
+// @Param token_type_hint formData string false "access_token or refresh_token"
+// @Success 200
+// @Failure 400 {object} dto.OAuthErrorResponse
+// @Failure 401 {object} dto.OAuthErrorResponse
+// @Failure 500 {object} dto.OAuthErrorResponse
+// @Router /oauth/revoke [post]
+func (h *OAuthHandler) Revoke(c *gin.Context) {
+	var req dto.RevokeTokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.handleError(c, errors.NewValidationError("Invalid request format", err))
+		return
+	}
+	if err := h.bindClientCredentials(c, &req.ClientID, &req.ClientSecret); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if err := h.revokeTokenUseCase.Execute(c.Request.Context(), &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// bindClientCredentials takes the client's credentials from HTTP Basic
+// authentication when present; RFC 6749 form-encodes both parts
+func (h *OAuthHandler) bindClientCredentials(c *gin.Context, clientID, clientSecret *string) error {
+	username, password, ok := c.Request.BasicAuth()
+	if !ok {
+		return nil
+	}
+
+	id, err := url.QueryUnescape(username)
+	if err != nil {
+		return errors.NewUnauthorizedError("Invalid client credentials", err)
+	}
+	secret, err := url.QueryUnescape(password)
+	if err != nil {
+		return errors.NewUnauthorizedError("Invalid client credentials", err)
+	}
+
+	*clientID, *clientSecret = id, secret
+	return nil
+}
+
+// handleError sends errors in the RFC 6749 format
+func (h *OAuthHandler) handleError(c *gin.Context, err error) {
+	var authErr *usecases.AuthorizationError
+	if stderrors.As(err, &authErr) {
+		h.writeError(c, http.StatusBadRequest, authErr.Code, authErr.Description)
+		return
+	}
+
+	customErr := errors.GetCustomError(err)
+	if customErr == nil {
+		customErr = errors.NewInternalError("An internal error occurred", err)
+	}
+
+	status, code := customErr.StatusCode, "server_error"
+	switch customErr.Type {
+	case errors.ValidationError:
+		code = "invalid_request"
+	case errors.Unauthorized:
+		code = "invalid_client"
+		c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+	case errors.Forbidden:
+		// The client is known but the grant it presented is not valid
+		status, code = http.StatusBadRequest, "invalid_grant"
+	case errors.ConflictError:
+		// The user is over the session limit, so the code can't start a session
+		status, code = http.StatusBadRequest, "invalid_grant"
+	}
+
+	h.writeError(c, status, code, customErr.Message)
+}
+
+// writeError sends an RFC 6749 error response
+func (h *OAuthHandler) writeError(c *gin.Context, status int, code, description string) {
+	c.JSON(status, dto.OAuthErrorResponse{
+		Error:            code,
+		ErrorDescription: description,
+	})
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/usecases"
+	"github.com/otp-auth/internal/infrastructure/http/middleware"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// OIDCHandler handles the OpenID Connect discovery and UserInfo endpoints
+type OIDCHandler struct {
+	configuration      *dto.OpenIDConfiguration
+	getUserInfoUseCase *usecases.GetOIDCUserInfoUseCase
+}
+
+// NewOIDCHandler creates a new OIDCHandler
+func NewOIDCHandler(configuration *dto.OpenIDConfiguration, getUserInfoUseCase *usecases.GetOIDCUserInfoUseCase) *OIDCHandler {
+	return &OIDCHandler{
+		configuration:      configuration,
+		getUserInfoUseCase: getUserInfoUseCase,
+	}
+}
+
+// Discovery handles the OpenID Provider configuration request
+// @Summary OpenID Connect Discovery
+// @Description OpenID Provider metadata for relying parties
+// @Tags well-known
+// @Produce json
+// @Success 200 {object} dto.OpenIDConfiguration
+// @Router /.well-known/openid-configuration [get]
+func (h *OIDCHandler) Discovery(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, h.configuration)
+}
+
+// UserInfo handles the OpenID Connect UserInfo request
+// @Summary UserInfo
+// @Description Claims about the user the access token was issued to
+// @Tags oidc
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.OIDCUserInfoResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /userinfo [get]
+// @Router /userinfo [post]
+func (h *OIDCHandler) UserInfo(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		h.handleError(c, errors.NewUnauthorizedError("User ID not found in context", nil))
+		return
+	}
+
+	response, err := h.getUserInfoUseCase.Execute(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, response)
+}
+
+// handleError handles errors and sends appropriate HTTP responses
+func (h *OIDCHandler) handleError(c *gin.Context, err error) {
+	if customErr, ok := err.(*errors.CustomError); ok {
+		// A valid token of a deleted user no longer identifies anyone
+		if customErr.Type == errors.NotFoundError {
+			customErr = errors.NewUnauthorizedError("User not found", err)
+		}
+		c.JSON(customErr.StatusCode, dto.ErrorResponse{
+			Error:   customErr.Message,
+			Code:    string(customErr.Type),
+			Details: customErr.Details,
+		})
+		return
+	}
+
+	// Default to internal server error
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "An internal error occurred",
+		Code:    "INTERNAL_ERROR",
+		Details: err.Error(),
+	})
+}
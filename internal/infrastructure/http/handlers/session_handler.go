@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/usecases"
+	"github.com/otp-auth/internal/infrastructure/http/middleware"
+	"github.com/otp-auth/pkg/errors"
+)
+
+var revokeSessionTemplate = template.Must(template.ParseFS(templateFS, "templates/revoke_session.html"))
+
+// revokeSessionPage is the data of the revoke link's page template
+type revokeSessionPage struct {
+	Token   string // set while asking for confirmation
+	Message string // result of the revocation
+	Error   string
+}
+
+// SessionHandler handles the current user's session management requests
+type SessionHandler struct {
+	listUserSessionsUseCase        *usecases.ListUserSessionsUseCase
+	revokeUserSessionUseCase       *usecases.RevokeUserSessionUseCase
+	revokeOtherUserSessionsUseCase *usecases.RevokeOtherUserSessionsUseCase
+	revokeSessionByLinkUseCase     *usecases.RevokeSessionByLinkUseCase
+	transport                      middleware.TokenTransport
+}
+
+// NewSessionHandler creates a new SessionHandler
+func NewSessionHandler(listUserSessionsUseCase *usecases.ListUserSessionsUseCase, revokeUserSessionUseCase *usecases.RevokeUserSessionUseCase, revokeOtherUserSessionsUseCase *usecases.RevokeOtherUserSessionsUseCase, revokeSessionByLinkUseCase *usecases.RevokeSessionByLinkUseCase, transport middleware.TokenTransport) *SessionHandler {
+	return &SessionHandler{
+		listUserSessionsUseCase:        listUserSessionsUseCase,
+		revokeUserSessionUseCase:       revokeUserSessionUseCase,
+		revokeOtherUserSessionsUseCase: revokeOtherUserSessionsUseCase,
+		revokeSessionByLinkUseCase:     revokeSessionByLinkUseCase,
+		transport:                      transport,
+	}
+}
+
+// ListSessions handles the list sessions request
+// @Summary List Sessions
+// @Description List the current user's active sessions, most recently used first
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.SessionsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		h.handleError(c, errors.NewUnauthorizedError("User ID not found in context", nil))
+		return
+	}
+
+	// The session header or cookie identifies the caller's own session, if any
+	currentSessionID := h.transport.Pick(c, "session_id", c.GetHeader("X-Session-ID"))
+
+	response, err := h.listUserSessionsUseCase.Execute(c.Request.Context(), userID, currentSessionID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeSession handles the revoke session request
+// @Summary Revoke Session
+// @Description Log out one of the current user's sessions
+// @Tags users
+// @Produce json
+// @Param id path string true "Session ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		h.handleError(c, errors.NewUnauthorizedError("User ID not found in context", nil))
+		return
+	}
+
+	response, err := h.revokeUserSessionUseCase.Execute(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeOtherSessions handles the revoke other sessions request
+// @Summary Revoke Other Sessions
+// @Description Log out every session of the current user except the one making the request
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/me/sessions/revoke-others [post]
+func (h *SessionHandler) RevokeOtherSessions(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		h.handleError(c, errors.NewUnauthorizedError("User ID not found in context", nil))
+		return
+	}
+
+	currentSessionID := h.transport.Pick(c, "session_id", c.GetHeader("X-Session-ID"))
+
+	response, err := h.revokeOtherUserSessionsUseCase.Execute(c.Request.Context(), userID, currentSessionID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ConfirmRevokeSessionByLink handles the revoke link of a new-login notification
+// @Summary Confirm Revoke Session By Link
+// @Description Page asking the user to confirm ending the session a new-login notification was sent for. It changes nothing, so link previews can't end the session.
+// @Tags auth
+// @Produce html
+// @Param token query string true "Token from the notification link"
+// @Success 200 {string} string "Confirmation page"
+// @Router /auth/revoke-session [get]
+func (h *SessionHandler) ConfirmRevokeSessionByLink(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		h.renderRevokeSession(c, http.StatusBadRequest, &revokeSessionPage{Error: "The link is incomplete."})
+		return
+	}
+
+	h.renderRevokeSession(c, http.StatusOK, &revokeSessionPage{Token: token})
+}
+
+// RevokeSessionByLink handles the confirmation of the revoke link page
+// @Summary Revoke Session By Link
+// @Description End the session a new-login notification was sent for. The token is single use.
+// @Tags auth
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param token formData string true "Token from the notification link"
+// @Success 200 {string} string "Result page"
+// @Failure 400 {string} string "Error page"
+// @Failure 410 {string} string "Error page"
+// @Failure 500 {string} string "Error page"
+// @Router /auth/revoke-session [post]
+func (h *SessionHandler) RevokeSessionByLink(c *gin.Context) {
+	response, err := h.revokeSessionByLinkUseCase.Execute(c.Request.Context(), c.PostForm("token"))
+	if err != nil {
+		customErr := errors.GetCustomError(err)
+		if customErr == nil || customErr.Type == errors.InternalError {
+			log.Printf("[WARN] failed to revoke session by link: %v", err)
+			h.renderRevokeSession(c, http.StatusInternalServerError, &revokeSessionPage{Error: "Something went wrong, please try again later."})
+			return
+		}
+		h.renderRevokeSession(c, customErr.StatusCode, &revokeSessionPage{Error: customErr.Message})
+		return
+	}
+
+	h.renderRevokeSession(c, http.StatusOK, &revokeSessionPage{Message: response.Message})
+}
+
+// renderRevokeSession sends the revoke link's page
+func (h *SessionHandler) renderRevokeSession(c *gin.Context, status int, page *revokeSessionPage) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; form-action 'self'; frame-ancestors 'none'")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	if err := revokeSessionTemplate.Execute(c.Writer, page); err != nil {
+		log.Printf("[WARN] failed to render revoke session page: %v", err)
+	}
+}
+
+// handleError handles errors and sends appropriate HTTP responses
+func (h *SessionHandler) handleError(c *gin.Context, err error) {
+	if customErr, ok := err.(*errors.CustomError); ok {
+		c.JSON(customErr.StatusCode, dto.ErrorResponse{
+			Error:   customErr.Message,
+			Code:    string(customErr.Type),
+			Details: customErr.Details,
+		})
+		return
+	}
+
+	// Default to internal server error
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "An internal error occurred",
+		Code:    "INTERNAL_ERROR",
+		Details: err.Error(),
+	})
+}
@@ -13,15 +13,17 @@ import (
 
 // UserHandler handles user-related HTTP requests
 type UserHandler struct {
-	getUserProfileUseCase *usecases.GetUserProfileUseCase
-	getUsersListUseCase   *usecases.GetUsersListUseCase
+	getUserProfileUseCase   *usecases.GetUserProfileUseCase
+	getUsersListUseCase     *usecases.GetUsersListUseCase
+	revokeUserTokensUseCase *usecases.RevokeUserTokensUseCase
 }
 
 // NewUserHandler creates a new UserHandler
-func NewUserHandler(getUserProfileUseCase *usecases.GetUserProfileUseCase, getUsersListUseCase *usecases.GetUsersListUseCase) *UserHandler {
+func NewUserHandler(getUserProfileUseCase *usecases.GetUserProfileUseCase, getUsersListUseCase *usecases.GetUsersListUseCase, revokeUserTokensUseCase *usecases.RevokeUserTokensUseCase) *UserHandler {
 	return &UserHandler{
-		getUserProfileUseCase: getUserProfileUseCase,
-		getUsersListUseCase:   getUsersListUseCase,
+		getUserProfileUseCase:   getUserProfileUseCase,
+		getUsersListUseCase:     getUsersListUseCase,
+		revokeUserTokensUseCase: revokeUserTokensUseCase,
 	}
 }
 
@@ -103,6 +105,35 @@ func (h *UserHandler) UpdateUserScope(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"message": "Update user scope endpoint not implemented yet"})
 }
 
+// RevokeTokens handles the revoke user tokens request (admin only)
+// @Summary Revoke User Tokens
+// @Description Revoke all refresh tokens of a user and reject every access token issued to them so far (admin only)
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Security BearerAuth
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/users/{id}/revoke-tokens [post]
+func (h *UserHandler) RevokeTokens(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		h.handleError(c, errors.NewValidationError("User ID is required", nil))
+		return
+	}
+
+	response, err := h.revokeUserTokensUseCase.Execute(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
 // GetProfile handles the get user profile request
 // @Summary Get User Profile
 // @Description Get current user profile
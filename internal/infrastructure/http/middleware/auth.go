@@ -1,26 +1,135 @@
 package middleware
 
 import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
 	"github.com/otp-auth/internal/application/ports/services"
-	"net/http"
 )
 
+// TokenPrecedence selects which credential is used when a request carries both
+// an Authorization header and a cookie
+type TokenPrecedence string
+
+const (
+	TokenPrecedenceHeader TokenPrecedence = "header"
+	TokenPrecedenceCookie TokenPrecedence = "cookie"
+)
+
+// CookieConfig holds the attributes of the cookies the API sets
+type CookieConfig struct {
+	Secure   bool
+	SameSite http.SameSite
+	Domain   string
+	Path     string
+}
+
+// TokenTransport controls how tokens travel between clients and the API
+type TokenTransport struct {
+	Precedence TokenPrecedence
+	// TokenOnly returns tokens in response bodies only; auth cookies are
+	// neither set nor read
+	TokenOnly bool
+	Cookie    CookieConfig
+	// CSRF requires a double-submit token on state-changing requests
+	// authenticated by cookies
+	CSRF bool
+}
+
+// DefaultTokenTransport accepts both headers and cookies, preferring the header
+func DefaultTokenTransport() TokenTransport {
+	return TokenTransport{
+		Precedence: TokenPrecedenceHeader,
+		Cookie:     CookieConfig{SameSite: http.SameSiteLaxMode, Path: "/"},
+		CSRF:       true,
+	}
+}
+
+// ParseSameSite converts a configured SameSite name (lax, strict, none)
+func ParseSameSite(name string) (http.SameSite, bool) {
+	switch strings.ToLower(name) {
+	case "lax":
+		return http.SameSiteLaxMode, true
+	case "strict":
+		return http.SameSiteStrictMode, true
+	case "none":
+		return http.SameSiteNoneMode, true
+	}
+	return http.SameSiteDefaultMode, false
+}
+
+// SetCookie sets a cookie with the configured attributes; it does nothing in
+// token-only mode. A negative maxAge deletes the cookie.
+func (t TokenTransport) SetCookie(c *gin.Context, name, value string, maxAge int, httpOnly bool) {
+	if t.TokenOnly {
+		return
+	}
+	c.SetSameSite(t.Cookie.SameSite)
+	c.SetCookie(name, value, maxAge, t.Cookie.Path, t.Cookie.Domain, t.Cookie.Secure, httpOnly)
+}
+
+// Pick returns the credential to use out of one sent explicitly (header or
+// body) and the named cookie
+func (t TokenTransport) Pick(c *gin.Context, cookieName, explicit string) string {
+	credential, _ := t.PickCredential(c, cookieName, explicit)
+	return credential
+}
+
+// PickCredential is Pick that also reports whether the credential came from
+// the cookie, in which case the request may have been forged by another site
+func (t TokenTransport) PickCredential(c *gin.Context, cookieName, explicit string) (string, bool) {
+	cookie := ""
+	if !t.TokenOnly {
+		cookie, _ = c.Cookie(cookieName)
+	}
+	if t.Precedence == TokenPrecedenceCookie && cookie != "" {
+		return cookie, true
+	}
+	if explicit != "" {
+		return explicit, false
+	}
+	return cookie, cookie != ""
+}
+
 // AuthMiddleware handles JWT authentication
 type AuthMiddleware struct {
-	jwtService services.JWTService
+	jwtService    services.JWTService
+	denylist      repositories.AccessTokenDenylist // optional
+	failClosed    bool                             // reject tokens while the denylist is unavailable
+	transport     TokenTransport
+	verifyOptions []services.VerifyOption
 }
 
-// NewAuthMiddleware creates a new AuthMiddleware
-func NewAuthMiddleware(jwtService services.JWTService) *AuthMiddleware {
+// NewAuthMiddleware creates a new AuthMiddleware; verifyOptions typically pin
+// the expected issuer and this API's audience
+func NewAuthMiddleware(jwtService services.JWTService, denylist repositories.AccessTokenDenylist, failClosed bool, transport TokenTransport, verifyOptions ...services.VerifyOption) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtService: jwtService,
+		jwtService:    jwtService,
+		denylist:      denylist,
+		failClosed:    failClosed,
+		transport:     transport,
+		verifyOptions: verifyOptions,
 	}
 }
 
 // RequireAuth middleware that requires valid JWT token
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
+	return m.requireUser(false)
+}
+
+// RequireAdmin middleware that requires admin scope. A revoked admin token must
+// never work, so admin routes fail closed whatever the configuration.
+func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
+	return m.requireUser(true)
+}
+
+// requireUser requires a valid, unrevoked user token, with admin scope if admin
+func (m *AuthMiddleware) requireUser(admin bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := m.extractToken(c)
 		if token == "" {
@@ -28,7 +137,7 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := m.jwtService.VerifyToken(token)
+		claims, err := m.jwtService.VerifyToken(token, m.verifyOptions...)
 		if err != nil {
 			m.unauthorizedResponse(c, "Invalid or expired token")
 			return
@@ -40,61 +149,45 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			return
 		}
 
-		// Set user information in context
-		c.Set("user_id", claims.Subject)
-		c.Set("client_id", claims.ClientID)
-		c.Set("scopes", claims.Scopes)
-		c.Set("claims", claims)
-
-		c.Next()
-	}
-}
-
-// RequireAdmin middleware that requires admin scope
-func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		token := m.extractToken(c)
-		if token == "" {
-			m.unauthorizedResponse(c, "Authorization token required")
+		// Check if token was revoked before its expiry
+		denied, err := m.isDenied(c, claims)
+		if err != nil && (admin || m.failClosed) {
+			serviceUnavailableResponse(c)
 			return
 		}
-
-		claims, err := m.jwtService.VerifyToken(token)
-		if err != nil {
-			m.unauthorizedResponse(c, "Invalid or expired token")
+		if denied {
+			m.unauthorizedResponse(c, "Token has been revoked")
 			return
 		}
 
-		// Check if token is expired
-		if claims.IsExpired() {
-			m.unauthorizedResponse(c, "Token has expired")
+		// Client tokens are for service endpoints, and a client's own scopes
+		// never make it an admin
+		if claims.IsClient() {
+			m.forbiddenResponse(c, "User token required")
 			return
 		}
 
-		// Check if user has admin scope
-		hasAdminScope := false
-		for _, scope := range claims.Scopes {
-			if scope == "admin" || scope == "superadmin" {
-				hasAdminScope = true
-				break
-			}
-		}
-
-		if !hasAdminScope {
+		if admin && !hasAdminScope(claims.Scopes) {
 			m.forbiddenResponse(c, "Admin access required")
 			return
 		}
 
-		// Set user information in context
-		c.Set("user_id", claims.Subject)
-		c.Set("client_id", claims.ClientID)
-		c.Set("scopes", claims.Scopes)
-		c.Set("claims", claims)
+		setPrincipal(c, claims)
 
 		c.Next()
 	}
 }
 
+// hasAdminScope reports whether scopes include admin or superadmin
+func hasAdminScope(scopes []string) bool {
+	for _, scope := range scopes {
+		if scope == "admin" || scope == "superadmin" {
+			return true
+		}
+	}
+	return false
+}
+
 // OptionalAuth middleware that optionally validates JWT token
 func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -104,27 +197,63 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 			return
 		}
 
-		claims, err := m.jwtService.VerifyToken(token)
+		claims, err := m.jwtService.VerifyToken(token, m.verifyOptions...)
 		if err != nil {
 			// Invalid token, but continue without authentication
 			c.Next()
 			return
 		}
+		if denied, err := m.isDenied(c, claims); denied || (err != nil && m.failClosed) {
+			c.Next()
+			return
+		}
 
-		// Set user information in context
-		c.Set("user_id", claims.Subject)
-		c.Set("client_id", claims.ClientID)
-		c.Set("scopes", claims.Scopes)
-		c.Set("claims", claims)
+		setPrincipal(c, claims)
 
 		c.Next()
 	}
 }
 
-// extractToken extracts JWT token from access_token cookie
+// setPrincipal sets the token's principal in the context; user_id is only set
+// for users, so handlers can't mistake a client for one
+func setPrincipal(c *gin.Context, claims *services.JWTClaims) {
+	if !claims.IsClient() {
+		c.Set("user_id", claims.Subject)
+	}
+	c.Set("client_id", claims.ClientID)
+	c.Set("scopes", claims.Scopes)
+	c.Set("claims", claims)
+}
+
+// isDenied checks the denylist. It returns an error if the denylist can't be
+// reached; callers failing open then accept the token, since it has already
+// passed signature and expiry checks.
+func (m *AuthMiddleware) isDenied(c *gin.Context, claims *services.JWTClaims) (bool, error) {
+	if m.denylist == nil {
+		return false, nil
+	}
+
+	denied, err := m.denylist.IsDenied(c.Request.Context(), claims.TokenID, claims.Subject, claims.SessionID, time.Unix(claims.IssuedAt, 0))
+	if err != nil {
+		log.Printf("[WARN] Access token denylist unavailable: %v", err)
+		return false, err
+	}
+	return denied, nil
+}
+
+// extractToken extracts JWT token from the Authorization header or the
+// access_token cookie
 func (m *AuthMiddleware) extractToken(c *gin.Context) string {
-	accessToken, _ := c.Cookie("access_token")
-	return accessToken
+	return m.transport.Pick(c, "access_token", BearerToken(c))
+}
+
+// BearerToken returns the token of an "Authorization: Bearer" header, if any
+func BearerToken(c *gin.Context) string {
+	scheme, token, found := strings.Cut(strings.TrimSpace(c.GetHeader("Authorization")), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
 }
 
 // unauthorizedResponse sends an unauthorized response
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+)
+
+// staticJWTService accepts any token, returning the same claims
+type staticJWTService struct {
+	services.JWTService
+	claims *services.JWTClaims
+}
+
+func (s staticJWTService) VerifyToken(token string, opts ...services.VerifyOption) (*services.JWTClaims, error) {
+	copied := *s.claims
+	return &copied, nil
+}
+
+// unavailableDenylist fails every check, as if Redis were down
+type unavailableDenylist struct {
+	repositories.AccessTokenDenylist
+}
+
+func (unavailableDenylist) IsDenied(ctx context.Context, tokenID, userID, sessionID string, issuedAt time.Time) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestTokenTransportPick(t *testing.T) {
+	tests := []struct {
+		name       string
+		precedence TokenPrecedence
+		tokenOnly  bool
+		explicit   string
+		cookie     string
+		want       string
+		wantCookie bool
+	}{
+		{"explicit over cookie", TokenPrecedenceHeader, false, "body", "jar", "body", false},
+		{"cookie fallback", TokenPrecedenceHeader, false, "", "jar", "jar", true},
+		{"cookie precedence", TokenPrecedenceCookie, false, "body", "jar", "jar", true},
+		{"cookie precedence without cookie", TokenPrecedenceCookie, false, "body", "", "body", false},
+		{"token only ignores cookie", TokenPrecedenceCookie, true, "", "jar", "", false},
+		{"nothing", TokenPrecedenceHeader, false, "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := DefaultTokenTransport()
+			transport.Precedence = tt.precedence
+			transport.TokenOnly = tt.tokenOnly
+
+			cookies := map[string]string{}
+			if tt.cookie != "" {
+				cookies["refresh_token"] = tt.cookie
+			}
+			c, _ := newTestContext(http.MethodPost, nil, cookies)
+
+			got, fromCookie := transport.PickCredential(c, "refresh_token", tt.explicit)
+			if got != tt.want || fromCookie != tt.wantCookie {
+				t.Errorf("PickCredential = %q, %v, want %q, %v", got, fromCookie, tt.want, tt.wantCookie)
+			}
+			if picked := transport.Pick(c, "refresh_token", tt.explicit); picked != tt.want {
+				t.Errorf("Pick = %q, want %q", picked, tt.want)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"Bearer abc", "abc"},
+		{"bearer abc", "abc"},
+		{"  Bearer   abc  ", "abc"},
+		{"Basic abc", ""},
+		{"Bearer", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		c, _ := newTestContext(http.MethodGet, map[string]string{"Authorization": tt.header}, nil)
+		if got := BearerToken(c); got != tt.want {
+			t.Errorf("BearerToken(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestAuthMiddlewareDenylistUnavailable(t *testing.T) {
+	now := time.Now()
+	jwtService := staticJWTService{claims: &services.JWTClaims{
+		Subject:   "user-1",
+		Scopes:    []string{"admin"},
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Minute).Unix(),
+	}}
+
+	tests := []struct {
+		name       string
+		failClosed bool
+		admin      bool
+		wantCode   int
+	}{
+		{"user route fails closed", true, false, http.StatusServiceUnavailable},
+		{"user route fails open", false, false, http.StatusOK},
+		{"admin route always fails closed", false, true, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewAuthMiddleware(jwtService, unavailableDenylist{}, tt.failClosed, DefaultTokenTransport())
+			handler := m.RequireAuth()
+			if tt.admin {
+				handler = m.RequireAdmin()
+			}
+
+			c, recorder := newTestContext(http.MethodGet, map[string]string{"Authorization": "Bearer token"}, nil)
+			handler(c)
+			if !c.IsAborted() {
+				c.Status(http.StatusOK)
+			}
+			if recorder.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+		})
+	}
+}
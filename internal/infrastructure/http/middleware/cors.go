@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/otp-auth/internal/application/ports/repositories"
 )
 
 // CORSConfig holds CORS configuration
@@ -15,6 +18,9 @@ type CORSConfig struct {
 	ExposeHeaders    []string
 	AllowCredentials bool
 	MaxAge           int
+	// Optional check for origins not in AllowOrigins, e.g. those registered by
+	// clients; requests from these origins are never allowed credentials
+	AllowOriginFunc func(c *gin.Context, origin string) bool
 }
 
 // DefaultCORSConfig returns a default CORS configuration
@@ -22,7 +28,7 @@ func DefaultCORSConfig() CORSConfig {
 	return CORSConfig{
 		AllowOrigins: []string{"*"},
 		AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders: []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With"},
+		AllowHeaders: []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With", "X-CSRF-Token", "X-Session-ID", "X-App-Version"},
 		ExposeHeaders: []string{"Content-Length"},
 		AllowCredentials: false,
 		MaxAge: 12 * 60 * 60, // 12 hours
@@ -34,7 +40,7 @@ func ProductionCORSConfig(allowedOrigins []string) CORSConfig {
 	return CORSConfig{
 		AllowOrigins: allowedOrigins,
 		AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders: []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With"},
+		AllowHeaders: []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Requested-With", "X-CSRF-Token", "X-Session-ID", "X-App-Version"},
 		ExposeHeaders: []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge: 12 * 60 * 60, // 12 hours
@@ -46,22 +52,25 @@ func CORS(config CORSConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 		
-		// Check if origin is allowed
+		// Check if origin is allowed; "*" is ignored with credentials, since
+		// echoing every origin would let any site read authenticated responses
 		allowedOrigin := ""
-		for _, allowedOrigin := range config.AllowOrigins {
-			if allowedOrigin == "*" {
-				allowedOrigin = origin
-				break
-			}
-			if allowedOrigin == origin {
+		allowCredentials := config.AllowCredentials
+		for _, allowed := range config.AllowOrigins {
+			if allowed == origin || (allowed == "*" && !config.AllowCredentials) {
 				allowedOrigin = origin
 				break
 			}
 		}
+		if allowedOrigin == "" && origin != "" && config.AllowOriginFunc != nil && config.AllowOriginFunc(c, origin) {
+			allowedOrigin = origin
+			allowCredentials = false
+		}
 
 		// Set CORS headers
 		if allowedOrigin != "" {
 			c.Header("Access-Control-Allow-Origin", allowedOrigin)
+			c.Header("Vary", "Origin")
 		}
 		
 		c.Header("Access-Control-Allow-Methods", strings.Join(config.AllowMethods, ", "))
@@ -71,7 +80,7 @@ func CORS(config CORSConfig) gin.HandlerFunc {
 			c.Header("Access-Control-Expose-Headers", strings.Join(config.ExposeHeaders, ", "))
 		}
 		
-		if config.AllowCredentials {
+		if allowCredentials && allowedOrigin != "" {
 			c.Header("Access-Control-Allow-Credentials", "true")
 		}
 		
@@ -87,4 +96,22 @@ func CORS(config CORSConfig) gin.HandlerFunc {
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// ClientCORSOrigins returns an AllowOriginFunc allowing the CORS origins of
+// registered clients on the given paths only, which must not rely on cookies;
+// lookup failures deny the origin
+func ClientCORSOrigins(clients repositories.ClientReader, paths ...string) func(c *gin.Context, origin string) bool {
+	return func(c *gin.Context, origin string) bool {
+		if !slices.Contains(paths, c.Request.URL.Path) {
+			return false
+		}
+
+		allowed, err := clients.AllowsCORSOrigin(c.Request.Context(), origin)
+		if err != nil {
+			log.Printf("[WARN] failed to check client CORS origins for %s: %v", origin, err)
+			return false
+		}
+		return allowed
+	}
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestContext returns a context for a request to /api with the given
+// headers and cookies
+func newTestContext(method string, headers map[string]string, cookies map[string]string) (*gin.Context, *httptest.ResponseRecorder) {
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(method, "/api", nil)
+	for name, value := range headers {
+		c.Request.Header.Set(name, value)
+	}
+	for name, value := range cookies {
+		c.Request.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+	return c, recorder
+}
+
+func TestCSRF(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		headers   map[string]string
+		cookies   map[string]string
+		transport func(*TokenTransport)
+		wantCode  int
+	}{
+		{
+			name:     "cookie auth without token",
+			method:   http.MethodPost,
+			cookies:  map[string]string{"access_token": "a", CSRFCookieName: "t"},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "cookie auth with wrong token",
+			method:   http.MethodPost,
+			headers:  map[string]string{CSRFHeaderName: "other"},
+			cookies:  map[string]string{"access_token": "a", CSRFCookieName: "t"},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "cookie auth without csrf cookie",
+			method:   http.MethodPost,
+			headers:  map[string]string{CSRFHeaderName: ""},
+			cookies:  map[string]string{"refresh_token": "r"},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "cookie auth with token",
+			method:   http.MethodPost,
+			headers:  map[string]string{CSRFHeaderName: "t"},
+			cookies:  map[string]string{"access_token": "a", CSRFCookieName: "t"},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "safe method",
+			method:   http.MethodGet,
+			cookies:  map[string]string{"access_token": "a"},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "bearer header",
+			method:   http.MethodPost,
+			headers:  map[string]string{"Authorization": "Bearer a"},
+			cookies:  map[string]string{"access_token": "a"},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "bearer header with cookie precedence",
+			method:    http.MethodPost,
+			headers:   map[string]string{"Authorization": "Bearer a"},
+			cookies:   map[string]string{"access_token": "a"},
+			transport: func(t *TokenTransport) { t.Precedence = TokenPrecedenceCookie },
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			name:     "no auth cookies",
+			method:   http.MethodPost,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:      "token only",
+			method:    http.MethodPost,
+			cookies:   map[string]string{"access_token": "a"},
+			transport: func(t *TokenTransport) { t.TokenOnly = true },
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "disabled",
+			method:    http.MethodPost,
+			cookies:   map[string]string{"access_token": "a"},
+			transport: func(t *TokenTransport) { t.CSRF = false },
+			wantCode:  http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := DefaultTokenTransport()
+			if tt.transport != nil {
+				tt.transport(&transport)
+			}
+			c, recorder := newTestContext(tt.method, tt.headers, tt.cookies)
+
+			CSRF(transport)(c)
+			if !c.IsAborted() {
+				c.Status(http.StatusOK)
+			}
+			if recorder.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestValidCSRFToken(t *testing.T) {
+	transport := DefaultTokenTransport()
+
+	c, _ := newTestContext(http.MethodPost, map[string]string{CSRFHeaderName: "t"}, map[string]string{CSRFCookieName: "t"})
+	if !ValidCSRFToken(c, transport) {
+		t.Error("matching token rejected")
+	}
+
+	c, _ = newTestContext(http.MethodPost, map[string]string{"Authorization": "Bearer a"}, map[string]string{CSRFCookieName: "t"})
+	if ValidCSRFToken(c, transport) {
+		t.Error("a bearer header stood in for the CSRF token")
+	}
+
+	transport.CSRF = false
+	if !ValidCSRFToken(c, transport) {
+		t.Error("token required with CSRF protection off")
+	}
+}
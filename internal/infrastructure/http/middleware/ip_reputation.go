@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/otp-auth/internal/application/dto"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+)
+
+// IPReputationConfig holds IP reputation configuration
+type IPReputationConfig struct {
+	AllowCIDRs       []string      // Never blocked or auto-banned
+	DenyCIDRs        []string      // Always blocked
+	FailureThreshold int           // Failed requests per window before an automatic ban (0 disables)
+	FailureWindow    time.Duration // Failure counting window
+	BanTTL           time.Duration // Duration of automatic bans
+	CacheTTL         time.Duration // How long the ban list is cached per instance
+}
+
+// IPReputation blocks banned clients and bans clients that keep failing.
+// Client IPs come from gin's ClientIP, which only honors forwarding headers
+// from the router's trusted proxies.
+type IPReputation struct {
+	banRepo     repositories.IPBanRepository
+	rateLimiter repositories.RateLimiter
+	config      IPReputationConfig
+	allow       []*net.IPNet
+	deny        []*net.IPNet
+
+	mu        sync.RWMutex
+	bans      []bannedRange
+	fetchedAt time.Time
+}
+
+// bannedRange is a ban with its range parsed once, when the ban list is loaded
+type bannedRange struct {
+	network *net.IPNet
+	ban     *entities.IPBan
+}
+
+// NewIPReputation creates a new IPReputation middleware
+func NewIPReputation(banRepo repositories.IPBanRepository, rateLimiter repositories.RateLimiter, config IPReputationConfig) (*IPReputation, error) {
+	allow, err := parseIPRanges(config.AllowCIDRs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allow list: %w", err)
+	}
+	deny, err := parseIPRanges(config.DenyCIDRs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deny list: %w", err)
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = 10 * time.Second
+	}
+
+	return &IPReputation{
+		banRepo:     banRepo,
+		rateLimiter: rateLimiter,
+		config:      config,
+		allow:       allow,
+		deny:        deny,
+	}, nil
+}
+
+// Block rejects requests from denied or banned clients
+func (m *IPReputation) Block() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := net.ParseIP(c.ClientIP())
+		if ip == nil || containsIP(m.allow, ip) {
+			c.Next()
+			return
+		}
+
+		if containsIP(m.deny, ip) {
+			m.bannedResponse(c)
+			return
+		}
+
+		for _, banned := range m.activeBans(c.Request.Context()) {
+			if banned.network.Contains(ip) && !banned.ban.IsExpired() {
+				m.bannedResponse(c)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// TrackFailures counts rejected credentials (401) on sensitive routes and bans
+// clients that exceed the failure threshold
+func (m *IPReputation) TrackFailures() gin.HandlerFunc {
+	return m.trackFailures(false)
+}
+
+// TrackFailedLogins is TrackFailures for the OTP login routes, where a rejected
+// submission (400) counts as a failed attempt too
+func (m *IPReputation) TrackFailedLogins() gin.HandlerFunc {
+	return m.trackFailures(true)
+}
+
+func (m *IPReputation) trackFailures(login bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if m.config.FailureThreshold <= 0 || !isFailureStatus(c.Writer.Status(), login) {
+			return
+		}
+
+		ip := net.ParseIP(c.ClientIP())
+		if ip == nil || containsIP(m.allow, ip) {
+			return
+		}
+
+		ctx := c.Request.Context()
+		allowed, _, err := m.rateLimiter.CheckAndIncrement(ctx, "ip_failures:"+ip.String(), m.config.FailureThreshold, m.config.FailureWindow)
+		if err != nil || allowed {
+			return
+		}
+
+		ipRange, err := valueobjects.NewIPRange(ip.String())
+		if err != nil {
+			return
+		}
+
+		ban := entities.NewIPBan(ipRange, fmt.Sprintf("%d failed requests on %s", m.config.FailureThreshold, c.FullPath()), entities.IPBanSourceAuto, m.config.BanTTL)
+		if err := m.banRepo.Add(ctx, ban); err != nil {
+			log.Printf("[ERROR] failed to ban %s: %v", ipRange, err)
+			return
+		}
+		m.rateLimiter.Reset(ctx, "ip_failures:"+ip.String())
+
+		// Apply the ban on this instance immediately
+		m.mu.Lock()
+		m.bans = append(m.bans, parseBans([]*entities.IPBan{ban})...)
+		m.mu.Unlock()
+
+		log.Printf("[WARN] automatically banned %s for %s: %s", ipRange, m.config.BanTTL, ban.Reason)
+	}
+}
+
+// activeBans returns the cached ban list, refreshing it when stale
+func (m *IPReputation) activeBans(ctx context.Context) []bannedRange {
+	m.mu.RLock()
+	bans, fresh := m.bans, time.Since(m.fetchedAt) < m.config.CacheTTL
+	m.mu.RUnlock()
+	if fresh {
+		return bans
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Another request may have refreshed it while we waited
+	if time.Since(m.fetchedAt) < m.config.CacheTTL {
+		return m.bans
+	}
+
+	latest, err := m.banRepo.List(ctx)
+	if err != nil {
+		// Keep serving the last known list and retry after the cache TTL
+		log.Printf("[WARN] failed to refresh IP ban list: %v", err)
+		m.fetchedAt = time.Now()
+		return m.bans
+	}
+
+	m.bans = parseBans(latest)
+	m.fetchedAt = time.Now()
+	return m.bans
+}
+
+// bannedResponse sends a forbidden response for banned clients
+func (m *IPReputation) bannedResponse(c *gin.Context) {
+	c.JSON(http.StatusForbidden, dto.ErrorResponse{
+		Error: "Access from this IP address is blocked",
+		Code:  "IP_BANNED",
+	})
+	c.Abort()
+}
+
+// isFailureStatus reports whether a response status counts as a failed attempt.
+// Unknown or used links, challenges and rate limits don't: they are no guessed
+// credential, and clients sharing an IP would be banned for ordinary errors.
+func isFailureStatus(status int, login bool) bool {
+	return status == http.StatusUnauthorized || (login && status == http.StatusBadRequest)
+}
+
+// parseIPRanges parses a list of IP addresses and CIDR blocks
+func parseIPRanges(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(values))
+	for _, value := range values {
+		ipRange, err := valueobjects.NewIPRange(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", value, err)
+		}
+		network, err := ipRange.Network()
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", value, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// parseBans parses the range of each ban, skipping malformed ones
+func parseBans(bans []*entities.IPBan) []bannedRange {
+	parsed := make([]bannedRange, 0, len(bans))
+	for _, ban := range bans {
+		network, err := ban.Range.Network()
+		if err != nil {
+			log.Printf("[WARN] skipping IP ban with invalid range %q: %v", ban.Range, err)
+			continue
+		}
+		parsed = append(parsed, bannedRange{network: network, ban: ban})
+	}
+	return parsed
+}
+
+// containsIP reports whether any network contains the IP
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
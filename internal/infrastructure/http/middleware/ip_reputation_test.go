@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+)
+
+type staticIPBanRepo struct {
+	repositories.IPBanRepository
+	bans  []*entities.IPBan
+	lists int
+}
+
+func (r *staticIPBanRepo) List(ctx context.Context) ([]*entities.IPBan, error) {
+	r.lists++
+	return r.bans, nil
+}
+
+func TestIPReputationBlock(t *testing.T) {
+	expired := entities.NewIPBan("198.51.100.0/24", "expired", entities.IPBanSourceAuto, time.Hour)
+	expiresAt := time.Now().Add(-time.Minute)
+	expired.ExpiresAt = &expiresAt
+	banRepo := &staticIPBanRepo{bans: []*entities.IPBan{
+		entities.NewIPBan(valueobjects.IPRange("not-a-range"), "malformed", entities.IPBanSourceManual, 0),
+		entities.NewIPBan("192.0.2.0/24", "abuse", entities.IPBanSourceManual, 0),
+		expired,
+	}}
+	m, err := NewIPReputation(banRepo, nil, IPReputationConfig{AllowCIDRs: []string{"192.0.2.10"}, CacheTTL: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		remoteAddr string
+		wantCode   int
+	}{
+		{"192.0.2.1:1234", http.StatusForbidden},
+		{"192.0.2.10:1234", http.StatusOK},   // allow list wins over bans
+		{"198.51.100.7:1234", http.StatusOK}, // ban expired
+		{"203.0.113.5:1234", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		c, recorder := newTestContext(http.MethodGet, nil, nil)
+		c.Request.RemoteAddr = tt.remoteAddr
+		m.Block()(c)
+		if recorder.Code != tt.wantCode {
+			t.Errorf("request from %s: status = %d, want %d", tt.remoteAddr, recorder.Code, tt.wantCode)
+		}
+	}
+
+	// The ban list is loaded and parsed once per cache TTL
+	if banRepo.lists != 1 {
+		t.Errorf("ban list loaded %d times, want 1", banRepo.lists)
+	}
+}
+
+func TestIsFailureStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		login  bool
+		want   bool
+	}{
+		{http.StatusUnauthorized, false, true},
+		{http.StatusUnauthorized, true, true},
+		{http.StatusBadRequest, true, true},
+		{http.StatusBadRequest, false, false},
+		{http.StatusNotFound, true, false},
+		{http.StatusGone, true, false},
+		{http.StatusPreconditionRequired, true, false},
+		{http.StatusTooManyRequests, true, false},
+		{http.StatusOK, true, false},
+	}
+
+	for _, tt := range tests {
+		if got := isFailureStatus(tt.status, tt.login); got != tt.want {
+			t.Errorf("isFailureStatus(%d, %v) = %v, want %v", tt.status, tt.login, got, tt.want)
+		}
+	}
+}
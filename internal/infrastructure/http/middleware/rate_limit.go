@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/otp-auth/internal/application/ports/repositories"
 	"io"
+	"log"
 	"net/http"
 	"strconv"
 	"time"
@@ -20,6 +21,14 @@ type RateLimitConfig struct {
 	Window   time.Duration             // Time window
 	KeyFunc  func(*gin.Context) string // Function to generate rate limit key
 	SkipFunc func(*gin.Context) bool   // Function to skip rate limiting
+	// FailClosed rejects requests while the shared (Redis) limiter is unavailable
+	// instead of letting them through or serving them from a local fallback
+	FailClosed bool
+}
+
+// degradedReporter is implemented by rate limiters that can serve from a local fallback
+type degradedReporter interface {
+	Degraded() bool
 }
 
 // DefaultRateLimitConfig returns a default rate limit configuration
@@ -55,11 +64,22 @@ func RateLimit(rateLimiter repositories.RateLimiter, config RateLimitConfig) gin
 		// Check rate limit and increment counter
 		allowed, count, err := rateLimiter.CheckAndIncrement(c.Request.Context(), key, config.Limit, config.Window)
 		if err != nil {
+			if config.FailClosed {
+				serviceUnavailableResponse(c)
+				return
+			}
 			// Log error but don't block request
+			log.Printf("[WARN] rate limiter error, allowing request: %v", err)
 			c.Next()
 			return
 		}
 
+		// Sensitive routes must not rely on per-instance counters
+		if reporter, ok := rateLimiter.(degradedReporter); ok && config.FailClosed && reporter.Degraded() {
+			serviceUnavailableResponse(c)
+			return
+		}
+
 		if !allowed {
 			// Set rate limit headers
 			c.Header("X-RateLimit-Limit", strconv.Itoa(config.Limit))
@@ -99,6 +119,16 @@ func RateLimit(rateLimiter repositories.RateLimiter, config RateLimitConfig) gin
 	}
 }
 
+// serviceUnavailableResponse rejects a request because rate limiting can't be enforced
+func serviceUnavailableResponse(c *gin.Context) {
+	c.Header("Retry-After", "30")
+	c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+		Error: "Service temporarily unavailable, please try again later",
+		Code:  "SERVICE_UNAVAILABLE",
+	})
+	c.Abort()
+}
+
 // IPBasedRateLimit creates a rate limit middleware based on client IP
 func IPBasedRateLimit(rateLimiter repositories.RateLimiter, limit int, window time.Duration) gin.HandlerFunc {
 	config := RateLimitConfig{
@@ -115,10 +145,11 @@ func IPBasedRateLimit(rateLimiter repositories.RateLimiter, limit int, window ti
 }
 
 // OtpRateLimit creates a rate limit middleware based on phone number
-func OtpRateLimit(rateLimiter repositories.RateLimiter, limit int, window time.Duration) gin.HandlerFunc {
+func OtpRateLimit(rateLimiter repositories.RateLimiter, limit int, window time.Duration, failClosed bool) gin.HandlerFunc {
 	config := RateLimitConfig{
-		Limit:  limit,
-		Window: window,
+		Limit:      limit,
+		Window:     window,
+		FailClosed: failClosed,
 		KeyFunc: func(c *gin.Context) string {
 			// Try to get phone number from request body
 			var req struct {
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+)
+
+// stubRateLimiter allows every request, fails every check, or reports that it
+// serves from a local fallback
+type stubRateLimiter struct {
+	repositories.RateLimiter
+	err      error
+	degraded bool
+}
+
+func (l stubRateLimiter) CheckAndIncrement(ctx context.Context, key string, limit int, window time.Duration) (bool, int, error) {
+	if l.err != nil {
+		return false, 0, l.err
+	}
+	return true, 1, nil
+}
+
+func (l stubRateLimiter) Degraded() bool {
+	return l.degraded
+}
+
+func TestRateLimitFailClosed(t *testing.T) {
+	tests := []struct {
+		name       string
+		limiter    stubRateLimiter
+		failClosed bool
+		wantCode   int
+	}{
+		{"limiter error fails closed", stubRateLimiter{err: errors.New("connection refused")}, true, http.StatusServiceUnavailable},
+		{"limiter error fails open", stubRateLimiter{err: errors.New("connection refused")}, false, http.StatusOK},
+		{"local fallback fails closed", stubRateLimiter{degraded: true}, true, http.StatusServiceUnavailable},
+		{"local fallback serves open routes", stubRateLimiter{degraded: true}, false, http.StatusOK},
+		{"healthy limiter", stubRateLimiter{}, true, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultRateLimitConfig()
+			config.FailClosed = tt.failClosed
+
+			c, recorder := newTestContext(http.MethodPost, nil, nil)
+			RateLimit(tt.limiter, config)(c)
+			if !c.IsAborted() {
+				c.Status(http.StatusOK)
+			}
+			if recorder.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusServiceUnavailable && recorder.Header().Get("Retry-After") == "" {
+				t.Error("503 without Retry-After")
+			}
+		})
+	}
+}
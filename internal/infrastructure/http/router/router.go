@@ -1,13 +1,17 @@
 package router
 
 import (
+	"log"
+
 	"github.com/gin-gonic/gin"
+	"github.com/otp-auth/internal/application/dto"
 	"github.com/otp-auth/internal/application/ports/repositories"
 	"github.com/otp-auth/internal/application/ports/services"
 	"github.com/otp-auth/internal/application/usecases"
 	"github.com/otp-auth/internal/config"
 	"github.com/otp-auth/internal/infrastructure/http/handlers"
 	"github.com/otp-auth/internal/infrastructure/http/middleware"
+	"github.com/otp-auth/internal/infrastructure/metrics"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -35,51 +39,182 @@ func DefaultRouterConfig() RouterConfig {
 // Dependencies holds all the dependencies needed for the router
 type Dependencies struct {
 	// Use cases
-	SendOTPUseCase        *usecases.SendOTPUseCase
-	LoginUseCase          *usecases.LoginUseCase
-	RefreshUseCase        *usecases.RefreshUseCase
-	LogoutUseCase         *usecases.LogoutUseCase
-	GetUserProfileUseCase *usecases.GetUserProfileUseCase
-	GetUsersListUseCase   *usecases.GetUsersListUseCase
+	SendOTPUseCase          *usecases.SendOTPUseCase
+	SendOTPChallengeUseCase *usecases.SendOTPChallengeUseCase // optional
+	LoginUseCase            *usecases.LoginUseCase
+	RefreshUseCase          *usecases.RefreshUseCase
+	LogoutUseCase           *usecases.LogoutUseCase
+	GetUserProfileUseCase   *usecases.GetUserProfileUseCase
+	GetUsersListUseCase     *usecases.GetUsersListUseCase
+	ListIPBansUseCase       *usecases.ListIPBansUseCase
+	AddIPBanUseCase         *usecases.AddIPBanUseCase
+	RemoveIPBanUseCase      *usecases.RemoveIPBanUseCase
+	RevokeUserTokensUseCase *usecases.RevokeUserTokensUseCase
+
+	ListUserSessionsUseCase        *usecases.ListUserSessionsUseCase
+	RevokeUserSessionUseCase       *usecases.RevokeUserSessionUseCase
+	RevokeOtherUserSessionsUseCase *usecases.RevokeOtherUserSessionsUseCase
+	RevokeSessionByLinkUseCase     *usecases.RevokeSessionByLinkUseCase
+	ListDevicesUseCase             *usecases.ListDevicesUseCase
+	IntrospectTokenUseCase         *usecases.IntrospectTokenUseCase
+	RevokeTokenUseCase             *usecases.RevokeTokenUseCase
+	GetOIDCUserInfoUseCase         *usecases.GetOIDCUserInfoUseCase
+
+	AuthorizeUseCase                 *usecases.AuthorizeUseCase
+	CompleteAuthorizationUseCase     *usecases.CompleteAuthorizationUseCase
+	ExchangeAuthorizationCodeUseCase *usecases.ExchangeAuthorizationCodeUseCase
+	IssueClientTokenUseCase          *usecases.IssueClientTokenUseCase
+	ExchangeRefreshTokenUseCase      *usecases.ExchangeRefreshTokenUseCase
+
+	// Client registry administration; nil when clients come from the config file
+	ListClientsUseCase  *usecases.ListClientsUseCase
+	CreateClientUseCase *usecases.CreateClientUseCase
+	UpdateClientUseCase *usecases.UpdateClientUseCase
+	DeleteClientUseCase *usecases.DeleteClientUseCase
 
 	// Services
-	JWTService services.JWTService
+	JWTService         services.JWTService
+	KeySetProvider     services.KeySetProvider
+	TokenVerifyOptions []services.VerifyOption // issuer/audience checks for protected routes
+	// Issuer check for /userinfo, which serves access tokens minted for any client
+	UserInfoVerifyOptions []services.VerifyOption
 
 	// Repositories
-	RateLimiter repositories.RateLimiter
+	RateLimiter         repositories.RateLimiter
+	AccessTokenDenylist repositories.AccessTokenDenylist
+	DenylistFailClosed  bool                      // reject tokens while the denylist is unavailable
+	ClientRepository    repositories.ClientReader // optional; allows the CORS origins of clients on the OAuth token endpoints
+
+	// Middleware
+	IPReputation *middleware.IPReputation // optional
 
 	// Configuration
 	RateLimitConfig *config.RateLimitConfig
+	TokenTransport  middleware.TokenTransport // where clients send tokens and whether cookies are used
+	// OpenID Provider metadata; nil disables the OIDC endpoints
+	OpenIDConfiguration *dto.OpenIDConfiguration
 }
 
 // SetupRouter sets up the Gin router with all routes and middleware
 func SetupRouter(deps Dependencies, config RouterConfig) *gin.Engine {
 	router := gin.New()
 
-	// Set trusted proxies
-	router.SetTrustedProxies(config.TrustedProxies)
+	// Set trusted proxies; ClientIP (used by rate limiting and IP bans) only
+	// honors forwarding headers from these addresses
+	if err := router.SetTrustedProxies(config.TrustedProxies); err != nil {
+		log.Printf("Invalid trusted proxies %v, trusting none: %v", config.TrustedProxies, err)
+		router.SetTrustedProxies(nil)
+	}
 
 	// Global middleware
 	router.Use(gin.Recovery())
 	router.Use(middleware.Logging(config.LoggingConfig))
-	router.Use(middleware.CORS(config.CORSConfig))
+	corsConfig := config.CORSConfig
+	if deps.ClientRepository != nil {
+		// Only endpoints authenticated by client credentials or bearer tokens;
+		// the cookie-authenticated API is limited to the configured origins
+		corsConfig.AllowOriginFunc = middleware.ClientCORSOrigins(deps.ClientRepository, "/oauth/token", "/oauth/revoke", "/userinfo")
+	}
+	router.Use(middleware.CORS(corsConfig))
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(deps.SendOTPUseCase, deps.LoginUseCase, deps.RefreshUseCase, deps.LogoutUseCase)
-	userHandler := handlers.NewUserHandler(deps.GetUserProfileUseCase, deps.GetUsersListUseCase)
+	authHandler := handlers.NewAuthHandler(deps.SendOTPUseCase, deps.SendOTPChallengeUseCase, deps.LoginUseCase, deps.RefreshUseCase, deps.LogoutUseCase, deps.TokenTransport)
+	userHandler := handlers.NewUserHandler(deps.GetUserProfileUseCase, deps.GetUsersListUseCase, deps.RevokeUserTokensUseCase)
 	healthHandler := handlers.NewHealthHandler()
+	sessionHandler := handlers.NewSessionHandler(deps.ListUserSessionsUseCase, deps.RevokeUserSessionUseCase, deps.RevokeOtherUserSessionsUseCase, deps.RevokeSessionByLinkUseCase, deps.TokenTransport)
+	ipBanHandler := handlers.NewIPBanHandler(deps.ListIPBansUseCase, deps.AddIPBanUseCase, deps.RemoveIPBanUseCase)
+	deviceHandler := handlers.NewDeviceHandler(deps.ListDevicesUseCase)
+	jwksHandler := handlers.NewJWKSHandler(deps.KeySetProvider)
+	oauthHandler := handlers.NewOAuthHandler(deps.IntrospectTokenUseCase, deps.RevokeTokenUseCase, deps.ExchangeAuthorizationCodeUseCase, deps.ExchangeRefreshTokenUseCase, deps.IssueClientTokenUseCase)
+	authorizationHandler := handlers.NewAuthorizationHandler(deps.AuthorizeUseCase, deps.SendOTPUseCase, deps.SendOTPChallengeUseCase, deps.CompleteAuthorizationUseCase, deps.TokenTransport.Cookie)
 
 	// Initialize auth middleware
-	authMiddleware := middleware.NewAuthMiddleware(deps.JWTService)
+	authMiddleware := middleware.NewAuthMiddleware(deps.JWTService, deps.AccessTokenDenylist, deps.DenylistFailClosed, deps.TokenTransport, deps.TokenVerifyOptions...)
+
+	// Double-submit CSRF check for state-changing cookie-authenticated routes
+	csrf := middleware.CSRF(deps.TokenTransport)
 
 	// Health check routes (no authentication required)
 	router.GET("/health", healthHandler.Health)
 	router.GET("/live", healthHandler.Live)
 	router.GET("/ready", healthHandler.Ready)
 
+	// Public signing keys for local token verification
+	router.GET("/.well-known/jwks.json", jwksHandler.GetKeySet)
+
+	// OpenID Connect provider endpoints for relying parties
+	if deps.OpenIDConfiguration != nil {
+		oidcHandler := handlers.NewOIDCHandler(deps.OpenIDConfiguration, deps.GetOIDCUserInfoUseCase)
+		userInfoAuth := middleware.NewAuthMiddleware(deps.JWTService, deps.AccessTokenDenylist, deps.DenylistFailClosed, deps.TokenTransport, deps.UserInfoVerifyOptions...)
+
+		router.GET("/.well-known/openid-configuration", oidcHandler.Discovery)
+
+		userInfo := router.Group("/userinfo")
+		{
+			if deps.IPReputation != nil {
+				userInfo.Use(deps.IPReputation.Block())
+			}
+			userInfo.Use(middleware.IPBasedRateLimit(deps.RateLimiter, deps.RateLimitConfig.Requests, deps.RateLimitConfig.Window))
+			userInfo.Use(userInfoAuth.RequireAuth())
+
+			userInfo.GET("", oidcHandler.UserInfo)
+			userInfo.POST("", oidcHandler.UserInfo)
+		}
+	}
+
+	// Failed logins and client authentications count towards automatic IP bans
+	trackFailures := func(c *gin.Context) { c.Next() }
+	trackFailedLogins := trackFailures
+	if deps.IPReputation != nil {
+		trackFailures = deps.IPReputation.TrackFailures()
+		trackFailedLogins = deps.IPReputation.TrackFailedLogins()
+	}
+
+	// OAuth 2.0 endpoints: the hosted login page of the authorization code flow,
+	// and endpoints where clients authenticate on every request
+	oauth := router.Group("/oauth")
+	{
+		if deps.IPReputation != nil {
+			oauth.Use(deps.IPReputation.Block())
+		}
+		oauth.Use(middleware.IPBasedRateLimit(deps.RateLimiter, deps.RateLimitConfig.Requests, deps.RateLimitConfig.Window))
+
+		oauth.GET("/authorize", authorizationHandler.Authorize)
+
+		oauth.POST("/authorize/send-otp",
+			trackFailures,
+			authorizationHandler.SendOTP,
+		)
+
+		oauth.POST("/authorize/verify",
+			trackFailedLogins,
+			authorizationHandler.Verify,
+		)
+
+		oauth.POST("/token",
+			trackFailures,
+			oauthHandler.Token,
+		)
+
+		oauth.POST("/introspect",
+			trackFailures,
+			oauthHandler.Introspect,
+		)
+
+		oauth.POST("/revoke",
+			trackFailures,
+			oauthHandler.Revoke,
+		)
+	}
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
+		// Reject banned clients before spending anything else on them
+		if deps.IPReputation != nil {
+			v1.Use(deps.IPReputation.Block())
+		}
+
 		// IP rate limiting /api/v1 endpoint group
 		v1.Use(middleware.IPBasedRateLimit(deps.RateLimiter, deps.RateLimitConfig.Requests, deps.RateLimitConfig.Window))
 		// Authentication routes (no authentication required)
@@ -87,14 +222,18 @@ func SetupRouter(deps Dependencies, config RouterConfig) *gin.Engine {
 		{
 			// Rate limit for OTP sending (per phone number)
 			auth.POST("/send-otp",
-				middleware.OtpRateLimit(deps.RateLimiter, deps.RateLimitConfig.OTPLimit, deps.RateLimitConfig.OTPWindow),
+				trackFailures,
+				middleware.OtpRateLimit(deps.RateLimiter, deps.RateLimitConfig.OTPLimit, deps.RateLimitConfig.OTPWindow, deps.RateLimitConfig.OTPFailClosed),
 				authHandler.SendOTP,
 			)
 
 			auth.POST("/login",
+				trackFailedLogins,
 				authHandler.Login,
 			)
 
+			// Refresh and logout check the CSRF token themselves whenever they
+			// use a cookie, since a bearer header doesn't cover the refresh token
 			auth.POST("/refresh",
 				authHandler.RefreshToken,
 			)
@@ -102,6 +241,16 @@ func SetupRouter(deps Dependencies, config RouterConfig) *gin.Engine {
 			auth.POST("/logout",
 				authHandler.Logout,
 			)
+
+			// One-time link from new-login notifications: GET only asks for
+			// confirmation, since link previews fetch it too
+			auth.GET("/revoke-session",
+				sessionHandler.ConfirmRevokeSessionByLink,
+			)
+			auth.POST("/revoke-session",
+				trackFailures,
+				sessionHandler.RevokeSessionByLink,
+			)
 		}
 
 		// User routes
@@ -113,12 +262,40 @@ func SetupRouter(deps Dependencies, config RouterConfig) *gin.Engine {
 				userHandler.GetProfile,
 			)
 
+			// Current user's sessions (authentication required)
+			sessions := users.Group("/me/sessions", authMiddleware.RequireAuth(), csrf)
+			{
+				sessions.GET("", sessionHandler.ListSessions)
+				sessions.POST("/revoke-others", sessionHandler.RevokeOtherSessions)
+				sessions.DELETE("/:id", sessionHandler.RevokeSession)
+			}
+
 			// TODO: Admin routes (admin authentication required)
 			users.GET("/",
 				authMiddleware.RequireAuth(),
 				userHandler.GetUsers,
 			)
 		}
+
+		// Admin routes
+		admin := v1.Group("/admin", authMiddleware.RequireAdmin(), csrf)
+		{
+			admin.GET("/ip-bans", ipBanHandler.ListBans)
+			admin.POST("/ip-bans", ipBanHandler.AddBan)
+			admin.DELETE("/ip-bans", ipBanHandler.RemoveBan)
+
+			admin.POST("/users/:id/revoke-tokens", userHandler.RevokeTokens)
+
+			admin.GET("/devices", deviceHandler.ListDevices)
+
+			if deps.ListClientsUseCase != nil {
+				clientHandler := handlers.NewClientHandler(deps.ListClientsUseCase, deps.CreateClientUseCase, deps.UpdateClientUseCase, deps.DeleteClientUseCase)
+				admin.GET("/clients", clientHandler.ListClients)
+				admin.POST("/clients", clientHandler.CreateClient)
+				admin.PUT("/clients/:id", clientHandler.UpdateClient)
+				admin.DELETE("/clients/:id", clientHandler.DeleteClient)
+			}
+		}
 	}
 
 	// Swagger documentation (if enabled)
@@ -155,6 +332,16 @@ func SetupProductionRouter(deps Dependencies, allowedOrigins []string) *gin.Engi
 	return SetupRouter(deps, config)
 }
 
+// SetupMetricsRouter sets up the router of the internal metrics listener, which
+// serves /metrics (Prometheus text format) apart from the public API
+func SetupMetricsRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.GET("/metrics", metrics.Handler())
+
+	return router
+}
+
 // SetupDevelopmentRouter sets up a development router with detailed logging
 func SetupDevelopmentRouter(deps Dependencies) *gin.Engine {
 	config := DefaultRouterConfig()
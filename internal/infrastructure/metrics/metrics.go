@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Counter is a monotonically increasing metric
+type Counter struct {
+	name  string
+	help  string
+	value atomic.Int64
+}
+
+// Inc increments the counter by one
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Add increments the counter by the given delta
+func (c *Counter) Add(delta int64) {
+	if delta > 0 {
+		c.value.Add(delta)
+	}
+}
+
+// Value returns the current counter value
+func (c *Counter) Value() int64 {
+	return c.value.Load()
+}
+
+// Gauge is a metric that can go up and down
+type Gauge struct {
+	name  string
+	help  string
+	value atomic.Int64
+}
+
+// Set sets the gauge to the given value
+func (g *Gauge) Set(value int64) {
+	g.value.Store(value)
+}
+
+// Inc increments the gauge by one
+func (g *Gauge) Inc() {
+	g.value.Add(1)
+}
+
+// Dec decrements the gauge by one
+func (g *Gauge) Dec() {
+	g.value.Add(-1)
+}
+
+// Value returns the current gauge value
+func (g *Gauge) Value() int64 {
+	return g.value.Load()
+}
+
+// registry holds every metric created through this package
+var registry = struct {
+	sync.Mutex
+	counters map[string]*Counter
+	gauges   map[string]*Gauge
+}{
+	counters: make(map[string]*Counter),
+	gauges:   make(map[string]*Gauge),
+}
+
+// NewCounter registers a counter, returning the existing one if the name is already registered
+func NewCounter(name, help string) *Counter {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if c, ok := registry.counters[name]; ok {
+		return c
+	}
+	c := &Counter{name: name, help: help}
+	registry.counters[name] = c
+	return c
+}
+
+// NewGauge registers a gauge, returning the existing one if the name is already registered
+func NewGauge(name, help string) *Gauge {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if g, ok := registry.gauges[name]; ok {
+		return g
+	}
+	g := &Gauge{name: name, help: help}
+	registry.gauges[name] = g
+	return g
+}
+
+// Handler serves all registered metrics in the Prometheus text exposition format
+func Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(render()))
+	}
+}
+
+// render writes all registered metrics sorted by name
+func render() string {
+	registry.Lock()
+	defer registry.Unlock()
+
+	type entry struct {
+		name, help, kind string
+		value            int64
+	}
+
+	entries := make([]entry, 0, len(registry.counters)+len(registry.gauges))
+	for _, c := range registry.counters {
+		entries = append(entries, entry{c.name, c.help, "counter", c.Value()})
+	}
+	for _, g := range registry.gauges {
+		entries = append(entries, entry{g.name, g.help, "gauge", g.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&b, "# HELP %s %s\n", e.name, e.help)
+		fmt.Fprintf(&b, "# TYPE %s %s\n", e.name, e.kind)
+		fmt.Fprintf(&b, "%s %d\n", e.name, e.value)
+	}
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package filesystem
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/otp-auth/pkg/errors"
+)
+
+const (
+	privateKeyFileName  = "private.pem"
+	publicKeyFileName   = "public.pem"
+	publicKeyPermission = 0o644
+)
+
+// KeyPairGenerator returns a new PEM-encoded private/public key pair
+type KeyPairGenerator func() (privateKeyPEM, publicKeyPEM string, err error)
+
+// KeyPairStore keeps a single signing key pair as private.pem and public.pem in a directory
+type KeyPairStore struct {
+	dir string
+}
+
+// NewKeyPairStore creates a key pair store for a directory
+func NewKeyPairStore(dir string) *KeyPairStore {
+	return &KeyPairStore{
+		dir: dir,
+	}
+}
+
+// LoadOrCreate returns the stored key pair, generating and persisting one if the
+// directory has none. Replicas starting together serialize on a lock file, so
+// only the first one generates and the rest load its keys.
+func (s *KeyPairStore) LoadOrCreate(generate KeyPairGenerator) (privateKeyPEM, publicKeyPEM string, err error) {
+	if err := os.MkdirAll(s.dir, dirPermission); err != nil {
+		return "", "", errors.NewInternalError("Failed to create keys directory", err)
+	}
+
+	unlock, err := lockFile(filepath.Join(s.dir, lockFileName))
+	if err != nil {
+		return "", "", errors.NewInternalError("Failed to lock keys directory", err)
+	}
+	defer unlock()
+
+	privatePath := filepath.Join(s.dir, privateKeyFileName)
+	publicPath := filepath.Join(s.dir, publicKeyFileName)
+
+	privateData, privateErr := os.ReadFile(privatePath)
+	publicData, publicErr := os.ReadFile(publicPath)
+	if privateErr != nil && !os.IsNotExist(privateErr) {
+		return "", "", errors.NewInternalError("Failed to read private key", privateErr)
+	}
+	if publicErr != nil && !os.IsNotExist(publicErr) {
+		return "", "", errors.NewInternalError("Failed to read public key", publicErr)
+	}
+
+	switch {
+	case privateErr == nil && publicErr == nil:
+		if len(privateData) == 0 || len(publicData) == 0 {
+			return "", "", errors.NewInternalError(fmt.Sprintf(
+				"Key files in %s are empty; restore them from a backup or remove both to generate a new pair", s.dir), nil)
+		}
+		s.warnIfExposed(privatePath)
+		return string(privateData), string(publicData), nil
+
+	case privateErr == nil || publicErr == nil:
+		present, missing := privateKeyFileName, publicKeyFileName
+		if publicErr == nil {
+			present, missing = publicKeyFileName, privateKeyFileName
+		}
+		// Regenerating here would silently log everyone out; make the operator decide
+		return "", "", errors.NewInternalError(fmt.Sprintf(
+			"Keys directory %s has %s but not %s; restore the missing file or remove both to generate a new pair",
+			s.dir, present, missing), nil)
+	}
+
+	privateKeyPEM, publicKeyPEM, err = generate()
+	if err != nil {
+		return "", "", err
+	}
+
+	// Public key is written last: a crash in between leaves a partial pair, which
+	// is reported on the next start instead of being overwritten
+	if err := writeFileAtomic(privatePath, []byte(privateKeyPEM), filePermission); err != nil {
+		return "", "", errors.NewInternalError("Failed to write private key", err)
+	}
+	if err := writeFileAtomic(publicPath, []byte(publicKeyPEM), publicKeyPermission); err != nil {
+		return "", "", errors.NewInternalError("Failed to write public key", err)
+	}
+
+	log.Printf("Generated new signing key pair in %s", s.dir)
+	return privateKeyPEM, publicKeyPEM, nil
+}
+
+// warnIfExposed logs when the private key is readable by other users
+func (s *KeyPairStore) warnIfExposed(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		log.Printf("[WARN] Private key %s has permissions %v; it should only be readable by its owner (0600)", path, info.Mode().Perm())
+	}
+}
@@ -0,0 +1,58 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func countingGenerator(calls *int) KeyPairGenerator {
+	return func() (string, string, error) {
+		*calls++
+		return "private", "public", nil
+	}
+}
+
+func TestKeyPairStoreLoadOrCreate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keys")
+	store := NewKeyPairStore(dir)
+
+	calls := 0
+	for i := 0; i < 2; i++ {
+		privateKeyPEM, publicKeyPEM, err := store.LoadOrCreate(countingGenerator(&calls))
+		if err != nil {
+			t.Fatalf("LoadOrCreate: %v", err)
+		}
+		if privateKeyPEM != "private" || publicKeyPEM != "public" {
+			t.Errorf("got %q/%q", privateKeyPEM, publicKeyPEM)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("generated %d times, want 1", calls)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, privateKeyFileName))
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != filePermission {
+		t.Errorf("private key permissions = %v, want %v", perm, os.FileMode(filePermission))
+	}
+}
+
+func TestKeyPairStorePartialFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, privateKeyFileName), []byte("private"), filePermission); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	calls := 0
+	_, _, err := NewKeyPairStore(dir).LoadOrCreate(countingGenerator(&calls))
+	if err == nil || !strings.Contains(err.Error(), "not public.pem") {
+		t.Fatalf("expected partial key error, got %v", err)
+	}
+	if calls != 0 {
+		t.Error("generated a new pair over a partial one")
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package filesystem
+
+import (
+	"os"
+)
+
+// lockFile only creates the lock file; replicas sharing a keys directory
+// are expected to run on unix hosts
+func lockFile(path string) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, filePermission)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() { f.Close() }, nil
+}
@@ -0,0 +1,122 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+const (
+	signingKeyExt  = ".json"
+	lockFileName   = ".lock"
+	dirPermission  = 0o700
+	filePermission = 0o600
+)
+
+// SigningKeyRepository implements the signing key ring store as one JSON file per key in a directory
+type SigningKeyRepository struct {
+	dir string
+}
+
+// NewSigningKeyRepository creates a directory-backed signing key repository, creating the directory if needed
+func NewSigningKeyRepository(dir string) (repositories.SigningKeyRepository, error) {
+	if err := os.MkdirAll(dir, dirPermission); err != nil {
+		return nil, errors.NewInternalError("Failed to create signing keys directory", err)
+	}
+
+	return &SigningKeyRepository{
+		dir: dir,
+	}, nil
+}
+
+// List retrieves every key in the ring
+func (r *SigningKeyRepository) List(ctx context.Context) ([]*entities.SigningKey, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to read signing keys directory", err)
+	}
+
+	var keys []*entities.SigningKey
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), signingKeyExt) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(r.dir, entry.Name()))
+		if err != nil {
+			return nil, errors.NewInternalError(fmt.Sprintf("Failed to read signing key %s", entry.Name()), err)
+		}
+
+		var key entities.SigningKey
+		if err := json.Unmarshal(data, &key); err != nil {
+			return nil, errors.NewInternalError(fmt.Sprintf("Failed to decode signing key %s", entry.Name()), err)
+		}
+		keys = append(keys, &key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	return keys, nil
+}
+
+// Save stores a key, replacing any existing key with the same ID
+func (r *SigningKeyRepository) Save(ctx context.Context, key *entities.SigningKey) error {
+	path, err := r.path(key.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(key, "", "  ")
+	if err != nil {
+		return errors.NewInternalError("Failed to encode signing key", err)
+	}
+
+	if err := writeFileAtomic(path, data, filePermission); err != nil {
+		return errors.NewInternalError("Failed to write signing key", err)
+	}
+
+	return nil
+}
+
+// Delete removes a key from the ring
+func (r *SigningKeyRepository) Delete(ctx context.Context, id string) error {
+	path, err := r.path(id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.NewInternalError("Failed to delete signing key", err)
+	}
+
+	return nil
+}
+
+// WithLock runs fn while holding an exclusive lock on the directory
+func (r *SigningKeyRepository) WithLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	unlock, err := lockFile(filepath.Join(r.dir, lockFileName))
+	if err != nil {
+		return errors.NewInternalError("Failed to lock signing keys directory", err)
+	}
+	defer unlock()
+
+	return fn(ctx)
+}
+
+// path returns the file for a key ID, rejecting IDs that would escape the directory
+func (r *SigningKeyRepository) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", errors.NewValidationError(fmt.Sprintf("Invalid signing key ID %q", id), nil)
+	}
+	return filepath.Join(r.dir, id+signingKeyExt), nil
+}
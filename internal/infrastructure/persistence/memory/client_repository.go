@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// ClientRepository serves a fixed set of clients, e.g. those from the config file
+type ClientRepository struct {
+	clients map[string]*entities.Client
+}
+
+// NewClientRepository creates a new ClientRepository; later clients replace
+// earlier ones with the same ID
+func NewClientRepository(clients []*entities.Client) *ClientRepository {
+	byID := make(map[string]*entities.Client, len(clients))
+	for _, client := range clients {
+		byID[client.ID] = client
+	}
+	return &ClientRepository{clients: byID}
+}
+
+// GetByID retrieves a client by ID
+func (r *ClientRepository) GetByID(ctx context.Context, id string) (*entities.Client, error) {
+	client, ok := r.clients[id]
+	if !ok {
+		return nil, errors.NewNotFoundError("Client not found", nil)
+	}
+	copied := *client
+	return &copied, nil
+}
+
+// AllowsCORSOrigin reports whether any client lists origin in its CORS origins
+func (r *ClientRepository) AllowsCORSOrigin(ctx context.Context, origin string) (bool, error) {
+	for _, client := range r.clients {
+		if client.AllowsCORSOrigin(origin) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// clientColumns lists the columns read by scanClient, in order
+const clientColumns = `id, secret_hash, type, audience, redirect_uris, allowed_scopes, allowed_grant_types,
+		access_token_ttl_seconds, refresh_token_ttl_seconds, cors_origins, created_at, updated_at`
+
+// scanClient reads one row selected with clientColumns
+func scanClient(row rowScanner) (*entities.Client, error) {
+	var client entities.Client
+	var accessTTL, refreshTTL int64
+	err := row.Scan(
+		&client.ID,
+		&client.SecretHash,
+		&client.Type,
+		pq.Array(&client.Audience),
+		pq.Array(&client.RedirectURIs),
+		pq.Array(&client.AllowedScopes),
+		pq.Array(&client.AllowedGrantTypes),
+		&accessTTL,
+		&refreshTTL,
+		pq.Array(&client.CORSOrigins),
+		&client.CreatedAt,
+		&client.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	client.AccessTokenTTL = time.Duration(accessTTL) * time.Second
+	client.RefreshTokenTTL = time.Duration(refreshTTL) * time.Second
+	return &client, nil
+}
+
+// ClientRepository implements the client registry using PostgreSQL
+type ClientRepository struct {
+	db *sql.DB
+}
+
+// NewClientRepository creates a new PostgreSQL client repository
+func NewClientRepository(db *sql.DB) repositories.ClientRepository {
+	return &ClientRepository{
+		db: db,
+	}
+}
+
+// GetByID retrieves a client by ID
+func (r *ClientRepository) GetByID(ctx context.Context, id string) (*entities.Client, error) {
+	query := `
+		SELECT ` + clientColumns + `
+		FROM clients
+		WHERE id = $1
+	`
+
+	client, err := scanClient(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Client not found", nil)
+		}
+		return nil, errors.NewInternalError("Failed to get client by ID", err)
+	}
+
+	return client, nil
+}
+
+// AllowsCORSOrigin reports whether any client lists origin in its CORS origins
+func (r *ClientRepository) AllowsCORSOrigin(ctx context.Context, origin string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM clients WHERE cors_origins @> ARRAY[$1]::TEXT[])`
+
+	var allowed bool
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, origin).Scan(&allowed); err != nil {
+		return false, errors.NewInternalError("Failed to check client CORS origins", err)
+	}
+
+	return allowed, nil
+}
+
+// List retrieves all clients ordered by ID
+func (r *ClientRepository) List(ctx context.Context) ([]*entities.Client, error) {
+	query := `
+		SELECT ` + clientColumns + `
+		FROM clients
+		ORDER BY id
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to list clients", err)
+	}
+	defer rows.Close()
+
+	var clients []*entities.Client
+	for rows.Next() {
+		client, err := scanClient(rows)
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to scan client", err)
+		}
+		clients = append(clients, client)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError("Failed to list clients", err)
+	}
+
+	return clients, nil
+}
+
+// Create stores a new client
+func (r *ClientRepository) Create(ctx context.Context, client *entities.Client) error {
+	query := `
+		INSERT INTO clients (id, secret_hash, type, audience, redirect_uris, allowed_scopes, allowed_grant_types,
+			access_token_ttl_seconds, refresh_token_ttl_seconds, cors_origins, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		client.ID,
+		client.SecretHash,
+		client.Type,
+		pq.Array(nonNil(client.Audience)),
+		pq.Array(nonNil(client.RedirectURIs)),
+		pq.Array(nonNil(client.AllowedScopes)),
+		pq.Array(nonNil(client.AllowedGrantTypes)),
+		int64(client.AccessTokenTTL/time.Second),
+		int64(client.RefreshTokenTTL/time.Second),
+		pq.Array(nonNil(client.CORSOrigins)),
+		client.CreatedAt,
+		client.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return errors.NewConflictError("Client with this ID already exists", err)
+		}
+		return errors.NewInternalError("Failed to create client", err)
+	}
+
+	return nil
+}
+
+// Update replaces the settings and secret hash of an existing client
+func (r *ClientRepository) Update(ctx context.Context, client *entities.Client) error {
+	query := `
+		UPDATE clients
+		SET secret_hash = $2, type = $3, audience = $4, redirect_uris = $5, allowed_scopes = $6,
+			allowed_grant_types = $7, access_token_ttl_seconds = $8, refresh_token_ttl_seconds = $9,
+			cors_origins = $10, updated_at = $11
+		WHERE id = $1
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		client.ID,
+		client.SecretHash,
+		client.Type,
+		pq.Array(nonNil(client.Audience)),
+		pq.Array(nonNil(client.RedirectURIs)),
+		pq.Array(nonNil(client.AllowedScopes)),
+		pq.Array(nonNil(client.AllowedGrantTypes)),
+		int64(client.AccessTokenTTL/time.Second),
+		int64(client.RefreshTokenTTL/time.Second),
+		pq.Array(nonNil(client.CORSOrigins)),
+		client.UpdatedAt,
+	)
+	if err != nil {
+		return errors.NewInternalError("Failed to update client", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.NewInternalError("Failed to get rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("Client not found", nil)
+	}
+
+	return nil
+}
+
+// Delete removes a client
+func (r *ClientRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM clients WHERE id = $1`, id)
+	if err != nil {
+		return errors.NewInternalError("Failed to delete client", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.NewInternalError("Failed to get rows affected", err)
+	}
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("Client not found", nil)
+	}
+
+	return nil
+}
+
+// nonNil returns values, or an empty slice for nil so it is stored as '{}' rather than NULL
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+func TestClientRepositoryRoundTrip(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewClientRepository(db)
+	ctx := context.Background()
+
+	client := entities.NewClient("test-" + uuid.New().String())
+	client.Type = "mobile"
+	client.RedirectURIs = []string{"https://partner.example.com/callback"}
+	client.AccessTokenTTL = 5 * time.Minute
+	client.CORSOrigins = []string{"https://" + client.ID + ".example.com"}
+	if err := repo.Create(ctx, client); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	t.Cleanup(func() { repo.Delete(ctx, client.ID) })
+
+	err := repo.Create(ctx, client)
+	if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.ConflictError {
+		t.Errorf("duplicate create error = %v, want conflict", err)
+	}
+
+	got, err := repo.GetByID(ctx, client.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Type != "mobile" || got.AccessTokenTTL != 5*time.Minute || !got.AllowsGrantType("authorization_code") ||
+		!got.AllowsRedirectURI("https://partner.example.com/callback") || len(got.Audience) != 0 {
+		t.Errorf("stored client = %+v", got)
+	}
+
+	allowed, err := repo.AllowsCORSOrigin(ctx, client.CORSOrigins[0])
+	if err != nil || !allowed {
+		t.Errorf("CORS origin allowed = %v, %v; want true", allowed, err)
+	}
+
+	got.AllowedScopes = []string{"openid"}
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got, _ = repo.GetByID(ctx, client.ID); got.AllowsScope("phone") {
+		t.Errorf("allowed scopes = %v after update, want [openid]", got.AllowedScopes)
+	}
+
+	if err := repo.Delete(ctx, client.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	_, err = repo.GetByID(ctx, client.ID)
+	if customErr := errors.GetCustomError(err); customErr == nil || customErr.Type != errors.NotFoundError {
+		t.Errorf("get after delete error = %v, want not found", err)
+	}
+}
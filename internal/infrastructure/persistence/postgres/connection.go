@@ -75,14 +75,14 @@ func NewConnection(config Config) (*sql.DB, error) {
 	return db, nil
 }
 
+// migrationsDir is the migrations directory, relative to the working directory
+var migrationsDir = "internal/infrastructure/persistence/postgres/migrations"
+
 // loadMigrationFiles loads migration files from the migrations directory
 func loadMigrationFiles() ([]struct {
 	version string
 	query   string
 }, error) {
-	// Get the migrations directory path
-	migrationsDir := "internal/infrastructure/persistence/postgres/migrations"
-	
 	// Read all files in the migrations directory
 	files, err := ioutil.ReadDir(migrationsDir)
 	if err != nil {
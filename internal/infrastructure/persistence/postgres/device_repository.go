@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// deviceColumns lists the columns read by scanDevice, in order
+const deviceColumns = `id, user_id, fingerprint, declared_id, name, user_agent, os, browser, app_version,
+		first_ip, last_ip, first_seen_at, last_seen_at`
+
+// scanDevice reads one row selected with deviceColumns
+func scanDevice(row rowScanner) (*entities.Device, error) {
+	var device entities.Device
+	err := row.Scan(
+		&device.ID,
+		&device.UserID,
+		&device.Fingerprint,
+		&device.DeclaredID,
+		&device.Name,
+		&device.UserAgent,
+		&device.OS,
+		&device.Browser,
+		&device.AppVersion,
+		&device.FirstIP,
+		&device.LastIP,
+		&device.FirstSeenAt,
+		&device.LastSeenAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &device, nil
+}
+
+// DeviceRepository implements the device repository using PostgreSQL
+type DeviceRepository struct {
+	db *sql.DB
+}
+
+// NewDeviceRepository creates a new PostgreSQL device repository
+func NewDeviceRepository(db *sql.DB) repositories.DeviceRepository {
+	return &DeviceRepository{
+		db: db,
+	}
+}
+
+// getOne runs a query selecting deviceColumns and returns its single row
+func (r *DeviceRepository) getOne(ctx context.Context, failure string, query string, args ...interface{}) (*entities.Device, error) {
+	device, err := scanDevice(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("Device not found", nil)
+		}
+		return nil, errors.NewInternalError(failure, err)
+	}
+
+	return device, nil
+}
+
+// GetByID retrieves a device by ID
+func (r *DeviceRepository) GetByID(ctx context.Context, id string) (*entities.Device, error) {
+	query := `
+		SELECT ` + deviceColumns + `
+		FROM devices
+		WHERE id = $1
+	`
+
+	return r.getOne(ctx, "Failed to get device by ID", query, id)
+}
+
+// GetByFingerprint retrieves one of a user's devices by its fingerprint
+func (r *DeviceRepository) GetByFingerprint(ctx context.Context, userID, fingerprint string) (*entities.Device, error) {
+	query := `
+		SELECT ` + deviceColumns + `
+		FROM devices
+		WHERE user_id = $1 AND fingerprint = $2
+	`
+
+	return r.getOne(ctx, "Failed to get device by fingerprint", query, userID, fingerprint)
+}
+
+// List retrieves devices, most recently seen first, optionally only those of one user
+func (r *DeviceRepository) List(ctx context.Context, userID string, offset, limit int) ([]*entities.Device, int64, error) {
+	where := ""
+	var args []interface{}
+	if userID != "" {
+		where = " WHERE user_id = $1"
+		args = append(args, userID)
+	}
+
+	var total int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT COUNT(*) FROM devices`+where, args...).Scan(&total); err != nil {
+		return nil, 0, errors.NewInternalError("Failed to count devices", err)
+	}
+
+	query := `SELECT ` + deviceColumns + ` FROM devices` + where +
+		fmt.Sprintf(" ORDER BY last_seen_at DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.NewInternalError("Failed to query devices", err)
+	}
+	defer rows.Close()
+
+	var devices []*entities.Device
+	for rows.Next() {
+		device, err := scanDevice(rows)
+		if err != nil {
+			return nil, 0, errors.NewInternalError("Failed to scan device", err)
+		}
+		devices = append(devices, device)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, errors.NewInternalError("Error iterating devices", err)
+	}
+
+	return devices, total, nil
+}
+
+// Save creates the device or updates the user's device with the same fingerprint,
+// setting the device's ID to the stored one
+func (r *DeviceRepository) Save(ctx context.Context, device *entities.Device) error {
+	// On conflict the first-seen values of the stored row are kept
+	query := `
+		INSERT INTO devices (id, user_id, fingerprint, declared_id, name, user_agent, os, browser, app_version,
+			first_ip, last_ip, first_seen_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (user_id, fingerprint) DO UPDATE
+		SET name = EXCLUDED.name, user_agent = EXCLUDED.user_agent, os = EXCLUDED.os, browser = EXCLUDED.browser,
+			app_version = EXCLUDED.app_version, last_ip = EXCLUDED.last_ip, last_seen_at = EXCLUDED.last_seen_at
+		RETURNING id
+	`
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		device.ID,
+		device.UserID,
+		device.Fingerprint,
+		device.DeclaredID,
+		device.Name,
+		device.UserAgent,
+		device.OS,
+		device.Browser,
+		device.AppVersion,
+		device.FirstIP,
+		device.LastIP,
+		device.FirstSeenAt,
+		device.LastSeenAt,
+	).Scan(&device.ID)
+	if err != nil {
+		return errors.NewInternalError("Failed to save device", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+func TestDeviceRepositorySaveUpsertsByFingerprint(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewDeviceRepository(db)
+	ctx := context.Background()
+	user := createTestUser(t, db, "user")
+
+	first := entities.NewDevice(user.ID, "install-1", "okhttp/4.12.0")
+	first.ID = uuid.New().String()
+	first.Seen("Pixel 8", "okhttp/4.12.0", "203.0.113.7", "2.4.0")
+	if err := repo.Save(ctx, first); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	// A second login from the same device, stored under a fresh ID
+	again := entities.NewDevice(user.ID, "install-1", "okhttp/4.12.0")
+	again.ID = uuid.New().String()
+	again.Seen("", "okhttp/4.12.0", "198.51.100.23", "2.4.1")
+	if err := repo.Save(ctx, again); err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+	if again.ID != first.ID {
+		t.Errorf("saved ID = %s, want the stored device %s", again.ID, first.ID)
+	}
+
+	got, err := repo.GetByFingerprint(ctx, user.ID, first.Fingerprint)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.FirstIP != "203.0.113.7" || got.LastIP != "198.51.100.23" || got.AppVersion != "2.4.1" {
+		t.Errorf("first IP, last IP, app version = %q, %q, %q", got.FirstIP, got.LastIP, got.AppVersion)
+	}
+
+	devices, total, err := repo.List(ctx, user.ID, 0, 10)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if total != 1 || len(devices) != 1 {
+		t.Errorf("got %d of %d devices, want 1", len(devices), total)
+	}
+}
+
+func TestTokenRepositoryStoresDeviceID(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	user := createTestUser(t, db, "user")
+
+	device := entities.NewDevice(user.ID, "", "Mozilla/5.0")
+	device.ID = uuid.New().String()
+	if err := NewDeviceRepository(db).Save(ctx, device); err != nil {
+		t.Fatalf("save device: %v", err)
+	}
+
+	repo := NewTokenRepository(db)
+	token := newTestRefreshToken(t, user.ID)
+	token.DeviceID = device.ID
+	if err := repo.Create(ctx, token); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	got, err := repo.GetByTokenHash(ctx, token.TokenHash)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.DeviceID != device.ID {
+		t.Errorf("device = %q, want %q", got.DeviceID, device.ID)
+	}
+}
@@ -0,0 +1,21 @@
+-- Create signing_keys table (JWT signing key ring)
+CREATE TABLE IF NOT EXISTS signing_keys (
+	kid VARCHAR(64) PRIMARY KEY,
+	algorithm VARCHAR(16) NOT NULL,
+	private_key_pem TEXT NOT NULL,
+	public_key_pem TEXT NOT NULL,
+	status VARCHAR(16) NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+	activated_at TIMESTAMP WITH TIME ZONE NULL,
+	retired_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- Add constraints
+DO $$ BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_signing_keys_status') THEN
+		ALTER TABLE signing_keys ADD CONSTRAINT chk_signing_keys_status CHECK (status IN ('next', 'active', 'retired'));
+	END IF;
+END $$;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_signing_keys_status ON signing_keys(status);
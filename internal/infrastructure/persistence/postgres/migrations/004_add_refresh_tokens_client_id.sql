@@ -0,0 +1,3 @@
+-- Remember which client a refresh token was issued to, so refreshed
+-- access tokens keep the same client_id and audience
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS client_id VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,19 @@
+-- Group refresh tokens into families: every rotation keeps the family of the
+-- login's first token and links to the token it replaced, so reuse of a
+-- rotated token can revoke the whole family
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS family_id UUID;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS parent_id UUID NULL;
+
+-- Existing tokens each start their own family
+UPDATE refresh_tokens SET family_id = id WHERE family_id IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+
+DO $$ BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_refresh_tokens_parent_id') THEN
+		ALTER TABLE refresh_tokens ADD CONSTRAINT fk_refresh_tokens_parent_id
+			FOREIGN KEY (parent_id) REFERENCES refresh_tokens(id) ON DELETE SET NULL;
+	END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_parent_id ON refresh_tokens(parent_id);
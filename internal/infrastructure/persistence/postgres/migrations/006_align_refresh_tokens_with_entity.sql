@@ -0,0 +1,11 @@
+-- Store every RefreshToken field: when it was last used, and why it was revoked
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS last_used TIMESTAMP WITH TIME ZONE NULL;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS revoked BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS revoke_reason VARCHAR(50) NOT NULL DEFAULT '';
+
+-- Tokens revoked before this migration only have revoked_at
+UPDATE refresh_tokens SET revoked = TRUE WHERE revoked_at IS NOT NULL AND revoked = FALSE;
+
+-- Admins are stored with scope 'superadmin' (see User.IsAdmin)
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_scope;
+ALTER TABLE users ADD CONSTRAINT chk_users_scope CHECK (scope IN ('user', 'admin', 'superadmin'));
@@ -0,0 +1,10 @@
+-- Remember when the session (token family) started, so session lifetime
+-- limits are measured from the original login rather than the last refresh
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_started_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE refresh_tokens t
+SET session_started_at = f.started_at
+FROM (SELECT family_id, MIN(created_at) AS started_at FROM refresh_tokens GROUP BY family_id) f
+WHERE t.family_id = f.family_id AND t.session_started_at IS NULL;
+
+ALTER TABLE refresh_tokens ALTER COLUMN session_started_at SET NOT NULL;
@@ -0,0 +1,4 @@
+-- Describe where each session is used, for the user's active sessions list
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS device_name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS user_agent VARCHAR(512) NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45) NOT NULL DEFAULT '';
@@ -0,0 +1,38 @@
+-- Create devices table: one row per user and device fingerprint
+CREATE TABLE IF NOT EXISTS devices (
+	id UUID PRIMARY KEY,
+	user_id UUID NOT NULL,
+	fingerprint VARCHAR(64) NOT NULL,
+	declared_id VARCHAR(255) NOT NULL DEFAULT '',
+	name VARCHAR(100) NOT NULL DEFAULT '',
+	user_agent VARCHAR(512) NOT NULL DEFAULT '',
+	os VARCHAR(50) NOT NULL DEFAULT '',
+	browser VARCHAR(50) NOT NULL DEFAULT '',
+	app_version VARCHAR(50) NOT NULL DEFAULT '',
+	first_ip VARCHAR(45) NOT NULL DEFAULT '',
+	last_ip VARCHAR(45) NOT NULL DEFAULT '',
+	first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+	last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+DO $$ BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_devices_user_id') THEN
+		ALTER TABLE devices ADD CONSTRAINT fk_devices_user_id
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+	END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_devices_user_fingerprint ON devices(user_id, fingerprint);
+CREATE INDEX IF NOT EXISTS idx_devices_last_seen_at ON devices(last_seen_at DESC);
+
+-- Link each refresh token to the device its session was started on
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS device_id UUID NULL;
+
+DO $$ BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_refresh_tokens_device_id') THEN
+		ALTER TABLE refresh_tokens ADD CONSTRAINT fk_refresh_tokens_device_id
+			FOREIGN KEY (device_id) REFERENCES devices(id) ON DELETE SET NULL;
+	END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_device_id ON refresh_tokens(device_id);
@@ -0,0 +1,26 @@
+-- Create clients table (OAuth client registry)
+CREATE TABLE IF NOT EXISTS clients (
+	id VARCHAR(100) PRIMARY KEY,
+	secret_hash VARCHAR(255) NOT NULL DEFAULT '',
+	type VARCHAR(50) NOT NULL DEFAULT '',
+	audience TEXT[] NOT NULL DEFAULT '{}',
+	redirect_uris TEXT[] NOT NULL DEFAULT '{}',
+	allowed_scopes TEXT[] NOT NULL DEFAULT '{openid,phone}',
+	allowed_grant_types TEXT[] NOT NULL DEFAULT '{authorization_code}',
+	access_token_ttl_seconds BIGINT NOT NULL DEFAULT 0,
+	refresh_token_ttl_seconds BIGINT NOT NULL DEFAULT 0,
+	cors_origins TEXT[] NOT NULL DEFAULT '{}',
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Add constraints
+DO $$ BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_clients_token_ttls') THEN
+		ALTER TABLE clients ADD CONSTRAINT chk_clients_token_ttls
+			CHECK (access_token_ttl_seconds >= 0 AND refresh_token_ttl_seconds >= 0);
+	END IF;
+END $$;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_clients_cors_origins ON clients USING GIN (cors_origins);
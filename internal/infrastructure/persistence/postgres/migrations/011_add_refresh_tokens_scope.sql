@@ -0,0 +1,3 @@
+-- Scopes granted to sessions started through the authorization code flow; NULL
+-- for first-party logins, whose access tokens carry the user's role
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS scope TEXT;
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// signingKeyLockID is the advisory lock key guarding signing key rotation
+const signingKeyLockID = 7301
+
+// SigningKeyRepository implements the signing key ring store using PostgreSQL
+type SigningKeyRepository struct {
+	db *sql.DB
+}
+
+// NewSigningKeyRepository creates a new PostgreSQL signing key repository
+func NewSigningKeyRepository(db *sql.DB) repositories.SigningKeyRepository {
+	return &SigningKeyRepository{
+		db: db,
+	}
+}
+
+// List retrieves every key in the ring
+func (r *SigningKeyRepository) List(ctx context.Context) ([]*entities.SigningKey, error) {
+	query := `
+		SELECT kid, algorithm, private_key_pem, public_key_pem, status, created_at, activated_at, retired_at
+		FROM signing_keys
+		ORDER BY created_at ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to list signing keys", err)
+	}
+	defer rows.Close()
+
+	var keys []*entities.SigningKey
+	for rows.Next() {
+		var key entities.SigningKey
+		var activatedAt, retiredAt sql.NullTime
+
+		err := rows.Scan(
+			&key.ID,
+			&key.Algorithm,
+			&key.PrivateKeyPEM,
+			&key.PublicKeyPEM,
+			&key.Status,
+			&key.CreatedAt,
+			&activatedAt,
+			&retiredAt,
+		)
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to scan signing key", err)
+		}
+
+		if activatedAt.Valid {
+			key.ActivatedAt = &activatedAt.Time
+		}
+		if retiredAt.Valid {
+			key.RetiredAt = &retiredAt.Time
+		}
+
+		keys = append(keys, &key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.NewInternalError("Error iterating signing keys", err)
+	}
+
+	return keys, nil
+}
+
+// Save stores a key, replacing any existing key with the same ID
+func (r *SigningKeyRepository) Save(ctx context.Context, key *entities.SigningKey) error {
+	query := `
+		INSERT INTO signing_keys (kid, algorithm, private_key_pem, public_key_pem, status, created_at, activated_at, retired_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (kid) DO UPDATE SET
+			status = EXCLUDED.status,
+			activated_at = EXCLUDED.activated_at,
+			retired_at = EXCLUDED.retired_at
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		key.ID,
+		key.Algorithm,
+		key.PrivateKeyPEM,
+		key.PublicKeyPEM,
+		key.Status,
+		key.CreatedAt,
+		key.ActivatedAt,
+		key.RetiredAt,
+	)
+	if err != nil {
+		return errors.NewInternalError("Failed to save signing key", err)
+	}
+
+	return nil
+}
+
+// Delete removes a key from the ring
+func (r *SigningKeyRepository) Delete(ctx context.Context, id string) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM signing_keys WHERE kid = $1", id); err != nil {
+		return errors.NewInternalError("Failed to delete signing key", err)
+	}
+
+	return nil
+}
+
+// WithLock runs fn in a transaction holding an advisory lock, so the ring is
+// read and written atomically
+func (r *SigningKeyRepository) WithLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewInternalError("Failed to begin signing key transaction", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", signingKeyLockID); err != nil {
+		return errors.NewInternalError("Failed to lock signing keys", err)
+	}
+
+	if err := fn(context.WithValue(ctx, txContextKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewInternalError("Failed to release signing key lock", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+func TestSigningKeyRepositoryWithLockIsAtomic(t *testing.T) {
+	db := newTestDB(t)
+	// Work inside the lock must use the locked connection, not wait for another one
+	db.SetMaxOpenConns(1)
+	repo := NewSigningKeyRepository(db)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	key := &entities.SigningKey{
+		ID:            "test-" + uuid.New().String(),
+		Algorithm:     "ES256",
+		PrivateKeyPEM: "private",
+		PublicKeyPEM:  "public",
+		Status:        entities.SigningKeyStatusNext,
+		CreatedAt:     time.Now(),
+	}
+	t.Cleanup(func() { repo.Delete(context.Background(), key.ID) })
+
+	// A failed rotation leaves the ring unchanged
+	err := repo.WithLock(ctx, func(ctx context.Context) error {
+		if err := repo.Save(ctx, key); err != nil {
+			return err
+		}
+		return fmt.Errorf("rotation failed")
+	})
+	if err == nil {
+		t.Fatal("WithLock() error = nil, want the rotation error")
+	}
+	if findSigningKey(t, ctx, repo, key.ID) != nil {
+		t.Error("key of a failed rotation was stored")
+	}
+
+	err = repo.WithLock(ctx, func(ctx context.Context) error {
+		if err := repo.Save(ctx, key); err != nil {
+			return err
+		}
+		if findSigningKey(t, ctx, repo, key.ID) == nil {
+			t.Error("key not visible inside the lock")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithLock() error = %v", err)
+	}
+	if findSigningKey(t, ctx, repo, key.ID) == nil {
+		t.Error("key of a committed rotation not stored")
+	}
+}
+
+// findSigningKey returns the key with id from the ring, or nil
+func findSigningKey(t *testing.T, ctx context.Context, repo repositories.SigningKeyReader, id string) *entities.SigningKey {
+	t.Helper()
+	keys, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for _, key := range keys {
+		if key.ID == id {
+			return key
+		}
+	}
+	return nil
+}
@@ -11,165 +11,117 @@ import (
 	"github.com/otp-auth/pkg/errors"
 )
 
-// TokenRepository implements the token repository using PostgreSQL
-type TokenRepository struct {
-	db *sql.DB
-}
+// refreshTokenColumns lists the columns read by scanRefreshToken, in order
+const refreshTokenColumns = `id, user_id, session_id, client_id, family_id, parent_id, session_started_at,
+		device_id, device_name, user_agent, ip_address, scope, token_hash, created_at, expires_at, last_used, revoked, revoked_at, revoke_reason`
 
-// NewTokenRepository creates a new PostgreSQL token repository
-func NewTokenRepository(db *sql.DB) repositories.TokenRepository {
-	return &TokenRepository{
-		db: db,
-	}
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
-// GetByID retrieves a refresh token by ID
-func (r *TokenRepository) GetByID(ctx context.Context, id string) (*entities.RefreshToken, error) {
-	query := `
-		SELECT id, user_id, session_id, token_hash, created_at, expires_at, revoked_at
-		FROM refresh_tokens
-		WHERE id = $1
-	`
-
+// scanRefreshToken reads one row selected with refreshTokenColumns
+func scanRefreshToken(row rowScanner) (*entities.RefreshToken, error) {
 	var token entities.RefreshToken
-	var revokedAt sql.NullTime
+	var parentID, deviceID, scope sql.NullString
+	var lastUsed, revokedAt sql.NullTime
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := row.Scan(
 		&token.ID,
 		&token.UserID,
 		&token.SessionID,
+		&token.ClientID,
+		&token.FamilyID,
+		&parentID,
+		&token.SessionStartedAt,
+		&deviceID,
+		&token.DeviceName,
+		&token.UserAgent,
+		&token.IPAddress,
+		&scope,
 		&token.TokenHash,
 		&token.CreatedAt,
 		&token.ExpiresAt,
+		&lastUsed,
+		&token.Revoked,
 		&revokedAt,
+		&token.RevokeReason,
 	)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.NewNotFoundError("Refresh token not found", nil)
-		}
-		return nil, errors.NewInternalError("Failed to get refresh token by ID", err)
+		return nil, err
 	}
 
+	token.ParentID = parentID.String
+	token.DeviceID = deviceID.String
+	if scope.Valid {
+		token.Scope = &scope.String
+	}
+	if lastUsed.Valid {
+		token.LastUsed = &lastUsed.Time
+	}
 	if revokedAt.Valid {
 		token.RevokedAt = &revokedAt.Time
-		token.Revoked = true
 	}
 
 	return &token, nil
 }
 
-// GetByTokenHash retrieves a refresh token by token hash
-func (r *TokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
-	query := `
-		SELECT id, user_id, session_id, token_hash, created_at, expires_at, revoked_at
-		FROM refresh_tokens
-		WHERE token_hash = $1
-	`
-
-	var token entities.RefreshToken
-	var revokedAt sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
-		&token.ID,
-		&token.UserID,
-		&token.SessionID,
-		&token.TokenHash,
-		&token.CreatedAt,
-		&token.ExpiresAt,
-		&revokedAt,
-	)
-
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.NewNotFoundError("Refresh token not found", nil)
-		}
-		return nil, errors.NewInternalError("Failed to get refresh token by hash", err)
+// nullableString maps an empty string to NULL
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
 	}
+	return s
+}
 
-	if revokedAt.Valid {
-		token.RevokedAt = &revokedAt.Time
-		token.Revoked = true
+// nullableTime maps a nil time to NULL
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
 	}
-
-	return &token, nil
+	return *t
 }
 
-// GetByTokenHashAndSessionID retrieves a refresh token by token hash and session ID
-func (r *TokenRepository) GetByTokenHashAndSessionID(ctx context.Context, tokenHash string, sessionID valueobjects.SessionID) (*entities.RefreshToken, error) {
-	query := `
-		SELECT id, user_id, session_id, token_hash, created_at, expires_at, revoked_at
-		FROM refresh_tokens
-		WHERE token_hash = $1 AND session_id = $2
-	`
-
-	var token entities.RefreshToken
-	var revokedAt sql.NullTime
+// TokenRepository implements the token repository using PostgreSQL
+type TokenRepository struct {
+	db *sql.DB
+}
 
-	err := r.db.QueryRowContext(ctx, query, tokenHash, sessionID.String()).Scan(
-		&token.ID,
-		&token.UserID,
-		&token.SessionID,
-		&token.TokenHash,
-		&token.CreatedAt,
-		&token.ExpiresAt,
-		&revokedAt,
-	)
+// NewTokenRepository creates a new PostgreSQL token repository
+func NewTokenRepository(db *sql.DB) repositories.TokenRepository {
+	return &TokenRepository{
+		db: db,
+	}
+}
 
+// getOne runs a query selecting refreshTokenColumns and returns its single row
+func (r *TokenRepository) getOne(ctx context.Context, failure string, query string, args ...interface{}) (*entities.RefreshToken, error) {
+	token, err := scanRefreshToken(conn(ctx, r.db).QueryRowContext(ctx, query, args...))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.NewNotFoundError("Refresh token not found", nil)
 		}
-		return nil, errors.NewInternalError("Failed to get refresh token by hash and session ID", err)
-	}
-
-	if revokedAt.Valid {
-		token.RevokedAt = &revokedAt.Time
-		token.Revoked = true
+		return nil, errors.NewInternalError(failure, err)
 	}
 
-	return &token, nil
+	return token, nil
 }
 
-// GetByUserID retrieves all refresh tokens for a user
-func (r *TokenRepository) GetByUserID(ctx context.Context, userID string) ([]*entities.RefreshToken, error) {
-	query := `
-		SELECT id, user_id, session_id, token_hash, created_at, expires_at, revoked_at
-		FROM refresh_tokens
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, userID)
+// getMany runs a query selecting refreshTokenColumns and returns every row
+func (r *TokenRepository) getMany(ctx context.Context, failure string, query string, args ...interface{}) ([]*entities.RefreshToken, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, errors.NewInternalError("Failed to get refresh tokens by user ID", err)
+		return nil, errors.NewInternalError(failure, err)
 	}
 	defer rows.Close()
 
 	var tokens []*entities.RefreshToken
 	for rows.Next() {
-		var token entities.RefreshToken
-		var revokedAt sql.NullTime
-
-		err := rows.Scan(
-			&token.ID,
-			&token.UserID,
-			&token.SessionID,
-			&token.TokenHash,
-			&token.CreatedAt,
-			&token.ExpiresAt,
-			&revokedAt,
-		)
+		token, err := scanRefreshToken(rows)
 		if err != nil {
 			return nil, errors.NewInternalError("Failed to scan refresh token", err)
 		}
-
-		if revokedAt.Valid {
-			token.RevokedAt = &revokedAt.Time
-			token.Revoked = true
-		}
-
-		tokens = append(tokens, &token)
+		tokens = append(tokens, token)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -179,116 +131,122 @@ func (r *TokenRepository) GetByUserID(ctx context.Context, userID string) ([]*en
 	return tokens, nil
 }
 
-// GetActiveByUserID retrieves all active (non-revoked, non-expired) refresh tokens for a user
-func (r *TokenRepository) GetActiveByUserID(ctx context.Context, userID string) ([]*entities.RefreshToken, error) {
+// GetByID retrieves a refresh token by ID
+func (r *TokenRepository) GetByID(ctx context.Context, id string) (*entities.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, session_id, token_hash, created_at, expires_at, revoked_at
+		SELECT ` + refreshTokenColumns + `
 		FROM refresh_tokens
-		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
-		ORDER BY created_at DESC
+		WHERE id = $1
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
-	if err != nil {
-		return nil, errors.NewInternalError("Failed to get active refresh tokens by user ID", err)
-	}
-	defer rows.Close()
+	return r.getOne(ctx, "Failed to get refresh token by ID", query, id)
+}
 
-	var tokens []*entities.RefreshToken
-	for rows.Next() {
-		var token entities.RefreshToken
-		var revokedAt sql.NullTime
-
-		err := rows.Scan(
-			&token.ID,
-			&token.UserID,
-			&token.SessionID,
-			&token.TokenHash,
-			&token.CreatedAt,
-			&token.ExpiresAt,
-			&revokedAt,
-		)
-		if err != nil {
-			return nil, errors.NewInternalError("Failed to scan active refresh token", err)
-		}
+// GetByTokenHash retrieves a refresh token by token hash
+func (r *TokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	query := `
+		SELECT ` + refreshTokenColumns + `
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
 
-		if revokedAt.Valid {
-			token.RevokedAt = &revokedAt.Time
-			token.Revoked = true
-		}
+	return r.getOne(ctx, "Failed to get refresh token by hash", query, tokenHash)
+}
 
-		tokens = append(tokens, &token)
-	}
+// GetByTokenHashAndSessionID retrieves a refresh token by token hash and session ID
+func (r *TokenRepository) GetByTokenHashAndSessionID(ctx context.Context, tokenHash string, sessionID valueobjects.SessionID) (*entities.RefreshToken, error) {
+	return r.getByTokenHashAndSessionID(ctx, tokenHash, sessionID, "")
+}
 
-	if err = rows.Err(); err != nil {
-		return nil, errors.NewInternalError("Error iterating active refresh tokens", err)
-	}
+// GetByTokenHashAndSessionIDForUpdate retrieves a refresh token by token hash and
+// session ID and locks its row until the surrounding unit of work ends
+func (r *TokenRepository) GetByTokenHashAndSessionIDForUpdate(ctx context.Context, tokenHash string, sessionID valueobjects.SessionID) (*entities.RefreshToken, error) {
+	return r.getByTokenHashAndSessionID(ctx, tokenHash, sessionID, "FOR UPDATE")
+}
 
-	return tokens, nil
+func (r *TokenRepository) getByTokenHashAndSessionID(ctx context.Context, tokenHash string, sessionID valueobjects.SessionID, lock string) (*entities.RefreshToken, error) {
+	query := `
+		SELECT ` + refreshTokenColumns + `
+		FROM refresh_tokens
+		WHERE token_hash = $1 AND session_id = $2
+	` + lock
+
+	return r.getOne(ctx, "Failed to get refresh token by hash and session ID", query, tokenHash, sessionID.String())
 }
 
-// GetBySessionID retrieves all refresh tokens for a session
-func (r *TokenRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*entities.RefreshToken, error) {
+// GetByUserID retrieves all refresh tokens for a user
+func (r *TokenRepository) GetByUserID(ctx context.Context, userID string) ([]*entities.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, session_id, token_hash, created_at, expires_at, revoked_at
+		SELECT ` + refreshTokenColumns + `
 		FROM refresh_tokens
-		WHERE session_id = $1
+		WHERE user_id = $1
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, sessionID)
-	if err != nil {
-		return nil, errors.NewInternalError("Failed to get refresh tokens by session ID", err)
-	}
-	defer rows.Close()
-
-	var tokens []*entities.RefreshToken
-	for rows.Next() {
-		var token entities.RefreshToken
-		var revokedAt sql.NullTime
-
-		err := rows.Scan(
-			&token.ID,
-			&token.UserID,
-			&token.SessionID,
-			&token.TokenHash,
-			&token.CreatedAt,
-			&token.ExpiresAt,
-			&revokedAt,
-		)
-		if err != nil {
-			return nil, errors.NewInternalError("Failed to scan refresh token", err)
-		}
+	return r.getMany(ctx, "Failed to get refresh tokens by user ID", query, userID)
+}
 
-		if revokedAt.Valid {
-			token.RevokedAt = &revokedAt.Time
-			token.Revoked = true
-		}
+// GetActiveByUserID retrieves all active (non-revoked, non-expired) refresh tokens for a user
+func (r *TokenRepository) GetActiveByUserID(ctx context.Context, userID string) ([]*entities.RefreshToken, error) {
+	query := `
+		SELECT ` + refreshTokenColumns + `
+		FROM refresh_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+	`
 
-		tokens = append(tokens, &token)
-	}
+	return r.getMany(ctx, "Failed to get active refresh tokens by user ID", query, userID)
+}
 
-	if err = rows.Err(); err != nil {
-		return nil, errors.NewInternalError("Error iterating refresh tokens", err)
-	}
+// GetBySessionID retrieves all refresh tokens for a session
+func (r *TokenRepository) GetBySessionID(ctx context.Context, sessionID string) ([]*entities.RefreshToken, error) {
+	query := `
+		SELECT ` + refreshTokenColumns + `
+		FROM refresh_tokens
+		WHERE session_id = $1
+		ORDER BY created_at DESC
+	`
 
-	return tokens, nil
+	return r.getMany(ctx, "Failed to get refresh tokens by session ID", query, sessionID)
 }
 
 // Create creates a new refresh token
 func (r *TokenRepository) Create(ctx context.Context, token *entities.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (id, user_id, session_id, token_hash, created_at, expires_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO refresh_tokens (id, user_id, session_id, client_id, family_id, parent_id, session_started_at,
+			device_id, device_name, user_agent, ip_address, scope, token_hash, created_at, expires_at, last_used, revoked, revoked_at, revoke_reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	familyID := token.FamilyID
+	if familyID == "" {
+		familyID = token.ID
+	}
+	sessionStartedAt := token.SessionStartedAt
+	if sessionStartedAt.IsZero() {
+		sessionStartedAt = token.CreatedAt
+	}
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
 		token.ID,
 		token.UserID,
 		token.SessionID,
+		token.ClientID,
+		familyID,
+		nullableString(token.ParentID),
+		sessionStartedAt,
+		nullableString(token.DeviceID),
+		token.DeviceName,
+		token.UserAgent,
+		token.IPAddress,
+		token.Scope,
 		token.TokenHash,
 		token.CreatedAt,
 		token.ExpiresAt,
+		nullableTime(token.LastUsed),
+		token.Revoked,
+		nullableTime(token.RevokedAt),
+		token.RevokeReason,
 	)
 
 	if err != nil {
@@ -302,22 +260,22 @@ func (r *TokenRepository) Create(ctx context.Context, token *entities.RefreshTok
 func (r *TokenRepository) Update(ctx context.Context, token *entities.RefreshToken) error {
 	query := `
 		UPDATE refresh_tokens
-		SET user_id = $2, session_id = $3, token_hash = $4, expires_at = $5, revoked_at = $6
+		SET user_id = $2, session_id = $3, client_id = $4, token_hash = $5, expires_at = $6,
+			last_used = $7, revoked = $8, revoked_at = $9, revoke_reason = $10
 		WHERE id = $1
 	`
 
-	var revokedAt interface{}
-	if token.RevokedAt != nil {
-		revokedAt = *token.RevokedAt
-	}
-
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
 		token.ID,
 		token.UserID,
 		token.SessionID,
+		token.ClientID,
 		token.TokenHash,
 		token.ExpiresAt,
-		revokedAt,
+		nullableTime(token.LastUsed),
+		token.Revoked,
+		nullableTime(token.RevokedAt),
+		token.RevokeReason,
 	)
 
 	if err != nil {
@@ -340,7 +298,7 @@ func (r *TokenRepository) Update(ctx context.Context, token *entities.RefreshTok
 func (r *TokenRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM refresh_tokens WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return errors.NewInternalError("Failed to delete refresh token", err)
 	}
@@ -361,11 +319,11 @@ func (r *TokenRepository) Delete(ctx context.Context, id string) error {
 func (r *TokenRepository) Revoke(ctx context.Context, id string) error {
 	query := `
 		UPDATE refresh_tokens
-		SET revoked_at = $2
+		SET revoked = TRUE, revoked_at = $2
 		WHERE id = $1 AND revoked_at IS NULL
 	`
 
-	result, err := r.db.ExecContext(ctx, query, id, time.Now())
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, time.Now())
 	if err != nil {
 		return errors.NewInternalError("Failed to revoke refresh token", err)
 	}
@@ -386,11 +344,11 @@ func (r *TokenRepository) Revoke(ctx context.Context, id string) error {
 func (r *TokenRepository) RevokeByUserID(ctx context.Context, userID string) error {
 	query := `
 		UPDATE refresh_tokens
-		SET revoked_at = $2
+		SET revoked = TRUE, revoked_at = $2
 		WHERE user_id = $1 AND revoked_at IS NULL
 	`
 
-	_, err := r.db.ExecContext(ctx, query, userID, time.Now())
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, time.Now())
 	if err != nil {
 		return errors.NewInternalError("Failed to revoke refresh tokens by user ID", err)
 	}
@@ -402,11 +360,11 @@ func (r *TokenRepository) RevokeByUserID(ctx context.Context, userID string) err
 func (r *TokenRepository) RevokeBySessionID(ctx context.Context, sessionID string) error {
 	query := `
 		UPDATE refresh_tokens
-		SET revoked_at = $2
+		SET revoked = TRUE, revoked_at = $2
 		WHERE session_id = $1 AND revoked_at IS NULL
 	`
 
-	_, err := r.db.ExecContext(ctx, query, sessionID, time.Now())
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, sessionID, time.Now())
 	if err != nil {
 		return errors.NewInternalError("Failed to revoke refresh tokens by session ID", err)
 	}
@@ -418,13 +376,11 @@ func (r *TokenRepository) RevokeBySessionID(ctx context.Context, sessionID strin
 func (r *TokenRepository) RevokeByTokenHash(ctx context.Context, tokenHash string, reason string) error {
 	query := `
 		UPDATE refresh_tokens
-		SET revoked_at = $2
+		SET revoked = TRUE, revoked_at = $2, revoke_reason = $3
 		WHERE token_hash = $1 AND revoked_at IS NULL
 	`
 
-	// For now, we ignore the reason parameter as our current schema doesn't store revocation reasons
-	// In a production system, you might want to add a revocation_reason column
-	result, err := r.db.ExecContext(ctx, query, tokenHash, time.Now())
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, tokenHash, time.Now(), reason)
 	if err != nil {
 		return errors.NewInternalError("Failed to revoke refresh token by hash", err)
 	}
@@ -445,11 +401,11 @@ func (r *TokenRepository) RevokeByTokenHash(ctx context.Context, tokenHash strin
 func (r *TokenRepository) RevokeByTokenHashAndSessionID(ctx context.Context, tokenHash string, sessionID valueobjects.SessionID, reason string) error {
 	query := `
 		UPDATE refresh_tokens
-		SET revoked_at = $2
+		SET revoked = TRUE, revoked_at = $2, revoke_reason = $4
 		WHERE token_hash = $1 AND session_id = $3 AND revoked_at IS NULL
 	`
 
-	result, err := r.db.ExecContext(ctx, query, tokenHash, time.Now(), sessionID.String())
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, tokenHash, time.Now(), sessionID.String(), reason)
 	if err != nil {
 		return errors.NewInternalError("Failed to revoke refresh token by hash and session ID", err)
 	}
@@ -468,16 +424,91 @@ func (r *TokenRepository) RevokeByTokenHashAndSessionID(ctx context.Context, tok
 
 // RevokeAllByUserID revokes all refresh tokens for a user with a reason
 func (r *TokenRepository) RevokeAllByUserID(ctx context.Context, userID string, reason string) error {
-	// For now, we ignore the reason parameter as our current schema doesn't store revocation reasons
-	// In a production system, you might want to add a revocation_reason column
-	return r.RevokeByUserID(ctx, userID)
+	query := `
+		UPDATE refresh_tokens
+		SET revoked = TRUE, revoked_at = $2, revoke_reason = $3
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, time.Now(), reason)
+	if err != nil {
+		return errors.NewInternalError("Failed to revoke refresh tokens by user ID", err)
+	}
+
+	return nil
+}
+
+// RevokeSession revokes the active refresh tokens of one of a user's sessions
+func (r *TokenRepository) RevokeSession(ctx context.Context, userID string, sessionID string, reason string) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked = TRUE, revoked_at = $3, revoke_reason = $4
+		WHERE user_id = $1 AND session_id = $2 AND revoked_at IS NULL AND expires_at > $3
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, userID, sessionID, time.Now(), reason)
+	if err != nil {
+		return errors.NewInternalError("Failed to revoke session", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.NewInternalError("Failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("Session not found", nil)
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions revokes the refresh tokens of every session of a user but one
+func (r *TokenRepository) RevokeOtherSessions(ctx context.Context, userID string, keepSessionID string, reason string) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked = TRUE, revoked_at = $3, revoke_reason = $4
+		WHERE user_id = $1 AND session_id <> $2 AND revoked_at IS NULL
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, keepSessionID, time.Now(), reason)
+	if err != nil {
+		return errors.NewInternalError("Failed to revoke other sessions", err)
+	}
+
+	return nil
+}
+
+// RevokeFamily revokes the active refresh tokens of a token family with a reason
+func (r *TokenRepository) RevokeFamily(ctx context.Context, familyID string, reason string) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked = TRUE, revoked_at = $2, revoke_reason = $3
+		WHERE family_id = $1 AND revoked_at IS NULL AND expires_at > $2
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, familyID, time.Now(), reason)
+	if err != nil {
+		return errors.NewInternalError("Failed to revoke refresh token family", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.NewInternalError("Failed to get rows affected", err)
+	}
+
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("Token family not found", nil)
+	}
+
+	return nil
 }
 
 // CleanupExpired removes expired refresh tokens
 func (r *TokenRepository) CleanupExpired(ctx context.Context) error {
 	query := `DELETE FROM refresh_tokens WHERE expires_at < $1`
 
-	_, err := r.db.ExecContext(ctx, query, time.Now())
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now())
 	if err != nil {
 		return errors.NewInternalError("Failed to cleanup expired refresh tokens", err)
 	}
@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+)
+
+// newTestDB connects to the database in OTP_AUTH_TEST_DATABASE_DSN and applies
+// the migrations. Tests using it are skipped when the variable is unset.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("OTP_AUTH_TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("OTP_AUTH_TEST_DATABASE_DSN not set; skipping PostgreSQL integration test")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("ping database: %v", err)
+	}
+
+	// Tests run from the package directory
+	previousMigrationsDir := migrationsDir
+	migrationsDir = "migrations"
+	t.Cleanup(func() { migrationsDir = previousMigrationsDir })
+	if err := RunMigrations(db); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+
+	return db
+}
+
+// createTestUser stores a user with the given scope and removes it (and its tokens) after the test
+func createTestUser(t *testing.T, db *sql.DB, scope string) *entities.User {
+	t.Helper()
+
+	phone, err := valueobjects.NewPhoneNumber(fmt.Sprintf("+98912%07d", rand.Intn(10000000)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := entities.NewUser(phone)
+	user.ID = uuid.New().String()
+	user.Scope = scope
+	if err := NewUserRepository(db).Create(context.Background(), user); err != nil {
+		t.Fatalf("create user with scope %q: %v", scope, err)
+	}
+	t.Cleanup(func() { db.Exec("DELETE FROM users WHERE id = $1", user.ID) })
+
+	return user
+}
+
+func newTestRefreshToken(t *testing.T, userID string) *entities.RefreshToken {
+	t.Helper()
+
+	sessionID, err := valueobjects.NewSessionID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := entities.NewRefreshToken(userID, sessionID, uuid.New().String(), time.Hour)
+	token.ID = uuid.New().String()
+	token.ClientID = "web"
+	token.StartFamily()
+	return token
+}
+
+func TestTokenRepositoryRoundTripsAllFields(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewTokenRepository(db)
+	ctx := context.Background()
+	user := createTestUser(t, db, "user")
+
+	parent := newTestRefreshToken(t, user.ID)
+	if err := repo.Create(ctx, parent); err != nil {
+		t.Fatalf("create parent: %v", err)
+	}
+
+	token := newTestRefreshToken(t, user.ID)
+	token.InheritFamily(parent)
+	token.DeviceName = "Pixel 8"
+	scope := "openid phone"
+	token.Scope = &scope
+	token.RecordClient("Mozilla/5.0", "203.0.113.7")
+	token.UpdateLastUsed()
+	token.Revoke(entities.RevokeReasonLogout)
+	if err := repo.Create(ctx, token); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	got, err := repo.GetByTokenHash(ctx, token.TokenHash)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.ID != token.ID || got.UserID != token.UserID || got.SessionID != token.SessionID ||
+		got.ClientID != token.ClientID || got.TokenHash != token.TokenHash ||
+		got.DeviceName != token.DeviceName || got.UserAgent != token.UserAgent || got.IPAddress != token.IPAddress {
+		t.Errorf("identity fields = %+v, want %+v", got, token)
+	}
+	if got.Scope == nil || *got.Scope != scope {
+		t.Errorf("scope = %v, want %q", got.Scope, scope)
+	}
+	if got.FamilyID != parent.ID || got.ParentID != parent.ID {
+		t.Errorf("family, parent = %q, %q, want both %q", got.FamilyID, got.ParentID, parent.ID)
+	}
+	if !got.SessionStartedAt.Equal(parent.CreatedAt.Truncate(time.Microsecond)) {
+		t.Errorf("session started = %v, want the parent's creation %v", got.SessionStartedAt, parent.CreatedAt)
+	}
+	if !got.CreatedAt.Equal(token.CreatedAt.Truncate(time.Microsecond)) || !got.ExpiresAt.Equal(token.ExpiresAt.Truncate(time.Microsecond)) {
+		t.Errorf("created, expires = %v, %v, want %v, %v", got.CreatedAt, got.ExpiresAt, token.CreatedAt, token.ExpiresAt)
+	}
+	if got.LastUsed == nil || !got.LastUsed.Equal(token.LastUsed.Truncate(time.Microsecond)) {
+		t.Errorf("last used = %v, want %v", got.LastUsed, token.LastUsed)
+	}
+	if !got.Revoked || got.RevokedAt == nil || got.RevokeReason != entities.RevokeReasonLogout {
+		t.Errorf("revoked, at, reason = %v, %v, %q, want a logout revocation", got.Revoked, got.RevokedAt, got.RevokeReason)
+	}
+}
+
+func TestTokenRepositoryUpdateStoresLastUsed(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewTokenRepository(db)
+	ctx := context.Background()
+	user := createTestUser(t, db, "user")
+
+	token := newTestRefreshToken(t, user.ID)
+	if err := repo.Create(ctx, token); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	got, err := repo.GetByTokenHash(ctx, token.TokenHash)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.LastUsed != nil {
+		t.Fatalf("last used = %v for an unused token", got.LastUsed)
+	}
+
+	got.UpdateLastUsed()
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	reloaded, err := repo.GetByTokenHash(ctx, token.TokenHash)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if reloaded.LastUsed == nil || !reloaded.LastUsed.Equal(got.LastUsed.Truncate(time.Microsecond)) {
+		t.Errorf("last used = %v, want %v", reloaded.LastUsed, got.LastUsed)
+	}
+}
+
+func TestTokenRepositoryStoresRevokeReasons(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewTokenRepository(db)
+	ctx := context.Background()
+	user := createTestUser(t, db, "user")
+
+	rotated := newTestRefreshToken(t, user.ID)
+	family := newTestRefreshToken(t, user.ID)
+	other := newTestRefreshToken(t, user.ID)
+	for _, token := range []*entities.RefreshToken{rotated, family, other} {
+		if err := repo.Create(ctx, token); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	if err := repo.RevokeByTokenHashAndSessionID(ctx, rotated.TokenHash, rotated.SessionID, entities.RevokeReasonRefresh); err != nil {
+		t.Fatalf("revoke by hash: %v", err)
+	}
+	if err := repo.RevokeFamily(ctx, family.FamilyID, entities.RevokeReasonReuse); err != nil {
+		t.Fatalf("revoke family: %v", err)
+	}
+	if err := repo.RevokeAllByUserID(ctx, user.ID, entities.RevokeReasonAdmin); err != nil {
+		t.Fatalf("revoke all: %v", err)
+	}
+
+	want := map[string]string{
+		rotated.ID: entities.RevokeReasonRefresh,
+		family.ID:  entities.RevokeReasonReuse,
+		other.ID:   entities.RevokeReasonAdmin, // earlier revocations keep their reason
+	}
+	tokens, err := repo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %d tokens, want %d", len(tokens), len(want))
+	}
+	for _, token := range tokens {
+		if !token.Revoked || token.RevokeReason != want[token.ID] {
+			t.Errorf("token %s revoked, reason = %v, %q, want %q", token.ID, token.Revoked, token.RevokeReason, want[token.ID])
+		}
+	}
+
+	active, err := repo.GetActiveByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("list active: %v", err)
+	}
+	if len(active) != 0 {
+		t.Errorf("got %d active tokens after revoking all", len(active))
+	}
+}
+
+func TestUsersAcceptSuperadminScope(t *testing.T) {
+	db := newTestDB(t)
+	user := createTestUser(t, db, "superadmin")
+
+	got, err := NewUserRepository(db).GetByID(context.Background(), user.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !got.IsAdmin() {
+		t.Errorf("scope = %q, want an admin", got.Scope)
+	}
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// txContextKey carries the transaction of the current unit of work
+type txContextKey struct{}
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn returns the unit of work's transaction if ctx carries one, and db otherwise
+func conn(ctx context.Context, db *sql.DB) queryer {
+	if tx, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// UnitOfWork implements the unit of work using PostgreSQL transactions
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+// NewUnitOfWork creates a new PostgreSQL unit of work
+func NewUnitOfWork(db *sql.DB) repositories.UnitOfWork {
+	return &UnitOfWork{
+		db: db,
+	}
+}
+
+// Do runs fn in a transaction shared by the repositories through ctx
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewInternalError("Failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txContextKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.NewInternalError("Failed to commit transaction", err)
+	}
+
+	return nil
+}
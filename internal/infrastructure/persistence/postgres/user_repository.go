@@ -26,14 +26,24 @@ func NewUserRepository(db *sql.DB) repositories.UserRepository {
 
 // GetByID retrieves a user by ID
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*entities.User, error) {
+	return r.getByID(ctx, id, "")
+}
+
+// GetByIDForUpdate retrieves a user by ID and locks its row until the
+// surrounding unit of work ends
+func (r *UserRepository) GetByIDForUpdate(ctx context.Context, id string) (*entities.User, error) {
+	return r.getByID(ctx, id, "FOR UPDATE")
+}
+
+func (r *UserRepository) getByID(ctx context.Context, id string, lock string) (*entities.User, error) {
 	query := `
 		SELECT id, phone_number, scope, created_at, updated_at
 		FROM users
 		WHERE id = $1
-	`
+	` + lock
 
 	var user entities.User
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&user.ID,
 		&user.PhoneNumber,
 		&user.Scope,
@@ -60,7 +70,7 @@ func (r *UserRepository) GetByPhoneNumber(ctx context.Context, phoneNumber value
 	`
 
 	var user entities.User
-	err := r.db.QueryRowContext(ctx, query, string(phoneNumber)).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, string(phoneNumber)).Scan(
 		&user.ID,
 		&user.PhoneNumber,
 		&user.Scope,
@@ -112,13 +122,13 @@ func (r *UserRepository) GetAll(ctx context.Context, limit, offset int, scope st
 	if scope != "" {
 		countArgs = []interface{}{scope}
 	}
-	err := r.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
 	if err != nil {
 		return nil, 0, errors.NewInternalError("Failed to count users", err)
 	}
 
 	// Get users
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, errors.NewInternalError("Failed to get users", err)
 	}
@@ -154,7 +164,7 @@ func (r *UserRepository) Create(ctx context.Context, user *entities.User) error
 		VALUES ($1, $2, $3, $4, $5)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
 		user.ID,
 		user.PhoneNumber,
 		user.Scope,
@@ -183,7 +193,7 @@ func (r *UserRepository) Update(ctx context.Context, user *entities.User) error
 		WHERE id = $1
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
 		user.ID,
 		user.PhoneNumber,
 		user.Scope,
@@ -216,7 +226,7 @@ func (r *UserRepository) Update(ctx context.Context, user *entities.User) error
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM users WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return errors.NewInternalError("Failed to delete user", err)
 	}
@@ -241,7 +251,7 @@ func (r *UserRepository) UpdateScope(ctx context.Context, id, scope string) erro
 		WHERE id = $1
 	`
 
-	result, err := r.db.ExecContext(ctx, query, id, scope, time.Now())
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, scope, time.Now())
 	if err != nil {
 		return errors.NewInternalError("Failed to update user scope", err)
 	}
@@ -15,7 +15,7 @@ func (r *UserRepository) Exists(ctx context.Context, phoneNumber valueobjects.Ph
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE phone_number = $1)`
 	
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, phoneNumber.String()).Scan(&exists)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, phoneNumber.String()).Scan(&exists)
 	if err != nil {
 		return false, errors.NewInternalError("Failed to check user existence", err)
 	}
@@ -76,13 +76,13 @@ func (r *UserRepository) List(ctx context.Context, offset, limit int, searchPhon
 	// Get total count
 	var total int64
 	countArgs := args[:len(args)-2] // Remove limit and offset for count query
-	err := r.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
 	if err != nil {
 		return nil, 0, errors.NewInternalError("Failed to count users", err)
 	}
 	
 	// Get users
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, errors.NewInternalError("Failed to query users", err)
 	}
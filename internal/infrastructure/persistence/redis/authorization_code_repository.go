@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+const authorizationCodeKeyPrefix = "oauth_code:"
+
+// AuthorizationCodeRepository implements the authorization code repository using Redis
+type AuthorizationCodeRepository struct {
+	client *redis.Client
+}
+
+// NewAuthorizationCodeRepository creates a new Redis authorization code repository
+func NewAuthorizationCodeRepository(client *redis.Client) repositories.AuthorizationCodeRepository {
+	return &AuthorizationCodeRepository{
+		client: client,
+	}
+}
+
+// consumeCodeScript counts the uses of a code and returns it with the count; a
+// code stays stored until it expires, so a second exchange can be recognized
+var consumeCodeScript = redis.NewScript(`
+local code = redis.call('HGET', KEYS[1], 'code')
+if not code then
+	return false
+end
+return {code, redis.call('HINCRBY', KEYS[1], 'uses', 1)}
+`)
+
+// Save stores a code under the hash of its value
+func (r *AuthorizationCodeRepository) Save(ctx context.Context, codeHash string, code *entities.AuthorizationCode, ttl time.Duration) error {
+	value, err := json.Marshal(code)
+	if err != nil {
+		return errors.NewInternalError("Failed to encode authorization code", err)
+	}
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, authorizationCodeKeyPrefix+codeHash, "code", value, "uses", 0)
+		pipe.Expire(ctx, authorizationCodeKeyPrefix+codeHash, ttl)
+		return nil
+	})
+	if err != nil {
+		return errors.NewInternalError("Failed to store authorization code", err)
+	}
+	return nil
+}
+
+// Consume marks the code used and returns it
+func (r *AuthorizationCodeRepository) Consume(ctx context.Context, codeHash string) (*entities.AuthorizationCode, error) {
+	result, err := consumeCodeScript.Run(ctx, r.client, []string{authorizationCodeKeyPrefix + codeHash}).Slice()
+	if err == redis.Nil {
+		return nil, errors.NewNotFoundError("Authorization code not found", nil)
+	}
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to consume authorization code", err)
+	}
+
+	value, _ := result[0].(string)
+	uses, _ := result[1].(int64)
+
+	var code entities.AuthorizationCode
+	if err := json.Unmarshal([]byte(value), &code); err != nil {
+		return nil, errors.NewInternalError("Malformed authorization code", err)
+	}
+	code.Reused = uses > 1
+	return &code, nil
+}
@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// ChallengeRepository implements the challenge repository using Redis
+type ChallengeRepository struct {
+	client *redis.Client
+}
+
+// NewChallengeRepository creates a new Redis challenge repository
+func NewChallengeRepository(client *redis.Client) repositories.ChallengeRepository {
+	return &ChallengeRepository{
+		client: client,
+	}
+}
+
+// MarkUsed records a challenge as redeemed; it returns false if it already was
+func (r *ChallengeRepository) MarkUsed(ctx context.Context, challengeID string, ttl time.Duration) (bool, error) {
+	ok, err := r.client.SetNX(ctx, "challenge_used:"+challengeID, 1, ttl).Result()
+	if err != nil {
+		return false, errors.NewInternalError("Failed to record challenge", err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,161 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// ipBansSetKey indexes every stored ban so they can be listed without SCAN
+const ipBansSetKey = "ip_bans"
+
+// IPBanRepository implements the IP ban repository using Redis.
+// Each ban is stored under its own key so Redis expires it, and a set indexes them.
+type IPBanRepository struct {
+	client *redis.Client
+}
+
+// NewIPBanRepository creates a new Redis IP ban repository
+func NewIPBanRepository(client *redis.Client) repositories.IPBanRepository {
+	return &IPBanRepository{
+		client: client,
+	}
+}
+
+// ipBanKey returns the Redis key for a ban
+func ipBanKey(ipRange valueobjects.IPRange) string {
+	return "ip_ban:" + ipRange.String()
+}
+
+// addBanScript stores a ban unless the range is already banned for longer and
+// returns the ban in effect. A TTL of 0 stores a permanent ban.
+var addBanScript = redis.NewScript(`
+local current = redis.call('PTTL', KEYS[1])
+local ttl = tonumber(ARGV[2])
+if current == -1 and ttl > 0 or current > ttl and ttl > 0 then
+	return redis.call('GET', KEYS[1])
+end
+if ttl > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+redis.call('SADD', KEYS[2], ARGV[3])
+return ARGV[1]
+`)
+
+// Add stores a ban, keeping an existing ban for the same range if it expires
+// later. ban is updated to the ban in effect.
+func (r *IPBanRepository) Add(ctx context.Context, ban *entities.IPBan) error {
+	value, err := json.Marshal(ban)
+	if err != nil {
+		return errors.NewInternalError("Failed to encode IP ban", err)
+	}
+
+	var ttl time.Duration
+	if ban.ExpiresAt != nil {
+		ttl = time.Until(*ban.ExpiresAt)
+		if ttl <= 0 {
+			return nil
+		}
+		if ttl < time.Millisecond {
+			ttl = time.Millisecond
+		}
+	}
+
+	keys := []string{ipBanKey(ban.Range), ipBansSetKey}
+	stored, err := addBanScript.Run(ctx, r.client, keys, value, ttl.Milliseconds(), ban.Range.String()).Text()
+	if err != nil {
+		return errors.NewInternalError("Failed to store IP ban", err)
+	}
+
+	if err := json.Unmarshal([]byte(stored), ban); err != nil {
+		return errors.NewInternalError("Invalid IP ban data format", err)
+	}
+
+	return nil
+}
+
+// Remove deletes the ban for a range
+func (r *IPBanRepository) Remove(ctx context.Context, ipRange valueobjects.IPRange) error {
+	pipe := r.client.TxPipeline()
+	del := pipe.Del(ctx, ipBanKey(ipRange))
+	pipe.SRem(ctx, ipBansSetKey, ipRange.String())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errors.NewInternalError("Failed to remove IP ban", err)
+	}
+
+	if del.Val() == 0 {
+		return errors.NewNotFoundError("IP ban not found", nil)
+	}
+
+	return nil
+}
+
+// Get retrieves the ban for an exact range
+func (r *IPBanRepository) Get(ctx context.Context, ipRange valueobjects.IPRange) (*entities.IPBan, error) {
+	value, err := r.client.Get(ctx, ipBanKey(ipRange)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.NewNotFoundError("IP ban not found", nil)
+		}
+		return nil, errors.NewInternalError("Failed to get IP ban", err)
+	}
+
+	var ban entities.IPBan
+	if err := json.Unmarshal([]byte(value), &ban); err != nil {
+		return nil, errors.NewInternalError("Invalid IP ban data format", err)
+	}
+
+	return &ban, nil
+}
+
+// List retrieves all active bans, pruning index entries whose ban has expired
+func (r *IPBanRepository) List(ctx context.Context) ([]*entities.IPBan, error) {
+	ranges, err := r.client.SMembers(ctx, ipBansSetKey).Result()
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to list IP bans", err)
+	}
+	if len(ranges) == 0 {
+		return []*entities.IPBan{}, nil
+	}
+
+	keys := make([]string, len(ranges))
+	for i, ipRange := range ranges {
+		keys[i] = ipBanKey(valueobjects.IPRange(ipRange))
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to list IP bans", err)
+	}
+
+	bans := make([]*entities.IPBan, 0, len(values))
+	var expired []interface{}
+	for i, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			expired = append(expired, ranges[i])
+			continue
+		}
+
+		var ban entities.IPBan
+		if err := json.Unmarshal([]byte(str), &ban); err != nil {
+			return nil, errors.NewInternalError("Invalid IP ban data format", err)
+		}
+		bans = append(bans, &ban)
+	}
+
+	if len(expired) > 0 {
+		// Best effort cleanup, the keys themselves are already gone
+		r.client.SRem(ctx, ipBansSetKey, expired...)
+	}
+
+	return bans, nil
+}
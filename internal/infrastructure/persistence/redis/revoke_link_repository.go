@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/pkg/errors"
+)
+
+const revokeLinkKeyPrefix = "revoke_link:"
+
+// RevokeLinkRepository implements the revoke link repository using Redis
+type RevokeLinkRepository struct {
+	client *redis.Client
+}
+
+// NewRevokeLinkRepository creates a new Redis revoke link repository
+func NewRevokeLinkRepository(client *redis.Client) repositories.RevokeLinkRepository {
+	return &RevokeLinkRepository{
+		client: client,
+	}
+}
+
+// Save stores a link for the session under the hash of its token
+func (r *RevokeLinkRepository) Save(ctx context.Context, tokenHash, userID, sessionID string, ttl time.Duration) error {
+	if err := r.client.Set(ctx, revokeLinkKeyPrefix+tokenHash, userID+" "+sessionID, ttl).Err(); err != nil {
+		return errors.NewInternalError("Failed to store revoke link", err)
+	}
+	return nil
+}
+
+// Consume deletes the link and returns the session it was for
+func (r *RevokeLinkRepository) Consume(ctx context.Context, tokenHash string) (string, string, error) {
+	// GET and DEL in one transaction, so a link can only be used once
+	var get *redis.StringCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, revokeLinkKeyPrefix+tokenHash)
+		pipe.Del(ctx, revokeLinkKeyPrefix+tokenHash)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return "", "", errors.NewInternalError("Failed to consume revoke link", err)
+	}
+
+	value, err := get.Result()
+	if err == redis.Nil {
+		return "", "", errors.NewNotFoundError("Revoke link not found", nil)
+	}
+	if err != nil {
+		return "", "", errors.NewInternalError("Failed to consume revoke link", err)
+	}
+
+	userID, sessionID, ok := strings.Cut(value, " ")
+	if !ok {
+		return "", "", errors.NewInternalError("Malformed revoke link", nil)
+	}
+	return userID, sessionID, nil
+}
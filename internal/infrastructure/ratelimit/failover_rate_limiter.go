@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/infrastructure/metrics"
+)
+
+var (
+	fallbackActive = metrics.NewGauge(
+		"otp_auth_rate_limiter_fallback_active",
+		"1 while rate limiting is served by the in-process fallback limiter",
+	)
+	failoverTotal = metrics.NewCounter(
+		"otp_auth_rate_limiter_failover_total",
+		"Number of times rate limiting switched to the in-process fallback limiter",
+	)
+	recoveryTotal = metrics.NewCounter(
+		"otp_auth_rate_limiter_recovery_total",
+		"Number of times rate limiting switched back to the primary limiter",
+	)
+	fallbackRequestsTotal = metrics.NewCounter(
+		"otp_auth_rate_limiter_fallback_requests_total",
+		"Number of rate limit checks served by the in-process fallback limiter",
+	)
+)
+
+// FailoverRateLimiter wraps a primary (Redis) rate limiter and switches to a
+// local limiter while the primary is failing. During an outage the primary is
+// probed at most once per probe interval so requests don't pay its timeout.
+type FailoverRateLimiter struct {
+	primary       repositories.RateLimiter
+	fallback      repositories.RateLimiter
+	probeInterval time.Duration
+
+	mu        sync.Mutex
+	degraded  bool
+	since     time.Time
+	lastProbe time.Time
+}
+
+// NewFailoverRateLimiter creates a new FailoverRateLimiter
+func NewFailoverRateLimiter(primary, fallback repositories.RateLimiter, probeInterval time.Duration) *FailoverRateLimiter {
+	if probeInterval <= 0 {
+		probeInterval = 5 * time.Second
+	}
+	return &FailoverRateLimiter{
+		primary:       primary,
+		fallback:      fallback,
+		probeInterval: probeInterval,
+	}
+}
+
+var _ repositories.RateLimiter = (*FailoverRateLimiter)(nil)
+
+// CheckAndIncrement checks the limit on the primary limiter, falling back to the local one on failure
+func (f *FailoverRateLimiter) CheckAndIncrement(ctx context.Context, key string, limit int, window time.Duration) (bool, int, error) {
+	if f.shouldUsePrimary() {
+		allowed, count, err := f.primary.CheckAndIncrement(ctx, key, limit, window)
+		if err == nil {
+			f.markHealthy()
+			return allowed, count, nil
+		}
+		f.markDegraded(err)
+	}
+
+	fallbackRequestsTotal.Inc()
+	return f.fallback.CheckAndIncrement(ctx, key, limit, window)
+}
+
+// GetCount gets the current count for a key from whichever limiter is active
+func (f *FailoverRateLimiter) GetCount(ctx context.Context, key string) (int, error) {
+	if f.shouldUsePrimary() {
+		count, err := f.primary.GetCount(ctx, key)
+		if err == nil {
+			f.markHealthy()
+			return count, nil
+		}
+		f.markDegraded(err)
+	}
+	return f.fallback.GetCount(ctx, key)
+}
+
+// Reset resets the count for a key on both limiters
+func (f *FailoverRateLimiter) Reset(ctx context.Context, key string) error {
+	_ = f.fallback.Reset(ctx, key)
+	if err := f.primary.Reset(ctx, key); err != nil {
+		f.markDegraded(err)
+		return err
+	}
+	return nil
+}
+
+// Degraded reports whether the fallback limiter is currently in use
+func (f *FailoverRateLimiter) Degraded() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.degraded
+}
+
+// shouldUsePrimary reports whether the next call should go to the primary limiter
+func (f *FailoverRateLimiter) shouldUsePrimary() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.degraded {
+		return true
+	}
+	if time.Since(f.lastProbe) >= f.probeInterval {
+		f.lastProbe = time.Now()
+		return true
+	}
+	return false
+}
+
+// markDegraded switches to the fallback limiter
+func (f *FailoverRateLimiter) markDegraded(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.degraded {
+		return
+	}
+	f.degraded = true
+	f.since = time.Now()
+	f.lastProbe = f.since
+
+	failoverTotal.Inc()
+	fallbackActive.Set(1)
+	log.Printf("[WARN] rate limiter: primary unavailable, switching to in-process fallback: %v", err)
+}
+
+// markHealthy switches back to the primary limiter
+func (f *FailoverRateLimiter) markHealthy() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.degraded {
+		return
+	}
+	f.degraded = false
+
+	recoveryTotal.Inc()
+	fallbackActive.Set(0)
+	log.Printf("[INFO] rate limiter: primary recovered after %s, leaving in-process fallback", time.Since(f.since).Round(time.Second))
+}
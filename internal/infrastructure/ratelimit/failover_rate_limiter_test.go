@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyRateLimiter allows every request, or fails while down, counting calls
+type flakyRateLimiter struct {
+	down  bool
+	calls int
+}
+
+func (r *flakyRateLimiter) CheckAndIncrement(ctx context.Context, key string, limit int, window time.Duration) (bool, int, error) {
+	r.calls++
+	if r.down {
+		return false, 0, errors.New("connection refused")
+	}
+	return true, 1, nil
+}
+
+func (r *flakyRateLimiter) GetCount(ctx context.Context, key string) (int, error) {
+	if r.down {
+		return 0, errors.New("connection refused")
+	}
+	return 1, nil
+}
+
+func (r *flakyRateLimiter) Reset(ctx context.Context, key string) error {
+	return nil
+}
+
+func TestFailoverRateLimiter_SwitchesToFallbackAndBack(t *testing.T) {
+	ctx := context.Background()
+	primary := &flakyRateLimiter{down: true}
+	limiter := NewFailoverRateLimiter(primary, NewLocalRateLimiter(), time.Minute)
+
+	// The primary fails: the request is served by the fallback
+	allowed, _, err := limiter.CheckAndIncrement(ctx, "ip:1.2.3.4", 1, time.Minute)
+	if err != nil || !allowed {
+		t.Fatalf("first request: allowed = %v, err = %v, want served by the fallback", allowed, err)
+	}
+	if !limiter.Degraded() {
+		t.Fatal("limiter not degraded after a primary error")
+	}
+
+	// Within the probe interval the primary isn't called; the fallback enforces the limit
+	if allowed, _, _ := limiter.CheckAndIncrement(ctx, "ip:1.2.3.4", 1, time.Minute); allowed {
+		t.Error("fallback did not enforce the limit")
+	}
+	if primary.calls != 1 {
+		t.Errorf("primary calls = %d, want 1 within the probe interval", primary.calls)
+	}
+
+	// The next probe finds the primary healthy again
+	primary.down = false
+	limiter.lastProbe = time.Now().Add(-time.Hour)
+	if allowed, _, err := limiter.CheckAndIncrement(ctx, "ip:1.2.3.4", 1, time.Minute); err != nil || !allowed {
+		t.Fatalf("request after recovery: allowed = %v, err = %v, want served by the primary", allowed, err)
+	}
+	if limiter.Degraded() {
+		t.Error("limiter still degraded after the primary recovered")
+	}
+	if primary.calls != 2 {
+		t.Errorf("primary calls = %d, want 2", primary.calls)
+	}
+}
+
+func TestFailoverRateLimiter_StaysDegradedWhileProbesFail(t *testing.T) {
+	ctx := context.Background()
+	primary := &flakyRateLimiter{down: true}
+	limiter := NewFailoverRateLimiter(primary, NewLocalRateLimiter(), time.Minute)
+
+	limiter.CheckAndIncrement(ctx, "ip:1.2.3.4", 10, time.Minute)
+	limiter.lastProbe = time.Now().Add(-time.Hour)
+	if _, _, err := limiter.CheckAndIncrement(ctx, "ip:1.2.3.4", 10, time.Minute); err != nil {
+		t.Fatalf("failed probe: err = %v, want served by the fallback", err)
+	}
+	if !limiter.Degraded() || primary.calls != 2 {
+		t.Errorf("degraded = %v, primary calls = %d, want still degraded after one probe", limiter.Degraded(), primary.calls)
+	}
+}
@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLocalRateLimiter_CheckAndIncrement(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewLocalRateLimiter()
+	limiter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		allowed, count, err := limiter.CheckAndIncrement(ctx, "phone:+989123456789", 3, 3*time.Minute)
+		if err != nil || !allowed {
+			t.Fatalf("request %d: allowed = %v, err = %v, want allowed", i, allowed, err)
+		}
+		if count != i {
+			t.Errorf("request %d: count = %d, want %d", i, count, i)
+		}
+	}
+
+	if allowed, _, _ := limiter.CheckAndIncrement(ctx, "phone:+989123456789", 3, 3*time.Minute); allowed {
+		t.Fatal("fourth request should be rejected")
+	}
+
+	// One token refills per minute
+	now = now.Add(time.Minute)
+	if allowed, _, _ := limiter.CheckAndIncrement(ctx, "phone:+989123456789", 3, 3*time.Minute); !allowed {
+		t.Fatal("request after refill should be allowed")
+	}
+	if allowed, _, _ := limiter.CheckAndIncrement(ctx, "phone:+989123456789", 3, 3*time.Minute); allowed {
+		t.Fatal("only one token should have refilled")
+	}
+
+	// Other keys are independent
+	if allowed, _, _ := limiter.CheckAndIncrement(ctx, "phone:+989111111111", 3, 3*time.Minute); !allowed {
+		t.Fatal("a different key should have its own bucket")
+	}
+}
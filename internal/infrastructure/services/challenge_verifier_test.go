@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/otp-auth/internal/application/ports/services"
+)
+
+// memoryChallengeStore is an in-memory ChallengeRepository for tests
+type memoryChallengeStore map[string]bool
+
+func (m memoryChallengeStore) MarkUsed(ctx context.Context, challengeID string, ttl time.Duration) (bool, error) {
+	if m[challengeID] {
+		return false, nil
+	}
+	m[challengeID] = true
+	return true, nil
+}
+
+// solvePoW brute-forces a nonce for a proof-of-work challenge
+func solvePoW(t *testing.T, challenge *services.Challenge) string {
+	t.Helper()
+	for i := 0; i < 1<<24; i++ {
+		nonce := strconv.Itoa(i)
+		if leadingZeroBits(sha256.Sum256([]byte(challenge.Token+":"+nonce))) >= challenge.Difficulty {
+			return nonce
+		}
+	}
+	t.Fatal("no nonce found")
+	return ""
+}
+
+func TestPoWChallengeVerifier(t *testing.T) {
+	ctx := context.Background()
+	verifier, err := NewPoWChallengeVerifier(PoWConfig{Secret: "test-secret", Difficulty: 8, TTL: time.Minute}, memoryChallengeStore{})
+	if err != nil {
+		t.Fatalf("NewPoWChallengeVerifier() error = %v", err)
+	}
+
+	challenge, err := verifier.Issue(ctx)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	nonce := solvePoW(t, challenge)
+
+	if err := verifier.Verify(ctx, &services.ChallengeSolution{Token: challenge.Token, Nonce: nonce}, ""); err != nil {
+		t.Fatalf("Verify() with valid solution error = %v", err)
+	}
+
+	if err := verifier.Verify(ctx, &services.ChallengeSolution{Token: challenge.Token, Nonce: nonce}, ""); err == nil {
+		t.Error("Verify() should reject a replayed solution")
+	}
+
+	other, _ := NewPoWChallengeVerifier(PoWConfig{Secret: "other-secret", Difficulty: 8}, memoryChallengeStore{})
+	if err := other.Verify(ctx, &services.ChallengeSolution{Token: challenge.Token, Nonce: nonce}, ""); err == nil {
+		t.Error("Verify() should reject a token signed with another secret")
+	}
+
+	if _, err := NewPoWChallengeVerifier(PoWConfig{Difficulty: 8}, memoryChallengeStore{}); err == nil {
+		t.Error("NewPoWChallengeVerifier() should require a secret")
+	}
+}
+
+func TestCaptchaChallengeVerifier(t *testing.T) {
+	// Local stand-in for the hCaptcha/Turnstile siteverify endpoint
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("secret") != "captcha-secret" {
+			w.Write([]byte(`{"success":false,"error-codes":["invalid-input-secret"]}`))
+			return
+		}
+		if r.PostForm.Get("response") == "pass" {
+			w.Write([]byte(`{"success":true}`))
+			return
+		}
+		w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+	}))
+	defer server.Close()
+
+	verifier, err := NewCaptchaChallengeVerifier(CaptchaConfig{VerifyURL: server.URL, Secret: "captcha-secret", SiteKey: "site"})
+	if err != nil {
+		t.Fatalf("NewCaptchaChallengeVerifier() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if err := verifier.Verify(ctx, &services.ChallengeSolution{Token: "pass"}, "203.0.113.7"); err != nil {
+		t.Errorf("Verify() with accepted token error = %v", err)
+	}
+	if err := verifier.Verify(ctx, &services.ChallengeSolution{Token: "fail"}, "203.0.113.7"); err == nil {
+		t.Error("Verify() should reject a token the endpoint refuses")
+	}
+}
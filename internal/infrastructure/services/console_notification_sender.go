@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/otp-auth/internal/domain/valueobjects"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// ConsoleNotificationSender implements NotificationSender interface for development/testing
+// It prints notifications to the console instead of sending them via SMS
+type ConsoleNotificationSender struct {
+	logger *log.Logger
+}
+
+// NewConsoleNotificationSender creates a new console notification sender
+func NewConsoleNotificationSender(logger *log.Logger) *ConsoleNotificationSender {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &ConsoleNotificationSender{
+		logger: logger,
+	}
+}
+
+// SendNotification prints the notification to console (for development/testing)
+func (s *ConsoleNotificationSender) SendNotification(ctx context.Context, phoneNumber valueobjects.PhoneNumber, message string) error {
+	if phoneNumber == "" {
+		return errors.NewValidationError("Phone number is required", nil)
+	}
+
+	if message == "" {
+		return errors.NewValidationError("Notification message is required", nil)
+	}
+
+	s.logger.Printf("[NOTIFICATION SENDER] Sending notification to %s: %s", string(phoneNumber), message)
+
+	return nil
+}
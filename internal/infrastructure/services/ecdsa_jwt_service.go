@@ -7,20 +7,14 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
-	"github.com/otp-auth/internal/application/ports/services"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/otp-auth/pkg/errors"
 )
 
 // ECDSAJWTService implements JWTService using ECDSA algorithm
 type ECDSAJWTService struct {
-	privateKey      *ecdsa.PrivateKey
-	publicKey       *ecdsa.PublicKey
-	accessTokenTTL  time.Duration
-	refreshTokenTTL time.Duration
-	issuer          string
+	*jwtService
 }
 
 // JWTConfig holds configuration for JWT service
@@ -30,6 +24,10 @@ type JWTConfig struct {
 	AccessTokenTTL  time.Duration
 	RefreshTokenTTL time.Duration
 	Issuer          string
+	// VerificationKeysPEM are extra public keys accepted for verification and
+	// published in the JWKS, e.g. the previous signing key. They may use any
+	// supported algorithm, which allows migrating between algorithms.
+	VerificationKeysPEM []string
 }
 
 // DefaultJWTConfig returns default JWT configuration
@@ -58,8 +56,14 @@ func NewECDSAJWTService(config JWTConfig) (*ECDSAJWTService, error) {
 		if err != nil {
 			return nil, errors.NewInternalError("Failed to parse public key", err)
 		}
+
+		if !privateKey.PublicKey.Equal(publicKey) {
+			return nil, errors.NewInternalError("Public key does not match private key", nil)
+		}
+	} else if config.PrivateKeyPEM != "" || config.PublicKeyPEM != "" {
+		return nil, errors.NewInternalError("Both private and public key are required", nil)
 	} else {
-		// Generate new key pair
+		// Generate an in-memory key pair; tokens won't survive a restart
 		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 		if err != nil {
 			return nil, errors.NewInternalError("Failed to generate ECDSA key pair", err)
@@ -67,122 +71,17 @@ func NewECDSAJWTService(config JWTConfig) (*ECDSAJWTService, error) {
 		publicKey = &privateKey.PublicKey
 	}
 
-	return &ECDSAJWTService{
-		privateKey:      privateKey,
-		publicKey:       publicKey,
-		accessTokenTTL:  config.AccessTokenTTL,
-		refreshTokenTTL: config.RefreshTokenTTL,
-		issuer:          config.Issuer,
-	}, nil
-}
-
-// CustomClaims wraps JWTClaims for jwt library compatibility
-type CustomClaims struct {
-	// Standard JWT claims with explicit JSON tags
-	Subject   string `json:"sub"`
-	Issuer    string `json:"iss"`
-	IssuedAt  int64  `json:"iat"`
-	ExpiresAt int64  `json:"exp"`
-	ID        string `json:"jti"`
-	// Custom claims
-	ClientID string   `json:"client_id"`
-	Scopes   []string `json:"scopes"`
-}
-
-// Implement jwt.Claims interface methods
-func (c CustomClaims) GetExpirationTime() (*jwt.NumericDate, error) {
-	if c.ExpiresAt == 0 {
-		return nil, nil
-	}
-	return jwt.NewNumericDate(time.Unix(c.ExpiresAt, 0)), nil
-}
-
-func (c CustomClaims) GetIssuedAt() (*jwt.NumericDate, error) {
-	if c.IssuedAt == 0 {
-		return nil, nil
-	}
-	return jwt.NewNumericDate(time.Unix(c.IssuedAt, 0)), nil
-}
-
-func (c CustomClaims) GetNotBefore() (*jwt.NumericDate, error) {
-	return nil, nil
-}
-
-func (c CustomClaims) GetIssuer() (string, error) {
-	return c.Issuer, nil
-}
-
-func (c CustomClaims) GetSubject() (string, error) {
-	return c.Subject, nil
-}
-
-func (c CustomClaims) GetAudience() (jwt.ClaimStrings, error) {
-	return nil, nil
-}
-
-// GenerateToken generates a JWT token from claims
-func (j *ECDSAJWTService) GenerateToken(claims *services.JWTClaims) (string, error) {
-	// Debug logging - function entry
-	fmt.Printf("[DEBUG] GenerateToken called\n")
-	fmt.Println("[DEBUG] JWT Claims - Subject:", claims.Subject, "Issuer:", claims.Issuer, "IssuedAt:", claims.IssuedAt, "ExpiresAt:", claims.ExpiresAt, "TokenID:", claims.TokenID, "ClientID:", claims.ClientID, "Scopes:", claims.Scopes)
-
-	// Create custom claims for jwt library
-	customClaims := &CustomClaims{
-		Subject:   claims.Subject,
-		Issuer:    claims.Issuer,
-		IssuedAt:  claims.IssuedAt,
-		ExpiresAt: claims.ExpiresAt,
-		ID:        claims.TokenID,
-		ClientID:  claims.ClientID,
-		Scopes:    claims.Scopes,
-	}
-
-	// Debug logging for custom claims
-	fmt.Printf("[DEBUG] Custom Claims - Subject: %s, Issuer: %s, IssuedAt: %d, ExpiresAt: %d, ID: %s, ClientID: %s, Scopes: %v\n",
-		customClaims.Subject, customClaims.Issuer, customClaims.IssuedAt, customClaims.ExpiresAt, customClaims.ID, customClaims.ClientID, customClaims.Scopes)
-
-	token := jwt.NewWithClaims(jwt.SigningMethodES256, customClaims)
-	tokenString, err := token.SignedString(j.privateKey)
+	service, err := newJWTService(config, privateKey, publicKey)
 	if err != nil {
-		return "", errors.NewInternalError("Failed to sign JWT token", err)
-	}
-
-	return tokenString, nil
-}
-
-// VerifyToken verifies and parses a JWT token, returning the claims
-func (j *ECDSAJWTService) VerifyToken(tokenString string) (*services.JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return j.publicKey, nil
-	})
-
-	if err != nil {
-		return nil, errors.NewUnauthorizedError("Invalid token", err)
-	}
-
-	if claims, ok := token.Claims.(*CustomClaims); ok && token.Valid {
-		// Convert CustomClaims back to JWTClaims
-		jwtClaims := &services.JWTClaims{
-			Subject:   claims.Subject,
-			Issuer:    claims.Issuer,
-			IssuedAt:  claims.IssuedAt,
-			ExpiresAt: claims.ExpiresAt,
-			TokenID:   claims.ID,
-			ClientID:  claims.ClientID,
-			Scopes:    claims.Scopes,
-		}
-		return jwtClaims, nil
+		return nil, err
 	}
 
-	return nil, errors.NewUnauthorizedError("Invalid token claims", nil)
+	return &ECDSAJWTService{jwtService: service}, nil
 }
 
 // GetPublicKeyPEM returns the public key in PEM format
 func (j *ECDSAJWTService) GetPublicKeyPEM() (string, error) {
-	x509EncodedPub, err := x509.MarshalPKIXPublicKey(j.publicKey)
+	x509EncodedPub, err := x509.MarshalPKIXPublicKey(j.current().publicKey)
 	if err != nil {
 		return "", errors.NewInternalError("Failed to marshal public key", err)
 	}
@@ -197,7 +96,12 @@ func (j *ECDSAJWTService) GetPublicKeyPEM() (string, error) {
 
 // GetPrivateKeyPEM returns the private key in PEM format
 func (j *ECDSAJWTService) GetPrivateKeyPEM() (string, error) {
-	x509Encoded, err := x509.MarshalECPrivateKey(j.privateKey)
+	privateKey, ok := j.current().privateKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return "", errors.NewInternalError("Signing key is not an ECDSA key", nil)
+	}
+
+	x509Encoded, err := x509.MarshalECPrivateKey(privateKey)
 	if err != nil {
 		return "", errors.NewInternalError("Failed to marshal private key", err)
 	}
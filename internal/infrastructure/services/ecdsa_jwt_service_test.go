@@ -0,0 +1,170 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+)
+
+func newTestJWTService(t *testing.T, verificationKeys ...string) *ECDSAJWTService {
+	t.Helper()
+	svc, err := NewECDSAJWTService(JWTConfig{
+		AccessTokenTTL:      time.Minute,
+		RefreshTokenTTL:     time.Hour,
+		Issuer:              "test",
+		VerificationKeysPEM: verificationKeys,
+	})
+	if err != nil {
+		t.Fatalf("NewECDSAJWTService: %v", err)
+	}
+	return svc
+}
+
+func testClaims() *services.JWTClaims {
+	claims := services.NewJWTClaims("user-1", "client", []string{"user"}, time.Minute, "test", "jti-1")
+	claims.SessionID = "session-1"
+	return claims
+}
+
+func TestECDSAJWTServiceStampsKid(t *testing.T) {
+	svc := newTestJWTService(t)
+
+	tokenString, err := svc.GenerateToken(testClaims())
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &CustomClaims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified: %v", err)
+	}
+	if kid := token.Header["kid"]; kid != svc.KeyID() {
+		t.Errorf("kid = %v, want %s", kid, svc.KeyID())
+	}
+
+	claims, err := svc.VerifyToken(tokenString)
+	if err != nil {
+		t.Fatalf("VerifyToken: %v", err)
+	}
+	if claims.Subject != "user-1" {
+		t.Errorf("Subject = %s, want user-1", claims.Subject)
+	}
+}
+
+func TestECDSAJWTServiceVerifiesWithKeySet(t *testing.T) {
+	previous := newTestJWTService(t)
+	previousPEM, err := previous.GetPublicKeyPEM()
+	if err != nil {
+		t.Fatalf("GetPublicKeyPEM: %v", err)
+	}
+	current := newTestJWTService(t, previousPEM)
+
+	oldToken, err := previous.GenerateToken(testClaims())
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if _, err := current.VerifyToken(oldToken); err != nil {
+		t.Errorf("token signed by a verification key rejected: %v", err)
+	}
+
+	if _, err := previous.VerifyToken(mustToken(t, current)); err == nil {
+		t.Error("token signed by an unknown kid accepted")
+	}
+
+	keySet, err := current.KeySet()
+	if err != nil {
+		t.Fatalf("KeySet: %v", err)
+	}
+	if len(keySet.Keys) != 2 {
+		t.Fatalf("len(Keys) = %d, want 2", len(keySet.Keys))
+	}
+	if keySet.Keys[0].Kid != current.KeyID() || keySet.Keys[1].Kid != previous.KeyID() {
+		t.Errorf("unexpected key order: %s, %s", keySet.Keys[0].Kid, keySet.Keys[1].Kid)
+	}
+	for _, key := range keySet.Keys {
+		if key.Kty != "EC" || key.Alg != "ES256" || key.Crv != "P-256" || key.X == "" || key.Y == "" {
+			t.Errorf("unexpected JWK: %+v", key)
+		}
+	}
+}
+
+func TestECDSAKeyIDStableAcrossPEM(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	parsed, err := parsePublicKeyFromPEM(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	if err != nil {
+		t.Fatalf("parsePublicKeyFromPEM: %v", err)
+	}
+
+	a, err := publicKeyID(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("ecdsaKeyID: %v", err)
+	}
+	b, err := publicKeyID(parsed)
+	if err != nil {
+		t.Fatalf("ecdsaKeyID: %v", err)
+	}
+	if a != b || len(a) != 43 {
+		t.Errorf("key IDs %q and %q should be equal 43-char thumbprints", a, b)
+	}
+}
+
+func mustToken(t *testing.T, svc *ECDSAJWTService) string {
+	t.Helper()
+	token, err := svc.GenerateToken(testClaims())
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	return token
+}
+
+func TestECDSAJWTServiceLoadSigningKeys(t *testing.T) {
+	generator := NewECDSAKeyGenerator()
+	retired, err := generator.GenerateSigningKey()
+	if err != nil {
+		t.Fatalf("GenerateSigningKey: %v", err)
+	}
+	active, err := generator.GenerateSigningKey()
+	if err != nil {
+		t.Fatalf("GenerateSigningKey: %v", err)
+	}
+	now := time.Now()
+	retired.Activate(now)
+	active.Activate(now)
+
+	// Sign with the soon-to-be-retired key first
+	svc := newTestJWTService(t)
+	if err := svc.LoadSigningKeys([]*entities.SigningKey{retired}); err != nil {
+		t.Fatalf("LoadSigningKeys: %v", err)
+	}
+	oldToken := mustToken(t, svc)
+
+	retired.Retire(now)
+	if err := svc.LoadSigningKeys([]*entities.SigningKey{retired, active}); err != nil {
+		t.Fatalf("LoadSigningKeys: %v", err)
+	}
+	if svc.KeyID() != active.ID {
+		t.Errorf("KeyID = %s, want %s", svc.KeyID(), active.ID)
+	}
+	if _, err := svc.VerifyToken(oldToken); err != nil {
+		t.Errorf("token signed by a retired key rejected: %v", err)
+	}
+
+	if err := svc.LoadSigningKeys([]*entities.SigningKey{retired}); err == nil {
+		t.Error("ring without an active key accepted")
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+
+	"github.com/otp-auth/pkg/errors"
+)
+
+// EdDSAJWTService implements JWTService using Ed25519, for smaller and faster tokens
+type EdDSAJWTService struct {
+	*jwtService
+}
+
+// NewEdDSAJWTService creates a new EdDSA JWT service
+func NewEdDSAJWTService(config JWTConfig) (*EdDSAJWTService, error) {
+	var privateKey ed25519.PrivateKey
+
+	if config.PrivateKeyPEM != "" && config.PublicKeyPEM != "" {
+		signer, _, err := parseKeyPairPEM(config.PrivateKeyPEM, config.PublicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if privateKey, ok = signer.(ed25519.PrivateKey); !ok {
+			return nil, errors.NewInternalError("Private key is not an Ed25519 key", nil)
+		}
+	} else if config.PrivateKeyPEM != "" || config.PublicKeyPEM != "" {
+		return nil, errors.NewInternalError("Both private and public key are required", nil)
+	} else {
+		// Generate an in-memory key pair; tokens won't survive a restart
+		var err error
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to generate Ed25519 key pair", err)
+		}
+	}
+
+	service, err := newJWTService(config, privateKey, privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return &EdDSAJWTService{jwtService: service}, nil
+}
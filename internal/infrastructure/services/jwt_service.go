@@ -0,0 +1,522 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// JWT signing algorithms supported by the service
+const (
+	AlgorithmES256 = "ES256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// jwtService holds the algorithm-independent part of the JWT services: signing with
+// the current key and verifying with any key in the keyset, selected by kid.
+// The keyset may mix algorithms, so tokens from a previous algorithm keep verifying.
+type jwtService struct {
+	mu              sync.RWMutex
+	keys            *signingKeys
+	extraKeys       map[string]crypto.PublicKey // verification keys from config, kept across reloads
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+	issuer          string
+}
+
+// signingKeys is an immutable snapshot of the keys in use, swapped as a whole on reload
+type signingKeys struct {
+	privateKey crypto.Signer
+	publicKey  crypto.PublicKey
+	method     jwt.SigningMethod
+	keyID      string
+	keySet     map[string]crypto.PublicKey // kid -> key, includes the signing key
+}
+
+// newJWTService creates the shared part of a JWT service for a key pair
+func newJWTService(config JWTConfig, privateKey crypto.Signer, publicKey crypto.PublicKey) (*jwtService, error) {
+	keys, err := newSigningKeys(privateKey, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	extraKeys := make(map[string]crypto.PublicKey)
+	for _, keyPEM := range config.VerificationKeysPEM {
+		key, err := parseAnyPublicKeyPEM(keyPEM)
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to parse verification key", err)
+		}
+		kid, err := publicKeyID(key)
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to compute key ID", err)
+		}
+		extraKeys[kid] = key
+		keys.keySet[kid] = key
+	}
+	// The signing key wins if it is also listed as a verification key
+	keys.keySet[keys.keyID] = publicKey
+
+	return &jwtService{
+		keys:            keys,
+		extraKeys:       extraKeys,
+		accessTokenTTL:  config.AccessTokenTTL,
+		refreshTokenTTL: config.RefreshTokenTTL,
+		issuer:          config.Issuer,
+	}, nil
+}
+
+// newSigningKeys creates a key snapshot that signs with privateKey
+func newSigningKeys(privateKey crypto.Signer, publicKey crypto.PublicKey) (*signingKeys, error) {
+	method, err := signingMethodForKey(publicKey)
+	if err != nil {
+		return nil, errors.NewInternalError("Unsupported signing key", err)
+	}
+
+	keyID, err := publicKeyID(publicKey)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to compute key ID", err)
+	}
+
+	return &signingKeys{
+		privateKey: privateKey,
+		publicKey:  publicKey,
+		method:     method,
+		keyID:      keyID,
+		keySet:     map[string]crypto.PublicKey{keyID: publicKey},
+	}, nil
+}
+
+// CustomClaims wraps JWTClaims for jwt library compatibility
+type CustomClaims struct {
+	// Standard JWT claims with explicit JSON tags
+	Subject   string `json:"sub"`
+	Issuer    string `json:"iss"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	ID        string `json:"jti"`
+	// Audience is a string or an array of strings on the wire
+	Audience jwt.ClaimStrings `json:"aud,omitempty"`
+	// Custom claims
+	ClientID  string   `json:"client_id"`
+	SessionID string   `json:"sid,omitempty"`
+	Scopes    []string `json:"scopes"`
+}
+
+// Implement jwt.Claims interface methods
+func (c CustomClaims) GetExpirationTime() (*jwt.NumericDate, error) {
+	if c.ExpiresAt == 0 {
+		return nil, nil
+	}
+	return jwt.NewNumericDate(time.Unix(c.ExpiresAt, 0)), nil
+}
+
+func (c CustomClaims) GetIssuedAt() (*jwt.NumericDate, error) {
+	if c.IssuedAt == 0 {
+		return nil, nil
+	}
+	return jwt.NewNumericDate(time.Unix(c.IssuedAt, 0)), nil
+}
+
+func (c CustomClaims) GetNotBefore() (*jwt.NumericDate, error) {
+	return nil, nil
+}
+
+func (c CustomClaims) GetIssuer() (string, error) {
+	return c.Issuer, nil
+}
+
+func (c CustomClaims) GetSubject() (string, error) {
+	return c.Subject, nil
+}
+
+func (c CustomClaims) GetAudience() (jwt.ClaimStrings, error) {
+	return c.Audience, nil
+}
+
+// GenerateToken generates a JWT token from claims
+func (j *jwtService) GenerateToken(claims *services.JWTClaims) (string, error) {
+	// Debug logging - function entry
+	fmt.Printf("[DEBUG] GenerateToken called\n")
+	fmt.Println("[DEBUG] JWT Claims - Subject:", claims.Subject, "Issuer:", claims.Issuer, "IssuedAt:", claims.IssuedAt, "ExpiresAt:", claims.ExpiresAt, "TokenID:", claims.TokenID, "ClientID:", claims.ClientID, "Scopes:", claims.Scopes)
+
+	// Create custom claims for jwt library
+	customClaims := &CustomClaims{
+		Subject:   claims.Subject,
+		Issuer:    claims.Issuer,
+		IssuedAt:  claims.IssuedAt,
+		ExpiresAt: claims.ExpiresAt,
+		ID:        claims.TokenID,
+		Audience:  claims.Audience,
+		ClientID:  claims.ClientID,
+		SessionID: claims.SessionID,
+		Scopes:    claims.Scopes,
+	}
+
+	// Debug logging for custom claims
+	fmt.Printf("[DEBUG] Custom Claims - Subject: %s, Issuer: %s, IssuedAt: %d, ExpiresAt: %d, ID: %s, ClientID: %s, Scopes: %v\n",
+		customClaims.Subject, customClaims.Issuer, customClaims.IssuedAt, customClaims.ExpiresAt, customClaims.ID, customClaims.ClientID, customClaims.Scopes)
+
+	keys := j.current()
+	token := jwt.NewWithClaims(keys.method, customClaims)
+	token.Header["kid"] = keys.keyID
+	tokenString, err := token.SignedString(keys.privateKey)
+	if err != nil {
+		return "", errors.NewInternalError("Failed to sign JWT token", err)
+	}
+
+	return tokenString, nil
+}
+
+// VerifyToken verifies and parses a JWT token, returning the claims
+func (j *jwtService) VerifyToken(tokenString string, opts ...services.VerifyOption) (*services.JWTClaims, error) {
+	options := services.NewVerifyOptions(opts...)
+	var parserOptions []jwt.ParserOption
+	if options.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(options.Issuer))
+	}
+	if options.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(options.Audience))
+	}
+
+	keys := j.current()
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
+		key := keys.publicKey
+		if kid, _ := token.Header["kid"].(string); kid != "" {
+			var ok bool
+			if key, ok = keys.keySet[kid]; !ok {
+				return nil, fmt.Errorf("unknown key id: %s", kid)
+			}
+		}
+		// Tokens without kid were issued before it was stamped and are signed by the current key
+
+		// The algorithm is pinned by the key, never taken from the token alone
+		method, err := signingMethodForKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return key, nil
+	}, parserOptions...)
+
+	if err != nil {
+		return nil, errors.NewUnauthorizedError("Invalid token", err)
+	}
+
+	if claims, ok := token.Claims.(*CustomClaims); ok && token.Valid {
+		// Convert CustomClaims back to JWTClaims
+		jwtClaims := &services.JWTClaims{
+			Subject:   claims.Subject,
+			Issuer:    claims.Issuer,
+			IssuedAt:  claims.IssuedAt,
+			ExpiresAt: claims.ExpiresAt,
+			TokenID:   claims.ID,
+			Audience:  claims.Audience,
+			ClientID:  claims.ClientID,
+			SessionID: claims.SessionID,
+			Scopes:    claims.Scopes,
+		}
+		return jwtClaims, nil
+	}
+
+	return nil, errors.NewUnauthorizedError("Invalid token claims", nil)
+}
+
+// GenerateIDToken signs an OpenID Connect ID token with the current signing key, so
+// relying parties verify it against the same JWKS as access tokens
+func (j *jwtService) GenerateIDToken(claims *services.IDTokenClaims) (string, error) {
+	idClaims := jwt.MapClaims{
+		"iss":       claims.Issuer,
+		"sub":       claims.Subject,
+		"aud":       jwt.ClaimStrings(claims.Audience),
+		"iat":       claims.IssuedAt,
+		"exp":       claims.ExpiresAt,
+		"auth_time": claims.AuthTime,
+	}
+	if claims.Nonce != "" {
+		idClaims["nonce"] = claims.Nonce
+	}
+	if claims.PhoneNumber != "" {
+		idClaims["phone_number"] = claims.PhoneNumber
+		idClaims["phone_number_verified"] = claims.PhoneNumberVerified
+	}
+
+	keys := j.current()
+	token := jwt.NewWithClaims(keys.method, idClaims)
+	token.Header["kid"] = keys.keyID
+	tokenString, err := token.SignedString(keys.privateKey)
+	if err != nil {
+		return "", errors.NewInternalError("Failed to sign ID token", err)
+	}
+
+	return tokenString, nil
+}
+
+// KeyID returns the kid stamped on tokens signed by this service
+func (j *jwtService) KeyID() string {
+	return j.current().keyID
+}
+
+// KeySet returns the public keys accepted for verification, signing key first
+func (j *jwtService) KeySet() (*services.JSONWebKeySet, error) {
+	keys := j.current()
+	kids := make([]string, 0, len(keys.keySet))
+	for kid := range keys.keySet {
+		if kid != keys.keyID {
+			kids = append(kids, kid)
+		}
+	}
+	sort.Strings(kids)
+	kids = append([]string{keys.keyID}, kids...)
+
+	keySet := &services.JSONWebKeySet{Keys: make([]services.JSONWebKey, 0, len(kids))}
+	for _, kid := range kids {
+		jwk, err := publicKeyJWK(keys.keySet[kid], kid)
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to encode public key", err)
+		}
+		keySet.Keys = append(keySet.Keys, *jwk)
+	}
+
+	return keySet, nil
+}
+
+// LoadSigningKeys replaces the keys in use with the signing key ring: the active
+// key signs, and every key (plus configured verification keys) verifies.
+// Keys of any supported algorithm may be mixed in the ring.
+func (j *jwtService) LoadSigningKeys(ring []*entities.SigningKey) error {
+	keySet := make(map[string]crypto.PublicKey, len(ring)+len(j.extraKeys))
+	for kid, key := range j.extraKeys {
+		keySet[kid] = key
+	}
+
+	var keys *signingKeys
+	for _, signingKey := range ring {
+		publicKey, err := parseAnyPublicKeyPEM(signingKey.PublicKeyPEM)
+		if err != nil {
+			return errors.NewInternalError(fmt.Sprintf("Failed to parse public key %s", signingKey.ID), err)
+		}
+		keySet[signingKey.ID] = publicKey
+
+		if signingKey.Status != entities.SigningKeyStatusActive {
+			continue
+		}
+		privateKey, err := parseAnyPrivateKeyPEM(signingKey.PrivateKeyPEM)
+		if err != nil {
+			return errors.NewInternalError(fmt.Sprintf("Failed to parse private key %s", signingKey.ID), err)
+		}
+		if keys, err = newSigningKeys(privateKey, publicKey); err != nil {
+			return err
+		}
+		keys.keyID = signingKey.ID
+	}
+
+	if keys == nil {
+		return errors.NewInternalError("Signing key ring has no active key", nil)
+	}
+	keys.keySet = keySet
+
+	j.mu.Lock()
+	j.keys = keys
+	j.mu.Unlock()
+
+	return nil
+}
+
+// current returns the keys in use
+func (j *jwtService) current() *signingKeys {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.keys
+}
+
+// signingMethodForKey returns the only algorithm a key may be used with
+func signingMethodForKey(key crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return jwt.SigningMethodES256, nil
+		case elliptic.P384():
+			return jwt.SigningMethodES384, nil
+		case elliptic.P521():
+			return jwt.SigningMethodES512, nil
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Curve.Params().Name)
+		}
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+// publicKeyJWK encodes a public key as a JWK
+func publicKeyJWK(key crypto.PublicKey, kid string) (*services.JSONWebKey, error) {
+	method, err := signingMethodForKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	jwk := &services.JSONWebKey{
+		Use: "sig",
+		Alg: method.Alg(),
+		Kid: kid,
+	}
+
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		ecdhKey, err := k.ECDH()
+		if err != nil {
+			return nil, err
+		}
+		// Uncompressed point: 0x04 || X || Y, both coordinates zero-padded
+		point := ecdhKey.Bytes()
+		size := (len(point) - 1) / 2
+
+		jwk.Kty = "EC"
+		jwk.Crv = k.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(point[1 : 1+size])
+		jwk.Y = base64.RawURLEncoding.EncodeToString(point[1+size:])
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k)
+	}
+
+	return jwk, nil
+}
+
+// publicKeyID derives a stable kid from the RFC 7638 JWK thumbprint of the key
+func publicKeyID(key crypto.PublicKey) (string, error) {
+	jwk, err := publicKeyJWK(key, "")
+	if err != nil {
+		return "", err
+	}
+
+	// Required members only, in lexicographic order
+	var thumbprintInput string
+	switch jwk.Kty {
+	case "EC":
+		thumbprintInput = fmt.Sprintf(`{"crv":"%s","kty":"EC","x":"%s","y":"%s"}`, jwk.Crv, jwk.X, jwk.Y)
+	case "RSA":
+		thumbprintInput = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N)
+	case "OKP":
+		thumbprintInput = fmt.Sprintf(`{"crv":"%s","kty":"OKP","x":"%s"}`, jwk.Crv, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(thumbprintInput))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// parseAnyPrivateKeyPEM parses an EC (SEC 1), RSA (PKCS #1) or PKCS #8 private key
+func parseAnyPrivateKeyPEM(pemStr string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(pemStr))
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse PEM block containing the key")
+	}
+
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+// parseAnyPublicKeyPEM parses a PKIX public key of any supported type
+func parseAnyPublicKeyPEM(pemStr string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(pemStr))
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse PEM block containing the key")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := signingMethodForKey(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// parseKeyPairPEM parses a configured key pair and checks both halves belong together
+func parseKeyPairPEM(privateKeyPEM, publicKeyPEM string) (crypto.Signer, crypto.PublicKey, error) {
+	privateKey, err := parseAnyPrivateKeyPEM(privateKeyPEM)
+	if err != nil {
+		return nil, nil, errors.NewInternalError("Failed to parse private key", err)
+	}
+
+	publicKey, err := parseAnyPublicKeyPEM(publicKeyPEM)
+	if err != nil {
+		return nil, nil, errors.NewInternalError("Failed to parse public key", err)
+	}
+
+	if !publicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(privateKey.Public()) {
+		return nil, nil, errors.NewInternalError("Public key does not match private key", nil)
+	}
+
+	return privateKey, publicKey, nil
+}
+
+// encodeKeyPairPEM encodes a generated key pair; EC keys keep the SEC 1 format
+// this service has always written, everything else uses PKCS #8
+func encodeKeyPairPEM(privateKey crypto.Signer) (privateKeyPEM, publicKeyPEM string, err error) {
+	var privateBlock *pem.Block
+	if ecKey, ok := privateKey.(*ecdsa.PrivateKey); ok {
+		der, err := x509.MarshalECPrivateKey(ecKey)
+		if err != nil {
+			return "", "", errors.NewInternalError("Failed to marshal private key", err)
+		}
+		privateBlock = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	} else {
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return "", "", errors.NewInternalError("Failed to marshal private key", err)
+		}
+		privateBlock = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return "", "", errors.NewInternalError("Failed to marshal public key", err)
+	}
+
+	return string(pem.EncodeToMemory(privateBlock)),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})), nil
+}
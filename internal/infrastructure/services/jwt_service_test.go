@@ -0,0 +1,172 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/otp-auth/internal/application/ports/services"
+)
+
+func TestJWTServiceAlgorithms(t *testing.T) {
+	config := JWTConfig{AccessTokenTTL: time.Minute, Issuer: "test"}
+
+	rsaService, err := NewRSAJWTService(config)
+	if err != nil {
+		t.Fatalf("NewRSAJWTService: %v", err)
+	}
+	edService, err := NewEdDSAJWTService(config)
+	if err != nil {
+		t.Fatalf("NewEdDSAJWTService: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		service *jwtService
+		alg     string
+		kty     string
+	}{
+		{"RS256", rsaService.jwtService, "RS256", "RSA"},
+		{"EdDSA", edService.jwtService, "EdDSA", "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenString, err := tt.service.GenerateToken(testClaims())
+			if err != nil {
+				t.Fatalf("GenerateToken: %v", err)
+			}
+
+			token, _, err := jwt.NewParser().ParseUnverified(tokenString, &CustomClaims{})
+			if err != nil {
+				t.Fatalf("ParseUnverified: %v", err)
+			}
+			if token.Header["alg"] != tt.alg {
+				t.Errorf("alg = %v, want %s", token.Header["alg"], tt.alg)
+			}
+
+			claims, err := tt.service.VerifyToken(tokenString)
+			if err != nil {
+				t.Errorf("VerifyToken: %v", err)
+			} else if claims.SessionID != "session-1" {
+				t.Errorf("sid = %q, want the session of the token", claims.SessionID)
+			}
+
+			keySet, err := tt.service.KeySet()
+			if err != nil {
+				t.Fatalf("KeySet: %v", err)
+			}
+			if key := keySet.Keys[0]; key.Alg != tt.alg || key.Kty != tt.kty {
+				t.Errorf("JWK alg/kty = %s/%s, want %s/%s", key.Alg, key.Kty, tt.alg, tt.kty)
+			}
+		})
+	}
+}
+
+func TestJWTServiceMixedKeySet(t *testing.T) {
+	// Migrating from RS256 to ES256: the old RSA public key stays in the keyset
+	rsaService, err := NewRSAJWTService(JWTConfig{AccessTokenTTL: time.Minute})
+	if err != nil {
+		t.Fatalf("NewRSAJWTService: %v", err)
+	}
+	_, rsaPublicPEM, err := encodeKeyPairPEM(rsaService.current().privateKey)
+	if err != nil {
+		t.Fatalf("encodeKeyPairPEM: %v", err)
+	}
+	ecService := newTestJWTService(t, rsaPublicPEM)
+
+	if _, err := ecService.VerifyToken(mustToken(t, &ECDSAJWTService{jwtService: rsaService.jwtService})); err != nil {
+		t.Errorf("RS256 token rejected by mixed keyset: %v", err)
+	}
+
+	keySet, err := ecService.KeySet()
+	if err != nil {
+		t.Fatalf("KeySet: %v", err)
+	}
+	if len(keySet.Keys) != 2 || keySet.Keys[0].Kty != "EC" || keySet.Keys[1].Kty != "RSA" {
+		t.Errorf("unexpected keyset: %+v", keySet.Keys)
+	}
+
+	// A token claiming a different algorithm than its key must be rejected
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &CustomClaims{Subject: "user-1", ExpiresAt: time.Now().Add(time.Minute).Unix()})
+	forged.Header["kid"] = rsaService.KeyID()
+	forgedString, err := forged.SignedString([]byte(rsaPublicPEM))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	if _, err := ecService.VerifyToken(forgedString); err == nil {
+		t.Error("token with mismatched algorithm accepted")
+	}
+}
+
+func TestJWTServiceVerifyOptions(t *testing.T) {
+	svc := newTestJWTService(t)
+	claims := testClaims()
+	claims.Audience = []string{"orders-api", "otp-auth"}
+	tokenString, err := svc.GenerateToken(claims)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []services.VerifyOption
+		wantErr bool
+	}{
+		{"no options", nil, false},
+		{"matching issuer and audience", []services.VerifyOption{services.WithIssuer("test"), services.WithAudience("orders-api")}, false},
+		{"other issuer", []services.VerifyOption{services.WithIssuer("someone-else")}, true},
+		{"other audience", []services.VerifyOption{services.WithAudience("billing-api")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verified, err := svc.VerifyToken(tokenString, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyToken error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(verified.Audience) != 2 {
+				t.Errorf("Audience = %v, want 2 entries", verified.Audience)
+			}
+		})
+	}
+}
+
+func TestJWTServiceGenerateIDToken(t *testing.T) {
+	svc := newTestJWTService(t)
+	now := time.Now()
+	tokenString, err := svc.GenerateIDToken(&services.IDTokenClaims{
+		Issuer:              "https://auth.example.com",
+		Subject:             "user-1",
+		Audience:            []string{"web-app"},
+		IssuedAt:            now.Unix(),
+		ExpiresAt:           now.Add(time.Hour).Unix(),
+		AuthTime:            now.Unix(),
+		Nonce:               "n-0S6",
+		PhoneNumber:         "+989121234567",
+		PhoneNumberVerified: true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateIDToken: %v", err)
+	}
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.NewParser(jwt.WithIssuer("https://auth.example.com"), jwt.WithAudience("web-app")).
+		ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+			return svc.current().publicKey, nil
+		})
+	if err != nil {
+		t.Fatalf("parse ID token: %v", err)
+	}
+	if token.Header["kid"] != svc.KeyID() {
+		t.Errorf("kid = %v, want %s", token.Header["kid"], svc.KeyID())
+	}
+	if claims["nonce"] != "n-0S6" || claims["phone_number"] != "+989121234567" || claims["phone_number_verified"] != true {
+		t.Errorf("claims = %v", claims)
+	}
+
+	// Access token verification pinned to the API issuer must reject it
+	if _, err := svc.VerifyToken(tokenString, services.WithIssuer("test")); err == nil {
+		t.Error("ID token accepted as an access token")
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/infrastructure/metrics"
+)
+
+var securityEventsTotal = metrics.NewCounter(
+	"otp_auth_security_events_total",
+	"Number of security events raised, e.g. refresh token reuse",
+)
+
+// LogSecurityEventPublisher writes security events to the log and counts them
+type LogSecurityEventPublisher struct{}
+
+// NewLogSecurityEventPublisher creates a new LogSecurityEventPublisher
+func NewLogSecurityEventPublisher() services.SecurityEventPublisher {
+	return &LogSecurityEventPublisher{}
+}
+
+// Publish logs the event with a [SECURITY] prefix
+func (p *LogSecurityEventPublisher) Publish(ctx context.Context, event services.SecurityEvent) error {
+	securityEventsTotal.Inc()
+
+	keys := make([]string, 0, len(event.Details))
+	for key := range event.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var details strings.Builder
+	for _, key := range keys {
+		details.WriteString(" ")
+		details.WriteString(key)
+		details.WriteString("=")
+		details.WriteString(event.Details[key])
+	}
+
+	log.Printf("[SECURITY] event=%s user_id=%s session_id=%s client_id=%s at=%s%s",
+		event.Type, event.UserID, event.SessionID, event.ClientID,
+		event.OccurredAt.UTC().Format(time.RFC3339), details.String())
+	return nil
+}
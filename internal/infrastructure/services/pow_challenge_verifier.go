@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/otp-auth/internal/application/ports/repositories"
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// PoWChallengeVerifier implements ChallengeVerifier with a hashcash-style proof of work.
+// Challenges are stateless HMAC-signed tokens; the client must find a nonce such that
+// SHA-256("<token>:<nonce>") starts with the required number of zero bits.
+type PoWChallengeVerifier struct {
+	secret     []byte
+	difficulty int
+	ttl        time.Duration
+	store      repositories.ChallengeRepository
+}
+
+// PoWConfig holds configuration for the proof-of-work challenge verifier
+type PoWConfig struct {
+	Secret     string        // HMAC secret; must be shared by all replicas
+	Difficulty int           // Required leading zero bits
+	TTL        time.Duration // How long an issued challenge stays valid
+}
+
+// defaultPoWConfig returns default proof-of-work configuration
+func defaultPoWConfig() PoWConfig {
+	return PoWConfig{
+		Difficulty: 20,
+		TTL:        2 * time.Minute,
+	}
+}
+
+// NewPoWChallengeVerifier creates a new proof-of-work challenge verifier
+func NewPoWChallengeVerifier(config PoWConfig, store repositories.ChallengeRepository) (*PoWChallengeVerifier, error) {
+	if config.Secret == "" {
+		return nil, errors.NewValidationError("Proof-of-work secret is required", nil)
+	}
+
+	if config.Difficulty < 1 || config.Difficulty > 32 {
+		return nil, errors.NewValidationError("Proof-of-work difficulty must be between 1 and 32", nil)
+	}
+
+	if config.TTL <= 0 {
+		config.TTL = defaultPoWConfig().TTL
+	}
+
+	return &PoWChallengeVerifier{
+		secret:     []byte(config.Secret),
+		difficulty: config.Difficulty,
+		ttl:        config.TTL,
+		store:      store,
+	}, nil
+}
+
+// Type returns the challenge type handled by this verifier
+func (v *PoWChallengeVerifier) Type() string {
+	return services.ChallengeTypeProofOfWork
+}
+
+// Issue creates a new signed proof-of-work challenge
+func (v *PoWChallengeVerifier) Issue(ctx context.Context) (*services.Challenge, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return nil, errors.NewInternalError("Failed to generate challenge", err)
+	}
+
+	expiresAt := time.Now().Add(v.ttl)
+	payload := fmt.Sprintf("%d:%d:%s", v.difficulty, expiresAt.Unix(), hex.EncodeToString(random))
+	token := base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(v.sign(payload))
+
+	return &services.Challenge{
+		Type:       services.ChallengeTypeProofOfWork,
+		Token:      token,
+		Difficulty: v.difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// Verify checks the signature, expiry and work of a proof-of-work solution
+func (v *PoWChallengeVerifier) Verify(ctx context.Context, solution *services.ChallengeSolution, remoteIP string) error {
+	if solution == nil || solution.Token == "" || solution.Nonce == "" {
+		return errors.NewValidationError("Challenge token and nonce are required", nil)
+	}
+
+	parts := strings.Split(solution.Token, ".")
+	if len(parts) != 2 {
+		return errors.NewValidationError("Malformed challenge token", nil)
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return errors.NewValidationError("Malformed challenge token", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return errors.NewValidationError("Malformed challenge token", err)
+	}
+
+	payload := string(payloadBytes)
+	if !hmac.Equal(signature, v.sign(payload)) {
+		return errors.NewValidationError("Invalid challenge signature", nil)
+	}
+
+	fields := strings.Split(payload, ":")
+	if len(fields) != 3 {
+		return errors.NewValidationError("Malformed challenge token", nil)
+	}
+	difficulty, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return errors.NewValidationError("Malformed challenge token", err)
+	}
+	expiresAt, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return errors.NewValidationError("Malformed challenge token", err)
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return errors.NewValidationError("Challenge has expired", nil)
+	}
+
+	if leadingZeroBits(sha256.Sum256([]byte(solution.Token+":"+solution.Nonce))) < difficulty {
+		return errors.NewValidationError("Insufficient proof of work", nil)
+	}
+
+	// Each challenge may only be redeemed once
+	if v.store != nil {
+		fresh, err := v.store.MarkUsed(ctx, fields[2], time.Until(time.Unix(expiresAt, 0))+time.Second)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return errors.NewValidationError("Challenge has already been used", nil)
+		}
+	}
+
+	return nil
+}
+
+// sign computes the HMAC of a challenge payload
+func (v *PoWChallengeVerifier) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// leadingZeroBits counts the leading zero bits of a hash
+func leadingZeroBits(hash [sha256.Size]byte) int {
+	count := 0
+	for _, b := range hash {
+		if b != 0 {
+			return count + bits.LeadingZeros8(b)
+		}
+		count += 8
+	}
+	return count
+}
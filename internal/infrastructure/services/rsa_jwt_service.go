@@ -0,0 +1,53 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+
+	"github.com/otp-auth/pkg/errors"
+)
+
+// rsaKeyBits is the modulus size of generated RSA keys
+const rsaKeyBits = 2048
+
+// RSAJWTService implements JWTService using RS256, for consumers without ECDSA support
+type RSAJWTService struct {
+	*jwtService
+}
+
+// NewRSAJWTService creates a new RS256 JWT service
+func NewRSAJWTService(config JWTConfig) (*RSAJWTService, error) {
+	var privateKey *rsa.PrivateKey
+
+	if config.PrivateKeyPEM != "" && config.PublicKeyPEM != "" {
+		signer, _, err := parseKeyPairPEM(config.PrivateKeyPEM, config.PublicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if privateKey, ok = signer.(*rsa.PrivateKey); !ok {
+			return nil, errors.NewInternalError("Private key is not an RSA key", nil)
+		}
+	} else if config.PrivateKeyPEM != "" || config.PublicKeyPEM != "" {
+		return nil, errors.NewInternalError("Both private and public key are required", nil)
+	} else {
+		// Generate an in-memory key pair; tokens won't survive a restart
+		var err error
+		privateKey, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, errors.NewInternalError("Failed to generate RSA key pair", err)
+		}
+	}
+
+	if privateKey.N.BitLen() < rsaKeyBits {
+		return nil, errors.NewInternalError("RSA key must be at least 2048 bits", nil)
+	}
+
+	service, err := newJWTService(config, privateKey, &privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RSAJWTService{jwtService: service}, nil
+}
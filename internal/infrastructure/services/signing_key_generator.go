@@ -0,0 +1,113 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+
+	"github.com/otp-auth/internal/application/ports/services"
+	"github.com/otp-auth/internal/domain/entities"
+	"github.com/otp-auth/pkg/errors"
+)
+
+// ECDSAKeyGenerator generates P-256 signing keys for the key ring
+type ECDSAKeyGenerator struct{}
+
+// NewECDSAKeyGenerator creates a new ECDSA key generator
+func NewECDSAKeyGenerator() *ECDSAKeyGenerator {
+	return &ECDSAKeyGenerator{}
+}
+
+// GenerateSigningKey generates a new ES256 key pair
+func (g *ECDSAKeyGenerator) GenerateSigningKey() (*entities.SigningKey, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate ECDSA key pair", err)
+	}
+	return newSigningKey(privateKey)
+}
+
+// RSAKeyGenerator generates 2048-bit RSA signing keys for the key ring
+type RSAKeyGenerator struct{}
+
+// NewRSAKeyGenerator creates a new RSA key generator
+func NewRSAKeyGenerator() *RSAKeyGenerator {
+	return &RSAKeyGenerator{}
+}
+
+// GenerateSigningKey generates a new RS256 key pair
+func (g *RSAKeyGenerator) GenerateSigningKey() (*entities.SigningKey, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate RSA key pair", err)
+	}
+	return newSigningKey(privateKey)
+}
+
+// EdDSAKeyGenerator generates Ed25519 signing keys for the key ring
+type EdDSAKeyGenerator struct{}
+
+// NewEdDSAKeyGenerator creates a new EdDSA key generator
+func NewEdDSAKeyGenerator() *EdDSAKeyGenerator {
+	return &EdDSAKeyGenerator{}
+}
+
+// GenerateSigningKey generates a new EdDSA key pair
+func (g *EdDSAKeyGenerator) GenerateSigningKey() (*entities.SigningKey, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to generate Ed25519 key pair", err)
+	}
+	return newSigningKey(privateKey)
+}
+
+// GenerateECDSAKeyPairPEM generates a new P-256 key pair in PEM format
+func GenerateECDSAKeyPairPEM() (privateKeyPEM, publicKeyPEM string, err error) {
+	return generateKeyPairPEM(NewECDSAKeyGenerator())
+}
+
+// GenerateRSAKeyPairPEM generates a new RSA key pair in PEM format
+func GenerateRSAKeyPairPEM() (privateKeyPEM, publicKeyPEM string, err error) {
+	return generateKeyPairPEM(NewRSAKeyGenerator())
+}
+
+// GenerateEdDSAKeyPairPEM generates a new Ed25519 key pair in PEM format
+func GenerateEdDSAKeyPairPEM() (privateKeyPEM, publicKeyPEM string, err error) {
+	return generateKeyPairPEM(NewEdDSAKeyGenerator())
+}
+
+func generateKeyPairPEM(generator services.SigningKeyGenerator) (string, string, error) {
+	key, err := generator.GenerateSigningKey()
+	if err != nil {
+		return "", "", err
+	}
+	return key.PrivateKeyPEM, key.PublicKeyPEM, nil
+}
+
+// newSigningKey wraps a generated private key in a key ring entry
+func newSigningKey(privateKey crypto.Signer) (*entities.SigningKey, error) {
+	method, err := signingMethodForKey(privateKey.Public())
+	if err != nil {
+		return nil, errors.NewInternalError("Unsupported signing key", err)
+	}
+
+	keyID, err := publicKeyID(privateKey.Public())
+	if err != nil {
+		return nil, errors.NewInternalError("Failed to compute key ID", err)
+	}
+
+	privateKeyPEM, publicKeyPEM, err := encodeKeyPairPEM(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.SigningKey{
+		ID:            keyID,
+		Algorithm:     method.Alg(),
+		PrivateKeyPEM: privateKeyPEM,
+		PublicKeyPEM:  publicKeyPEM,
+	}, nil
+}